@@ -27,7 +27,7 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Bearer token for authenticated requests
+// @description "Bearer <access token>" or "Bearer <API key>"; API keys are also accepted in the X-API-Key header
 func main() {
 	logger, err := logger.NewLogger("logs/app.log")
 
@@ -49,9 +49,17 @@ func main() {
 		logger.Fatal("Не удалось проверить подключение к базе данных", zap.Error(err))
 	}
 	logger.Info("Успешное подключение к базе данных")
+	// прежние HS256-токены принимаются только для доступа и обновления:
+	// приглашения и шаги второго фактора проверяются лишь ключами Ed25519
+	jwtKeys, err := usecase.LoadJWTKeys(config.Envs.JWT_KEYS_DIR, config.Envs.JWT_GENERATE_KEYS, map[string]string{
+		usecase.TokenAccess:  config.Envs.JWTSecretAccess,
+		usecase.TokenRefresh: config.Envs.JWTSecretRefresh,
+	})
+	if err != nil {
+		logger.Fatal("Ошибка загрузки ключей JWT", zap.Error(err))
+	}
 	jwtService := usecase.NewJWTService(
-		config.Envs.JWTSecretAccess,
-		config.Envs.JWTSecretRefresh,
+		jwtKeys,
 		config.Envs.JWTAccessTTL,
 		config.Envs.JWTRefreshTTL,
 	)
@@ -64,7 +72,7 @@ func main() {
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   []string{config.Envs.FRONT_URI}, // Укажите разрешённые домены
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "X-API-Key"},
 		AllowCredentials: true, // Разрешить отправку куки и заголовков авторизации
 		MaxAge:           300,  // Кэширование CORS-запросов (в секундах)
 	})
@@ -15,9 +15,49 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns all API keys, including revoked and expired ones. The keys themselves are never returned, only their first characters. Requires apikey:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Authenticates a user and returns access and refresh tokens",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a key for an integration. The key is returned only in this response; send it as the X-API-Key header or as \"Authorization: Bearer \u003ckey\u003e\". Requests with the key act as a SERVICE principal with exactly the listed permissions. apikey:manage cannot be granted to a key. Requires apikey:manage.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,45 +65,103 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "api-keys"
                 ],
-                "summary": "User login",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
+                        "description": "Name, permissions and expiry",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.LoginRequest"
+                            "$ref": "#/definitions/apikey.CreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User successfully authenticated",
+                    "201": {
+                        "description": "Key created",
                         "schema": {
-                            "$ref": "#/definitions/auth.LoginResponse"
+                            "$ref": "#/definitions/apikey.CreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid JSON, validation error, unknown permission or expiry in the past",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apikey.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+                            "$ref": "#/definitions/apikey.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/logout": {
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a key; requests with it are rejected immediately. Requires apikey:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key revoked",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.RevokeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found or already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
             "post": {
-                "description": "Logs out a user and clears the refresh token cookie",
+                "description": "Authenticates a user and returns access and refresh tokens. If the user has two-factor authentication enabled (or it is mandatory for the role but not set up yet), returns an MFA token and the next step instead: \"verify\" — finish with /auth/login/mfa, \"setup\" — enroll with /auth/login/mfa/setup and /auth/login/mfa/confirm. The MFA token lives 5 minutes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -73,25 +171,55 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "User logout",
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "User successfully logged out",
+                        "description": "User successfully authenticated",
                         "schema": {
-                            "$ref": "#/definitions/auth.LogoutResponse"
+                            "$ref": "#/definitions/auth.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Second factor required",
+                        "schema": {
+                            "$ref": "#/definitions/mfa.ChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts or account temporarily locked; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/online": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of user IDs who are currently online",
+        "/auth/login/mfa": {
+            "post": {
+                "description": "Exchanges the MFA token from /auth/login (step \"verify\") and a code from the authenticator app or a one-time recovery code for access and refresh tokens.",
                 "consumes": [
                     "application/json"
                 ],
@@ -101,16 +229,39 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "List online users",
+                "summary": "Finish login with a 2FA code",
+                "parameters": [
+                    {
+                        "description": "MFA token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/mfa.ChallengeRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "List of online user IDs",
+                        "description": "User successfully authenticated",
                         "schema": {
-                            "$ref": "#/definitions/auth.OnlineListResponse"
+                            "$ref": "#/definitions/auth.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Expired MFA token",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
                         "schema": {
                             "$ref": "#/definitions/auth.ErrorResponse"
                         }
@@ -124,9 +275,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
+        "/auth/login/mfa/confirm": {
             "post": {
-                "description": "Refreshes access token using refresh token stored in cookie",
+                "description": "Confirms the enrollment started with /auth/login/mfa/setup by the first code from the app, enables 2FA and logs in. Recovery codes are returned once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -136,16 +287,39 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Refresh access token",
+                "summary": "Confirm 2FA enrollment and finish login",
+                "parameters": [
+                    {
+                        "description": "MFA token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/mfa.ChallengeRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Token successfully refreshed",
+                        "description": "Access token and recovery codes",
                         "schema": {
-                            "$ref": "#/definitions/auth.RefreshResponse"
+                            "$ref": "#/definitions/mfa.LoginConfirmResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, wrong code or enrollment not started",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid or missing refresh token",
+                        "description": "Expired MFA token",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
                         "schema": {
                             "$ref": "#/definitions/auth.ErrorResponse"
                         }
@@ -159,9 +333,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/auth/login/mfa/setup": {
             "post": {
-                "description": "Registers a new user with email and password",
+                "description": "For accounts that must use 2FA but have not set it up (step \"setup\" from /auth/login): returns a new TOTP secret and otpauth URI for the authenticator app.",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,23 +345,23 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register a new user",
+                "summary": "Start mandatory 2FA enrollment during login",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
+                        "description": "MFA token",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterRequest"
+                            "$ref": "#/definitions/mfa.ChallengeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User successfully registered",
+                    "200": {
+                        "description": "Secret and otpauth URI",
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterResponse"
+                            "$ref": "#/definitions/mfa.EnrollResponse"
                         }
                     },
                     "400": {
@@ -195,18 +369,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/auth.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Expired MFA token",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/cargo": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of all cargos in the system",
+        "/auth/logout": {
+            "post": {
+                "description": "Logs out a user: revokes the refresh token family server-side and clears the refresh token cookie",
                 "consumes": [
                     "application/json"
                 ],
@@ -214,143 +401,80 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cargo"
+                    "auth"
                 ],
-                "summary": "List all cargos",
+                "summary": "User logout",
                 "responses": {
                     "200": {
-                        "description": "List of cargos",
+                        "description": "User successfully logged out",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ListResponse"
+                            "$ref": "#/definitions/auth.LogoutResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/magic-link": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new cargo with the provided details via form-data",
+                "description": "Emails a single-use, time-limited sign-in link (MAGIC_LINK_TTL) and sets an httpOnly magic_nonce cookie: the link works only in the browser that requested it. A new request replaces the cookie, so only the latest link works. The response is the same whether or not the email is registered. Rate limited per IP and per email.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cargo"
+                    "auth"
                 ],
-                "summary": "Create a new cargo",
+                "summary": "Request a magic sign-in link",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Номер груза",
-                        "name": "cargoNumber",
-                        "in": "formData",
-                        "required": true
+                        "description": "Email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.MagicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link sent if the email is registered",
+                        "schema": {
+                            "$ref": "#/definitions/auth.MagicLinkResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Дата (RFC3339), например 2025-04-30T08:00:00Z",
-                        "name": "date",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата погрузки/разгрузки (2025-04-30T08:00:00Z)",
-                        "name": "loadUnloadDate",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Водитель",
-                        "name": "driver",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Информация о перевозке",
-                        "name": "transportationInfo",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Сумма выплаты, например 12345.67",
-                        "name": "payoutAmount",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата выплаты (RFC3339)",
-                        "name": "payoutDate",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Статус оплаты",
-                        "name": "paymentStatus",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Условия выплаты",
-                        "name": "payoutTerms",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID машины (c8169351-f6d8-4058-af4a-8ead3363fd92)",
-                        "name": "truckId",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Фотографии груза (можно выбрать несколько файлов)",
-                        "name": "photos",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Груз успешно создан",
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/cargo.CreateResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Ошибки валидации или неверный формат данных",
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/cargo/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a cargo by its ID",
+        "/auth/magic-link/verify": {
+            "post": {
+                "description": "Exchanges the token from the emailed link for access and refresh tokens. Must be called from the browser that requested the link (magic_nonce cookie). The link works once; opening it in another browser does not use it up. As with /auth/login, users with two-factor authentication get an MFA token and the next step instead.",
                 "consumes": [
                     "application/json"
                 ],
@@ -358,46 +482,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cargo"
+                    "auth"
                 ],
-                "summary": "Get a cargo by ID",
+                "summary": "Sign in with a magic link",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Cargo ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Token from the link",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.MagicLinkVerifyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Cargo found",
+                        "description": "User successfully authenticated",
                         "schema": {
-                            "$ref": "#/definitions/cargo.GetResponse"
+                            "$ref": "#/definitions/auth.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Second factor required",
+                        "schema": {
+                            "$ref": "#/definitions/mfa.ChallengeResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Link is invalid, expired, already used or opened in another browser",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/mfa/confirm": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a cargo by its ID",
+                "description": "Enables 2FA after checking the first code from the authenticator app. Returns one-time recovery codes; they are shown only once. Not available while impersonating a user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -405,238 +545,202 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cargo"
+                    "auth"
                 ],
-                "summary": "Delete a cargo by ID",
+                "summary": "Confirm 2FA enrollment",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Cargo ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Code from the app",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/mfa.CodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Cargo deleted",
+                        "description": "Recovery codes",
                         "schema": {
-                            "$ref": "#/definitions/cargo.DeleteResponse"
+                            "$ref": "#/definitions/mfa.RecoveryCodesResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid input, wrong code, enrollment not started or 2FA already enabled",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/auth/mfa/disable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates a cargo by its ID",
+                "description": "Disables 2FA for the caller. Requires the password and a code from the app or a recovery code. Not allowed when 2FA is mandatory for the role. Not available while impersonating a user.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cargo"
+                    "auth"
                 ],
-                "summary": "Update a cargo by ID",
+                "summary": "Disable 2FA",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Cargo ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Номер груза",
-                        "name": "cargoNumber",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата (RFC3339), например 2025-04-30T08:00:00Z",
-                        "name": "date",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата погрузки/разгрузки (2025-04-30T08:00:00Z)",
-                        "name": "loadUnloadDate",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Водитель",
-                        "name": "driver",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Информация о перевозке",
-                        "name": "transportationInfo",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Сумма выплаты, например 12345.67",
-                        "name": "payoutAmount",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата выплаты (RFC3339)",
-                        "name": "payoutDate",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Статус оплаты",
-                        "name": "paymentStatus",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Условия выплаты",
-                        "name": "payoutTerms",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID машины (c8169351-f6d8-4058-af4a-8ead3363fd92)",
-                        "name": "truckId",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Фотографии груза (можно выбрать несколько файлов)",
-                        "name": "photos",
-                        "in": "formData"
+                        "description": "Password and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/mfa.DisableRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Cargo updated",
+                        "description": "2FA disabled",
                         "schema": {
-                            "$ref": "#/definitions/cargo.GetResponse"
+                            "$ref": "#/definitions/auth.LogoutResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid input, wrong password or code, 2FA not enabled or mandatory",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/invitation/invite": {
+        "/auth/mfa/enroll": {
             "post": {
-                "description": "Creates a new invitation with the provided details",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Generates a new TOTP secret and otpauth URI for the caller. 2FA is enabled only after /auth/mfa/confirm. Not available while impersonating a user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "invitation"
-                ],
-                "summary": "Create a new invitation",
-                "parameters": [
-                    {
-                        "description": "Invitation object to be created",
-                        "name": "invitation",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/invitation.CreateRequest"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Start 2FA enrollment",
                 "responses": {
-                    "201": {
-                        "description": "Invitation successfully created",
+                    "200": {
+                        "description": "Secret and otpauth URI",
                         "schema": {
-                            "$ref": "#/definitions/invitation.CreateResponse"
+                            "$ref": "#/definitions/mfa.EnrollResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON format",
+                        "description": "2FA already enabled",
                         "schema": {
-                            "$ref": "#/definitions/invitation.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/invitation.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/profile": {
+        "/auth/oidc/callback": {
             "get": {
-                "security": [
+                "description": "Redirect target registered at the provider. Rejected unless the state matches the oidc_state cookie set by /auth/oidc/login. Exchanges the code, verifies the ID token and signs in the user with the provider's email: an existing account, or a new one created from a pending invitation. With OIDC_GROUP_ROLES set, the role is taken from the provider's groups on every sign-in. On success sets the refresh token cookie and redirects to OIDC_SUCCESS_URL, where the client obtains an access token via /auth/refresh. If the user has two-factor authentication enabled (or it is mandatory for the role), redirects there with mfa_token and mfa_step query parameters instead, to be finished as after /auth/login. On failure redirects there with an oidc_error query parameter.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "OIDC callback",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State from /auth/oidc/login",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Error reported by the provider",
+                        "name": "error",
+                        "in": "query"
                     }
                 ],
-                "description": "Retrieves user profile information",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the frontend"
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects the browser to the OpenID Connect provider (authorization code flow with PKCE) and sets an httpOnly oidc_state cookie: the callback is accepted only in the same browser. Available when OIDC_ISSUER is configured.",
                 "tags": [
                     "auth"
                 ],
-                "summary": "User profile",
+                "summary": "Sign in with the corporate identity provider",
                 "responses": {
-                    "200": {
-                        "description": "User profile information",
-                        "schema": {
-                            "$ref": "#/definitions/auth.ProfileResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
-                        }
+                    "302": {
+                        "description": "Redirect to the provider"
                     },
-                    "404": {
-                        "description": "User not found",
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
                         "schema": {
                             "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "502": {
+                        "description": "Provider unavailable",
                         "schema": {
                             "$ref": "#/definitions/auth.ErrorResponse"
                         }
@@ -644,14 +748,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/truck": {
+        "/auth/online": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all trucks in the system",
+                "description": "Retrieves a list of user IDs who are currently online",
                 "consumes": [
                     "application/json"
                 ],
@@ -659,31 +763,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "truck"
+                    "auth"
                 ],
-                "summary": "List all trucks",
+                "summary": "List online users",
                 "responses": {
-                    "201": {
-                        "description": "List of trucks",
+                    "200": {
+                        "description": "List of online user IDs",
                         "schema": {
-                            "$ref": "#/definitions/truck.ListResponse"
+                            "$ref": "#/definitions/auth.OnlineListResponse"
                         }
                     },
-                    "404": {
-                        "description": "Trucks not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/truck.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/password/change": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new truck with the provided details",
+                "description": "Changes the caller's password. Requires the current password; all other sessions are ended. Not available while impersonating a user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -691,50 +803,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "truck"
+                    "auth"
                 ],
-                "summary": "Create a new truck",
+                "summary": "Change password",
                 "parameters": [
                     {
-                        "description": "Truck object to be created",
-                        "name": "truck",
+                        "description": "Current and new password",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/truck.CreateRequest"
+                            "$ref": "#/definitions/user.ChangePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Cargo successfully created",
+                    "200": {
+                        "description": "Password changed",
                         "schema": {
-                            "$ref": "#/definitions/cargo.CreateResponse"
+                            "$ref": "#/definitions/user.PasswordResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON format",
+                        "description": "Wrong current password or password violates the policy",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/truck/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a truck by their ID",
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Emails a single-use, time-limited password reset link (PASSWORD_RESET_TTL). The response is the same whether or not the email is registered.",
                 "consumes": [
                     "application/json"
                 ],
@@ -742,48 +855,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "truck"
+                    "auth"
                 ],
-                "summary": "Get a truck by ID",
+                "summary": "Request a password reset",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Truck ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.ForgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Truck found",
+                    "200": {
+                        "description": "Reset link sent if the email is registered",
                         "schema": {
-                            "$ref": "#/definitions/truck.GetResponse"
+                            "$ref": "#/definitions/user.PasswordResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/truck.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Truck not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/truck.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/truck/{id}/cargos": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of cargos by their truck ID",
+        "/auth/password/reset": {
+            "post": {
+                "description": "Sets a new password using the token from the reset link. The token works once; all sessions of the user are ended.",
                 "consumes": [
                     "application/json"
                 ],
@@ -791,60 +901,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "truck"
+                    "auth"
                 ],
-                "summary": "Get a list of cargos by truck ID",
+                "summary": "Reset password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Truck ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit number of cargos per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "List of cargos",
+                    "200": {
+                        "description": "Password changed",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ListResponse"
+                            "$ref": "#/definitions/user.PasswordResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid or expired token, or password violates the policy",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Truck not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/cargo.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of all users in the system",
+        "/auth/refresh": {
+            "post": {
+                "description": "Refreshes access token using refresh token stored in cookie. The refresh token is rotated on every call; presenting an already rotated token revokes the whole login session.",
                 "consumes": [
                     "application/json"
                 ],
@@ -852,28 +947,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "List all users",
+                "summary": "Refresh access token",
                 "responses": {
-                    "201": {
-                        "description": "List of users",
+                    "200": {
+                        "description": "Token successfully refreshed",
                         "schema": {
-                            "$ref": "#/definitions/user.ListResponse"
+                            "$ref": "#/definitions/auth.RefreshResponse"
                         }
                     },
-                    "404": {
-                        "description": "Users not found",
+                    "401": {
+                        "description": "Invalid or missing refresh token",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
-                "description": "Retrieves a user by their ID",
+        "/auth/register": {
+            "post": {
+                "description": "Registers a new user with email and password by an invitation. The account gets the role and username set in the invitation; the invitation can be used only once. The password must satisfy the password policy (PASSWORD_*).",
                 "consumes": [
                     "application/json"
                 ],
@@ -881,83 +982,174 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User found",
+                        "description": "User successfully registered",
                         "schema": {
-                            "$ref": "#/definitions/user.GetResponse"
+                            "$ref": "#/definitions/auth.RegisterResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid input or password violates the policy",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "429": {
+                        "description": "Too many attempts; see Retry-After",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the caller's logged-in devices: user agent, IP, login time and last token refresh. The session of the current access token is marked as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List my sessions",
+                "responses": {
+                    "200": {
+                        "description": "Active sessions",
+                        "schema": {
+                            "$ref": "#/definitions/session.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes a user by their ID",
-                "consumes": [
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends all of the caller's sessions except the one of the current access token. Not available while impersonating a user.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Sign out everywhere else",
+                "responses": {
+                    "200": {
+                        "description": "IDs of ended sessions",
+                        "schema": {
+                            "$ref": "#/definitions/session.RevokeAllResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends one of the caller's sessions: its refresh token stops working and its access tokens are rejected. Not available while impersonating a user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Delete a user by ID",
+                "summary": "Sign out a session",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Session ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User deleted",
+                    "200": {
+                        "description": "Session ended",
                         "schema": {
-                            "$ref": "#/definitions/user.DeleteResponse"
+                            "$ref": "#/definitions/auth.LogoutResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid ID",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Session not found",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Updates a user by their ID",
+            }
+        },
+        "/cargo": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves cargos with filters, sorting and cursor-based pagination. Payout fields, filters and sorting require finance:view. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
                 "consumes": [
                     "application/json"
                 ],
@@ -965,166 +1157,5571 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "cargo"
                 ],
-                "summary": "Update a user by ID",
+                "summary": "List cargos",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID машины",
+                        "name": "truckId",
+                        "in": "query"
                     },
                     {
-                        "description": "User object to be updated",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.UpdateRequest"
-                        }
+                        "type": "string",
+                        "description": "ID водителя",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ФИО водителя (поиск по подстроке)",
+                        "name": "driver",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID заказчика",
+                        "name": "customerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузоотправителя",
+                        "name": "shipperId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузополучателя",
+                        "name": "consigneeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус оплаты",
+                        "name": "paymentStatus",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "planned",
+                            "loading",
+                            "in_transit",
+                            "delivered",
+                            "invoiced",
+                            "paid",
+                            "closed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Статус груза",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата от (RFC3339)",
+                        "name": "dateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата до (RFC3339)",
+                        "name": "dateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата погрузки/разгрузки от (RFC3339)",
+                        "name": "loadUnloadDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата погрузки/разгрузки до (RFC3339)",
+                        "name": "loadUnloadDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата выплаты от (RFC3339)",
+                        "name": "payoutDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата выплаты до (RFC3339)",
+                        "name": "payoutDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма выплаты",
+                        "name": "payoutAmountMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма выплаты",
+                        "name": "payoutAmountMax",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "createdAt",
+                            "date",
+                            "loadUnloadDate",
+                            "payoutDate",
+                            "payoutAmount",
+                            "cargoNumber"
+                        ],
+                        "type": "string",
+                        "description": "Поле сортировки",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (nextCursor из предыдущего ответа)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User updated",
+                    "200": {
+                        "description": "List of cargos",
                         "schema": {
-                            "$ref": "#/definitions/user.UpdateResponse"
+                            "$ref": "#/definitions/cargo.ListResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid filter",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/cargo.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponse"
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new cargo with the provided details via form-data",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Create a new cargo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер груза",
+                        "name": "cargoNumber",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата (RFC3339), например 2025-04-30T08:00:00Z",
+                        "name": "date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата погрузки/разгрузки (2025-04-30T08:00:00Z)",
+                        "name": "loadUnloadDate",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Плановое начало перевозки (RFC3339)",
+                        "name": "plannedStart",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Плановое окончание перевозки (RFC3339)",
+                        "name": "plannedEnd",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID водителя (GET /drivers)",
+                        "name": "driverId",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Информация о перевозке",
+                        "name": "transportationInfo",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Сумма выплаты, например 12345.67",
+                        "name": "payoutAmount",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата выплаты (RFC3339)",
+                        "name": "payoutDate",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус оплаты",
+                        "name": "paymentStatus",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Условия выплаты",
+                        "name": "payoutTerms",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Вес груза, кг (не больше грузоподъёмности машины)",
+                        "name": "weightKg",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Объём груза, м³ (не больше объёма кузова машины)",
+                        "name": "volumeM3",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество паллет",
+                        "name": "pallets",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3",
+                            "4.1",
+                            "4.2",
+                            "4.3",
+                            "5.1",
+                            "5.2",
+                            "6.1",
+                            "6.2",
+                            "7",
+                            "8",
+                            "9"
+                        ],
+                        "type": "string",
+                        "description": "Класс опасности ДОПОГ",
+                        "name": "hazardClass",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID машины (c8169351-f6d8-4058-af4a-8ead3363fd92)",
+                        "name": "truckId",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID заказчика (GET /counterparties)",
+                        "name": "customerId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузоотправителя (GET /counterparties)",
+                        "name": "shipperId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузополучателя (GET /counterparties)",
+                        "name": "consigneeId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Фотографии груза (можно выбрать несколько файлов)",
+                        "name": "photos",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Груз успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.CreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибки валидации или неверный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Машина занята в это время (SCHEDULE_CONFLICT_MODE=reject)",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a cargo by its ID. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Get a cargo by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cargo found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a cargo by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Delete a cargo by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cargo deleted",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo not found or already deleted",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates a cargo by its ID. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Update a cargo by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Номер груза",
+                        "name": "cargoNumber",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата (RFC3339), например 2025-04-30T08:00:00Z",
+                        "name": "date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата погрузки/разгрузки (2025-04-30T08:00:00Z)",
+                        "name": "loadUnloadDate",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Плановое начало перевозки (RFC3339)",
+                        "name": "plannedStart",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Плановое окончание перевозки (RFC3339)",
+                        "name": "plannedEnd",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID водителя (GET /drivers)",
+                        "name": "driverId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Информация о перевозке",
+                        "name": "transportationInfo",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Сумма выплаты, например 12345.67",
+                        "name": "payoutAmount",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата выплаты (RFC3339)",
+                        "name": "payoutDate",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус оплаты",
+                        "name": "paymentStatus",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Условия выплаты",
+                        "name": "payoutTerms",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Вес груза, кг (не больше грузоподъёмности машины)",
+                        "name": "weightKg",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Объём груза, м³ (не больше объёма кузова машины)",
+                        "name": "volumeM3",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество паллет",
+                        "name": "pallets",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3",
+                            "4.1",
+                            "4.2",
+                            "4.3",
+                            "5.1",
+                            "5.2",
+                            "6.1",
+                            "6.2",
+                            "7",
+                            "8",
+                            "9"
+                        ],
+                        "type": "string",
+                        "description": "Класс опасности ДОПОГ",
+                        "name": "hazardClass",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID машины (c8169351-f6d8-4058-af4a-8ead3363fd92)",
+                        "name": "truckId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID заказчика (GET /counterparties)",
+                        "name": "customerId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузоотправителя (GET /counterparties)",
+                        "name": "shipperId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузополучателя (GET /counterparties)",
+                        "name": "consigneeId",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Фотографии груза (можно выбрать несколько файлов)",
+                        "name": "photos",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cargo updated",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Truck is busy at that time (SCHEDULE_CONFLICT_MODE=reject)",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all revisions of a cargo (create, patch, status transitions, photo changes, delete, revert) with field-level diff, author and time. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Cargo change history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cargo revisions, newest first",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.HistoryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/revert/{revisionId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores cargo fields from the snapshot of the given revision. Status and photos are not restored. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Revert a cargo to a revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision ID",
+                        "name": "revisionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cargo reverted",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Revision cannot be reverted",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Truck is busy at the restored time (SCHEDULE_CONFLICT_MODE=reject)",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/stops": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the ordered list of pickup and delivery stops of a cargo. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Get cargo route",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cargo route",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.StopsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a load or unload stop. Without position the stop is appended to the end of the route. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Add cargo stop",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stop",
+                        "name": "stop",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cargo.AddStopRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Updated route",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.StopsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/stops/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the order of stops. The list must contain every stop of the cargo exactly once. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Reorder cargo stops",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stop IDs in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ReorderStopsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated route",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.StopsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/stops/{stopId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a stop; following stops move up. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Delete cargo stop",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stop ID",
+                        "name": "stopId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated route",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.StopsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo or stop not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/stops/{stopId}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records actual arrival and departure times of a stop. Missing times default to now. Available to all roles, like other physical-movement updates. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Complete cargo stop",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stop ID",
+                        "name": "stopId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Actual arrival and departure times",
+                        "name": "times",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.CompleteStopRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stop completed",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.StopResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo or stop not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Stop already completed",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/transition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a cargo to another lifecycle status (draft → planned → loading → in_transit → delivered → invoiced → paid → closed, or cancelled). Only legal transitions allowed for the caller's role are accepted. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Change cargo status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status and optional comment",
+                        "name": "transition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cargo.TransitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status changed",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.TransitionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Transition is not allowed for the role",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Illegal transition",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cargo/{id}/transitions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all status transitions of a cargo with author, time and comment. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cargo"
+                ],
+                "summary": "Cargo status history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cargo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status transitions",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.TransitionListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cargo not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/counterparties": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches counterparties by INN prefix or by a substring of the legal or short name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "counterparties"
+                ],
+                "summary": "Search counterparties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ИНН (начало) или часть названия",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимум записей (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of counterparties",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a customer, shipper or consignee. INN and OGRN are checked by checksum.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "counterparties"
+                ],
+                "summary": "Create a new counterparty",
+                "parameters": [
+                    {
+                        "description": "Counterparty to be created",
+                        "name": "counterparty",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Counterparty successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.CreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format or validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Counterparty with the same INN and KPP exists",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/counterparties/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a counterparty with contacts and bank details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "counterparties"
+                ],
+                "summary": "Get a counterparty by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Counterparty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Counterparty found",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.GetResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Counterparty not found",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a counterparty that is not referenced by any cargo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "counterparties"
+                ],
+                "summary": "Delete a counterparty by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Counterparty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Counterparty deleted",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Counterparty not found",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Counterparty is used by cargos",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates counterparty fields. Contacts and bank details are replaced as a whole.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "counterparties"
+                ],
+                "summary": "Update a counterparty by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Counterparty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "counterparty",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.UpdateCounterpartyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Counterparty updated",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format or validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Counterparty not found",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Counterparty with the same INN and KPP exists",
+                        "schema": {
+                            "$ref": "#/definitions/counterparty.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves drivers ordered by full name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "List drivers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поиск по ФИО, телефону или номеру удостоверения",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "on_leave",
+                            "dismissed"
+                        ],
+                        "type": "string",
+                        "description": "Статус водителя",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of drivers",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a driver. Phone must be a Russian number, licence number — series and number of a Russian driving licence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Create a new driver",
+                "parameters": [
+                    {
+                        "description": "Driver to be created",
+                        "name": "driver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/driver.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Driver successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/driver.CreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format or validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a driver by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get a driver by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver found",
+                        "schema": {
+                            "$ref": "#/definitions/driver.GetResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a driver without cargos. Drivers referenced by cargos must be dismissed instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Delete a driver by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver deleted",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Driver has cargos",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates driver fields. To retire a driver set status to dismissed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Update a driver by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "driver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/driver.UpdateDriverInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver updated",
+                        "schema": {
+                            "$ref": "#/definitions/driver.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format or validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/driver.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns impersonations, newest first. Requires user:impersonate.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impersonations"
+                ],
+                "summary": "List impersonations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only impersonations of this user",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Impersonations",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an impersonation with every request made with its token, oldest first. Requires user:impersonate.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impersonations"
+                ],
+                "summary": "Get an impersonation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Impersonation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Impersonation",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.GetResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Impersonation not found",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists invitations, newest first, optionally filtered by status: pending (waiting for registration), used (account created) or expired. Requires invitation:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "List invitations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, used or expired",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitations",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invites up to 500 addresses at once. Accepts a JSON array of invitations or CSV (text/csv body, or multipart/form-data with a \"file\" field) with columns email, role, username; a header row is optional. Returns a result per address: sent, already_user, already_invited, email_failed (the invitation exists and can be resent), invalid or failed (internal error). Requires invitation:manage.",
+                "consumes": [
+                    "application/json",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Invite a list of users",
+                "parameters": [
+                    {
+                        "description": "Invitations (JSON)",
+                        "name": "invitations",
+                        "in": "body",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/invitation.CreateRequest"
+                            }
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result per address",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or empty list",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many invitations; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation/invite": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new invitation with the provided details. Role (USER by default) and username are applied to the account at registration. An expired or used invitation for the same email is replaced. Requires invitation:manage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Create a new invitation",
+                "parameters": [
+                    {
+                        "description": "Invitation object to be created",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/invitation.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invitation successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.CreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format, validation error or already invited",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many invitations; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a pending or expired invitation; its link stops working. Used invitations cannot be revoked. Requires invitation:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Revoke an invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitation revoked",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invitation already used",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitation/{id}/resend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a new token for a pending or expired invitation, restarts its lifetime and emails the new link. The previous link stops working. Requires invitation:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Resend an invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitation resent",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invitation already used",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many invitations; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/invitation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every permission with its description and the roles it is granted to. Requires permission:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "List permissions",
+                "responses": {
+                    "200": {
+                        "description": "Permissions and roles",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the permissions granted to the caller's role, or to the API key for service clients, so the client can hide unavailable actions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "My permissions",
+                "responses": {
+                    "200": {
+                        "description": "Caller's permissions",
+                        "schema": {
+                            "$ref": "#/definitions/permission.RoleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/{role}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the whole permission set of a role. Takes effect on the next request of its users. permission:manage cannot be taken away from SUPERADMIN. Requires permission:manage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Set role permissions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "USER",
+                            "EDITOR",
+                            "SUPERADMIN"
+                        ],
+                        "type": "string",
+                        "description": "Role",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New permission set",
+                        "name": "permissions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/permission.SetRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permissions updated",
+                        "schema": {
+                            "$ref": "#/definitions/permission.RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown role or permission",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/permission.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves user profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile information",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ProfileResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns per-truck timelines for the period: cargos by planned start/end and free gaps between them. Cancelled cargos are not shown. Without cargo:view_all only trucks assigned to the caller are shown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Truck scheduling board",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339), не дальше 93 дней от начала",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduling board",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves soft-deleted cargos and trucks, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List trash",
+                "responses": {
+                    "200": {
+                        "description": "Trash items",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{type}/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently deletes a soft-deleted cargo or truck together with its files. Requires trash:purge.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge from trash",
+                "parameters": [
+                    {
+                        "enum": [
+                            "cargo",
+                            "truck"
+                        ],
+                        "type": "string",
+                        "description": "Object type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Purged",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Cannot be purged",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{type}/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a soft-deleted cargo or truck",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore from trash",
+                "parameters": [
+                    {
+                        "enum": [
+                            "cargo",
+                            "truck"
+                        ],
+                        "type": "string",
+                        "description": "Object type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Cannot be restored",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Cargo number, truck name, licence plate or VIN is taken by another object",
+                        "schema": {
+                            "$ref": "#/definitions/trash.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/truck": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all trucks in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "List all trucks",
+                "responses": {
+                    "201": {
+                        "description": "List of trucks",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trucks not found",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new truck with the provided details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Create a new truck",
+                "parameters": [
+                    {
+                        "description": "Truck object to be created",
+                        "name": "truck",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/truck.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Truck successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/truck.CreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format or validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Licence plate or VIN already in use",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/truck/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a truck by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Get a truck by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Truck ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Truck found",
+                        "schema": {
+                            "$ref": "#/definitions/truck.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Truck not found",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a truck to the trash. Trucks with unfinished cargos cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Delete a truck by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Truck ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Truck deleted",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Truck not found",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Truck has unfinished cargos",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates truck attributes. Licence plate must be a Russian plate, VIN must pass checksum validation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Update a truck by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Truck ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "truck",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/truck.UpdateTruckInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Truck updated",
+                        "schema": {
+                            "$ref": "#/definitions/truck.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON format or validation errors",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Truck not found",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Licence plate or VIN already in use",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/truck/{id}/cargos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves cargos of the truck with the same filters, sorting and cursor-based pagination as GET /cargo. Payout fields, filters and sorting require finance:view. Without cargo:view_all only cargos of trucks assigned to the caller are visible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Get a list of cargos by truck ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Truck ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID водителя",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ФИО водителя (поиск по подстроке)",
+                        "name": "driver",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID заказчика",
+                        "name": "customerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузоотправителя",
+                        "name": "shipperId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID грузополучателя",
+                        "name": "consigneeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус оплаты",
+                        "name": "paymentStatus",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "planned",
+                            "loading",
+                            "in_transit",
+                            "delivered",
+                            "invoiced",
+                            "paid",
+                            "closed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Статус груза",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата от (RFC3339)",
+                        "name": "dateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата до (RFC3339)",
+                        "name": "dateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата погрузки/разгрузки от (RFC3339)",
+                        "name": "loadUnloadDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата погрузки/разгрузки до (RFC3339)",
+                        "name": "loadUnloadDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата выплаты от (RFC3339)",
+                        "name": "payoutDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата выплаты до (RFC3339)",
+                        "name": "payoutDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма выплаты",
+                        "name": "payoutAmountMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма выплаты",
+                        "name": "payoutAmountMax",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "createdAt",
+                            "date",
+                            "loadUnloadDate",
+                            "payoutDate",
+                            "payoutAmount",
+                            "cargoNumber"
+                        ],
+                        "type": "string",
+                        "description": "Поле сортировки",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (nextCursor из предыдущего ответа)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "List of cargos",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Truck not found",
+                        "schema": {
+                            "$ref": "#/definitions/cargo.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/truck/{id}/files": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaches photos and documents (registration, insurance, etc.) to a truck",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Upload truck files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Truck ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Фотографии машины (можно выбрать несколько файлов)",
+                        "name": "photos",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Документы машины (можно выбрать несколько файлов)",
+                        "name": "documents",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Files uploaded",
+                        "schema": {
+                            "$ref": "#/definitions/truck.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid form",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/truck/{id}/files/{fileId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a photo or document attached to a truck",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "truck"
+                ],
+                "summary": "Delete truck file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Truck ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "fileId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File deleted",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/truck.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads a cargo photo or a truck file as an attachment. Cargo photos require cargo:view, truck files require truck:view. Without cargo:view_all only files of trucks assigned to the caller and of their cargos are available. Files of deleted cargos and trucks are not served to such callers.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Download a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File name from the file URL",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File contents",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/file.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/file.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all users in the system. Requires user:view.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List all users",
+                "responses": {
+                    "201": {
+                        "description": "List of users",
+                        "schema": {
+                            "$ref": "#/definitions/user.ListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Users not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a user by their ID. Requires user:view.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User found",
+                        "schema": {
+                            "$ref": "#/definitions/user.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a user by their ID. Requires user:manage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User deleted",
+                        "schema": {
+                            "$ref": "#/definitions/user.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates a user by their ID. Requires user:manage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User object to be updated",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User updated",
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a short-lived access token (IMPERSONATION_TTL) of the user to see the system as they do. The token carries an act claim with the caller and cannot be refreshed; it stops working when the caller's own session ends, when the caller's role changes or their tokens are revoked, or when their role loses user:impersonate. Requests made with it are logged and recorded in the impersonation journal with their response status. While impersonating, user:manage, invitation:manage, permission:manage, apikey:manage and user:impersonate are withheld even if the user's role has them, and changing the password, two-factor authentication or sessions is not allowed. SUPERADMIN users cannot be impersonated. Requires user:impersonate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "impersonations"
+                ],
+                "summary": "Impersonate a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason, e.g. a support ticket",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.StartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Impersonation token",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.StartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON, validation error, the caller themselves or a SUPERADMIN",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, or the caller's token has no session",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/impersonation.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/lock": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lifts the temporary login lockout set after too many failed login attempts and resets the failure counter. Requires user:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Unlock a user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account unlocked",
+                        "schema": {
+                            "$ref": "#/definitions/user.DeleteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists active sessions of any user. Requires user:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List user sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active sessions",
+                        "schema": {
+                            "$ref": "#/definitions/session.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Signs a user out everywhere. Requires user:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "End all user sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "IDs of ended sessions",
+                        "schema": {
+                            "$ref": "#/definitions/session.RevokeAllResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends one session of any user. Requires user:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "End a user session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session ended",
+                        "schema": {
+                            "$ref": "#/definitions/user.DeleteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/trucks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns IDs of the trucks assigned to a user. Users without cargo:view_all see only cargos of these trucks. Requires user:view.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List user trucks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assigned truck IDs",
+                        "schema": {
+                            "$ref": "#/definitions/user.TrucksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the whole list of trucks assigned to a user; an empty list removes all assignments. Requires user:manage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Assign trucks to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Truck IDs",
+                        "name": "trucks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.SetTrucksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assigned truck IDs",
+                        "schema": {
+                            "$ref": "#/definitions/user.TrucksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON, validation errors or unknown truck",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/validate-token": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validates an access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Validate token",
+                "responses": {
+                    "200": {
+                        "description": "Token is valid",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikey.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Бухгалтерия"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/permission.Permission"
+                    }
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "tpk_3f9a1c"
+                },
+                "revokedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "apikey.CreateRequest": {
+            "type": "object",
+            "required": [
+                "expiresAt",
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3,
+                    "example": "Бухгалтерия"
+                },
+                "permissions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/permission.Permission"
+                    }
+                }
+            }
+        },
+        "apikey.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apikey.Created"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Ключ API создан. Сохраните его: повторно он показан не будет"
+                }
+            }
+        },
+        "apikey.Created": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "tpk_3f9a1c..."
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Бухгалтерия"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/permission.Permission"
+                    }
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "tpk_3f9a1c"
+                },
+                "revokedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "apikey.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "ключ API не найден"
+                }
+            }
+        },
+        "apikey.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apikey.APIKey"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Ключи API"
+                }
+            }
+        },
+        "apikey.RevokeResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Ключ API отозван"
+                }
+            }
+        },
+        "auth.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Некорректные данные"
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "firulvv@mail.ru"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "auth.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Пользователь успешно авторизован"
+                }
+            }
+        },
+        "auth.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Успешный выход из системы"
+                }
+            }
+        },
+        "auth.MagicLinkRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "firulvv@mail.ru"
+                }
+            }
+        },
+        "auth.MagicLinkResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Если этот email зарегистрирован, на него отправлена ссылка для входа"
+                }
+            }
+        },
+        "auth.MagicLinkVerifyRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJFZERTQSIs..."
+                }
+            }
+        },
+        "auth.OnlineListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Список ID-шников онлайн пользователей"
+                }
+            }
+        },
+        "auth.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.User"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Данные о пользователе"
+                }
+            }
+        },
+        "auth.RefreshResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Токен успешно обновлён"
+                }
+            }
+        },
+        "auth.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "firulvv@mail.ru"
+                },
+                "inviteToken": {
+                    "type": "string",
+                    "example": "token"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "auth.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.User"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Пользователь успешно зарегистрирован"
+                }
+            }
+        },
+        "cargo.AddStopRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "type"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "г. Москва, ул. Складочная, д. 1"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 55.7963
+                },
+                "lng": {
+                    "type": "number",
+                    "example": 37.5921
+                },
+                "plannedFrom": {
+                    "type": "string",
+                    "example": "2025-04-30T08:00:00Z"
+                },
+                "plannedTo": {
+                    "type": "string",
+                    "example": "2025-04-30T12:00:00Z"
+                },
+                "position": {
+                    "description": "Position — куда вставить остановку; по умолчанию в конец маршрута.",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 2
+                },
+                "type": {
+                    "enum": [
+                        "load",
+                        "unload"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.StopType"
+                        }
+                    ],
+                    "example": "load"
+                }
+            }
+        },
+        "cargo.Cargo": {
+            "type": "object",
+            "required": [
+                "cargoNumber",
+                "driverId",
+                "transportationInfo",
+                "truckId"
+            ],
+            "properties": {
+                "cargoNumber": {
+                    "type": "string"
+                },
+                "cargoPhotos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.CargoPhoto"
+                    }
+                },
+                "consigneeId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "customerId": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "driverId": {
+                    "type": "string"
+                },
+                "hazardClass": {
+                    "type": "string",
+                    "example": "3"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "loadUnloadDate": {
+                    "type": "string"
+                },
+                "pallets": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 33
+                },
+                "paymentStatus": {
+                    "type": "string"
+                },
+                "payoutAmount": {
+                    "type": "number"
+                },
+                "payoutDate": {
+                    "type": "string"
+                },
+                "payoutTerms": {
+                    "type": "string"
+                },
+                "plannedEnd": {
+                    "type": "string"
+                },
+                "plannedStart": {
+                    "type": "string"
+                },
+                "scheduleConflicts": {
+                    "description": "ScheduleConflicts заполняется при создании и изменении груза в режиме\nSCHEDULE_CONFLICT_MODE=warn, если машина в это время уже занята.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.ScheduleSlot"
+                    }
+                },
+                "shipperId": {
+                    "type": "string"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.Status"
+                        }
+                    ],
+                    "example": "draft"
+                },
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.Stop"
+                    }
+                },
+                "transportationInfo": {
+                    "type": "string"
+                },
+                "truckId": {
+                    "type": "string"
+                },
+                "volumeM3": {
+                    "type": "number",
+                    "example": 64
+                },
+                "weightKg": {
+                    "type": "number",
+                    "example": 18500
+                }
+            }
+        },
+        "cargo.CargoPhoto": {
+            "type": "object",
+            "properties": {
+                "cargoId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "cargo.CompleteStopRequest": {
+            "type": "object",
+            "properties": {
+                "arrivedAt": {
+                    "type": "string",
+                    "example": "2025-04-30T09:15:00Z"
+                },
+                "departedAt": {
+                    "type": "string",
+                    "example": "2025-04-30T10:40:00Z"
+                }
+            }
+        },
+        "cargo.ConflictResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.ScheduleSlot"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "машина уже занята другим грузом в это время: 1234"
+                }
+            }
+        },
+        "cargo.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/cargo.Cargo"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Груз успешно создан"
+                }
+            }
+        },
+        "cargo.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Груз успешно удалён"
+                }
+            }
+        },
+        "cargo.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Невалидный формат JSON"
+                }
+            }
+        },
+        "cargo.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {}
+            }
+        },
+        "cargo.Gap": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-05-01T18:00:00Z"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-05-03T08:00:00Z"
+                }
+            }
+        },
+        "cargo.GetResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/cargo.Cargo"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Данные о грузе"
+                }
+            }
+        },
+        "cargo.HistoryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.Revision"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "История изменений груза"
+                }
+            }
+        },
+        "cargo.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/cargo.ListResult"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Список всех грузов"
+                }
+            }
+        },
+        "cargo.ListResult": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.Cargo"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJzIjoiY3JlYXRlZEF0In0"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "cargo.ReorderStopsRequest": {
+            "type": "object",
+            "required": [
+                "stopIds"
+            ],
+            "properties": {
+                "stopIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "cargo.Revision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.RevisionAction"
+                        }
+                    ],
+                    "example": "patch"
+                },
+                "cargoId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/cargo.FieldChange"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "snapshot": {
+                    "type": "object"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "cargo.RevisionAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "patch",
+                "transition",
+                "photo_add",
+                "photo_delete",
+                "delete",
+                "restore",
+                "revert",
+                "stops"
+            ],
+            "x-enum-varnames": [
+                "RevisionCreate",
+                "RevisionPatch",
+                "RevisionTransition",
+                "RevisionPhotoAdd",
+                "RevisionPhotoDelete",
+                "RevisionDelete",
+                "RevisionRestore",
+                "RevisionRevert",
+                "RevisionStops"
+            ]
+        },
+        "cargo.ScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.TruckTimeline"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Расписание машин"
+                }
+            }
+        },
+        "cargo.ScheduleSlot": {
+            "type": "object",
+            "properties": {
+                "cargoId": {
+                    "type": "string"
+                },
+                "cargoNumber": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "end": {
+                    "type": "string",
+                    "example": "2025-05-01T18:00:00Z"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2025-04-30T08:00:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.Status"
+                        }
+                    ],
+                    "example": "planned"
+                }
+            }
+        },
+        "cargo.Status": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "planned",
+                "loading",
+                "in_transit",
+                "delivered",
+                "invoiced",
+                "paid",
+                "closed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "StatusDraft",
+                "StatusPlanned",
+                "StatusLoading",
+                "StatusInTransit",
+                "StatusDelivered",
+                "StatusInvoiced",
+                "StatusPaid",
+                "StatusClosed",
+                "StatusCancelled"
+            ]
+        },
+        "cargo.StatusTransition": {
+            "type": "object",
+            "properties": {
+                "cargoId": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "Машина на погрузке"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "from": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.Status"
+                        }
+                    ],
+                    "example": "planned"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "to": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.Status"
+                        }
+                    ],
+                    "example": "loading"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "cargo.Stop": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "г. Москва, ул. Складочная, д. 1"
+                },
+                "arrivedAt": {
+                    "type": "string"
+                },
+                "cargoId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "departedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 55.7963
+                },
+                "lng": {
+                    "type": "number",
+                    "example": 37.5921
+                },
+                "plannedFrom": {
+                    "type": "string",
+                    "example": "2025-04-30T08:00:00Z"
+                },
+                "plannedTo": {
+                    "type": "string",
+                    "example": "2025-04-30T12:00:00Z"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.StopType"
+                        }
+                    ],
+                    "example": "load"
+                }
+            }
+        },
+        "cargo.StopResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/cargo.Stop"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Остановка завершена"
+                }
+            }
+        },
+        "cargo.StopType": {
+            "type": "string",
+            "enum": [
+                "load",
+                "unload"
+            ],
+            "x-enum-varnames": [
+                "StopLoad",
+                "StopUnload"
+            ]
+        },
+        "cargo.StopsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.Stop"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Маршрут груза"
+                }
+            }
+        },
+        "cargo.TransitionListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.StatusTransition"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "История статусов груза"
+                }
+            }
+        },
+        "cargo.TransitionRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Машина на погрузке"
+                },
+                "to": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/cargo.Status"
+                        }
+                    ],
+                    "example": "loading"
+                }
+            }
+        },
+        "cargo.TransitionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/cargo.Cargo"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Статус груза изменён"
+                }
+            }
+        },
+        "cargo.TruckTimeline": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "busyHours": {
+                    "description": "BusyHours — сколько часов периода машина занята (пересечения считаются один раз).",
+                    "type": "number",
+                    "example": 34
+                },
+                "gaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.Gap"
+                    }
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cargo.ScheduleSlot"
+                    }
+                },
+                "truckId": {
+                    "type": "string"
+                },
+                "truckName": {
+                    "type": "string",
+                    "example": "Машина"
+                }
+            }
+        },
+        "counterparty.BankDetails": {
+            "type": "object",
+            "required": [
+                "account",
+                "bankName",
+                "bik"
+            ],
+            "properties": {
+                "account": {
+                    "type": "string",
+                    "example": "40702810938000000001"
+                },
+                "bankName": {
+                    "type": "string",
+                    "example": "ПАО Сбербанк"
+                },
+                "bik": {
+                    "type": "string",
+                    "example": "044525225"
+                },
+                "correspondentAccount": {
+                    "type": "string",
+                    "example": "30101810400000000225"
+                }
+            }
+        },
+        "counterparty.Contact": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "logist@example.ru"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Петров Пётр"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "Логист"
+                }
+            }
+        },
+        "counterparty.Counterparty": {
+            "type": "object",
+            "required": [
+                "inn",
+                "legalName"
+            ],
+            "properties": {
+                "bank": {
+                    "$ref": "#/definitions/counterparty.BankDetails"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/counterparty.Contact"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inn": {
+                    "type": "string",
+                    "example": "7707083893"
+                },
+                "kpp": {
+                    "type": "string",
+                    "example": "773601001"
+                },
+                "legalAddress": {
+                    "type": "string",
+                    "example": "г. Москва, ул. Вавилова, д. 19"
+                },
+                "legalName": {
+                    "type": "string",
+                    "example": "ООО «Ромашка»"
+                },
+                "ogrn": {
+                    "type": "string",
+                    "example": "1027700132195"
+                },
+                "postalAddress": {
+                    "type": "string",
+                    "example": "г. Москва, ул. Вавилова, д. 19"
+                },
+                "shortName": {
+                    "type": "string",
+                    "example": "Ромашка"
+                }
+            }
+        },
+        "counterparty.CreateRequest": {
+            "type": "object",
+            "properties": {
+                "bank": {
+                    "$ref": "#/definitions/counterparty.BankDetails"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/counterparty.Contact"
+                    }
+                },
+                "inn": {
+                    "type": "string",
+                    "example": "7707083893"
+                },
+                "kpp": {
+                    "type": "string",
+                    "example": "773601001"
+                },
+                "legalAddress": {
+                    "type": "string",
+                    "example": "г. Москва, ул. Вавилова, д. 19"
+                },
+                "legalName": {
+                    "type": "string",
+                    "example": "ООО «Ромашка»"
+                },
+                "ogrn": {
+                    "type": "string",
+                    "example": "1027700132195"
+                },
+                "postalAddress": {
+                    "type": "string",
+                    "example": "г. Москва, ул. Вавилова, д. 19"
+                },
+                "shortName": {
+                    "type": "string",
+                    "example": "Ромашка"
+                }
+            }
+        },
+        "counterparty.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/counterparty.Counterparty"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Контрагент успешно создан"
+                }
+            }
+        },
+        "counterparty.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "контрагент не найден"
+                }
+            }
+        },
+        "counterparty.GetResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/counterparty.Counterparty"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Контрагент"
+                }
+            }
+        },
+        "counterparty.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/counterparty.Counterparty"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Список контрагентов"
+                }
+            }
+        },
+        "counterparty.UpdateCounterpartyInput": {
+            "type": "object",
+            "properties": {
+                "bank": {
+                    "$ref": "#/definitions/counterparty.BankDetails"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/counterparty.Contact"
+                    }
+                },
+                "inn": {
+                    "type": "string",
+                    "example": "7707083893"
+                },
+                "kpp": {
+                    "type": "string",
+                    "example": "773601001"
+                },
+                "legalAddress": {
+                    "type": "string"
+                },
+                "legalName": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "ООО «Ромашка»"
+                },
+                "ogrn": {
+                    "type": "string",
+                    "example": "1027700132195"
+                },
+                "postalAddress": {
+                    "type": "string"
+                },
+                "shortName": {
+                    "type": "string",
+                    "example": "Ромашка"
+                }
+            }
+        },
+        "driver.CreateRequest": {
+            "type": "object",
+            "properties": {
+                "fullName": {
+                    "type": "string",
+                    "example": "Иванов Иван Иванович"
+                },
+                "licenseCategories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "C",
+                        "CE"
+                    ]
+                },
+                "licenseExpiry": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "licenseNumber": {
+                    "type": "string",
+                    "example": "9901123456"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/driver.Status"
+                        }
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "driver.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/driver.Driver"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Водитель успешно создан"
+                }
+            }
+        },
+        "driver.Driver": {
+            "type": "object",
+            "required": [
+                "fullName",
+                "status"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "fullName": {
+                    "type": "string",
+                    "example": "Иванов Иван Иванович"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "licenseCategories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "C",
+                        "CE"
+                    ]
+                },
+                "licenseExpiry": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "licenseNumber": {
+                    "type": "string",
+                    "example": "9901123456"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "on_leave",
+                        "dismissed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/driver.Status"
+                        }
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "driver.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "водитель не найден"
+                }
+            }
+        },
+        "driver.GetResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/driver.Driver"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Водитель"
+                }
+            }
+        },
+        "driver.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/driver.Driver"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Список водителей"
+                }
+            }
+        },
+        "driver.Status": {
+            "type": "string",
+            "enum": [
+                "active",
+                "on_leave",
+                "dismissed"
+            ],
+            "x-enum-varnames": [
+                "StatusActive",
+                "StatusOnLeave",
+                "StatusDismissed"
+            ]
+        },
+        "driver.UpdateDriverInput": {
+            "type": "object",
+            "properties": {
+                "fullName": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Иванов Иван Иванович"
+                },
+                "licenseCategories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "C",
+                        "CE"
+                    ]
+                },
+                "licenseExpiry": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "licenseNumber": {
+                    "type": "string",
+                    "example": "9901123456"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "on_leave",
+                        "dismissed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/driver.Status"
+                        }
+                    ],
+                    "example": "on_leave"
+                }
+            }
+        },
+        "file.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "файл не найден"
+                }
+            }
+        },
+        "impersonation.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "нельзя войти от имени суперадминистратора"
+                }
+            }
+        },
+        "impersonation.GetResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/impersonation.Impersonation"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Вход от имени пользователя"
+                }
+            }
+        },
+        "impersonation.Impersonation": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "10.0.0.5"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Заявка 1432: не видит груз"
+                },
+                "requests": {
+                    "description": "Requests заполняется только при чтении одного входа.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/impersonation.Request"
+                    }
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "impersonation.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/impersonation.Impersonation"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Входы от имени пользователей"
+                }
+            }
+        },
+        "impersonation.Request": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/v1/cargo/5d7c..."
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                }
+            }
+        },
+        "impersonation.StartRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Заявка 1432: не видит груз"
+                }
+            }
+        },
+        "impersonation.StartResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/impersonation.Started"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Выполнен вход от имени пользователя"
+                }
+            }
+        },
+        "impersonation.Started": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "impersonation": {
+                    "$ref": "#/definitions/impersonation.Impersonation"
+                }
+            }
+        },
+        "invitation.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invitation.BulkResult"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Приглашения обработаны: отправлено 2 из 3"
+                }
+            }
+        },
+        "invitation.BulkResult": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "firulvv@mail.ru"
+                },
+                "id": {
+                    "description": "ID — приглашение; есть для sent и email_failed (его можно отправить повторно).",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message — причина для статусов, отличных от sent.",
+                    "type": "string"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/invitation.BulkStatus"
                         }
-                    }
+                    ],
+                    "example": "sent"
                 }
             }
         },
-        "/validate-token": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Validates an access token",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Validate token",
-                "responses": {
-                    "200": {
-                        "description": "Token is valid",
-                        "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/auth.ErrorResponse"
+        "invitation.BulkStatus": {
+            "type": "string",
+            "enum": [
+                "sent",
+                "already_user",
+                "already_invited",
+                "email_failed",
+                "invalid",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "BulkSent",
+                "BulkAlreadyUser",
+                "BulkAlreadyInvited",
+                "BulkEmailFailed",
+                "BulkInvalid",
+                "BulkFailed"
+            ]
+        },
+        "invitation.CreateRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "firulvv@mail.ru"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.Role"
                         }
-                    }
+                    ],
+                    "example": "EDITOR"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "Иван"
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.ErrorResponse": {
+        },
+        "invitation.CreateResponse": {
             "type": "object",
             "properties": {
-                "data": {},
+                "data": {
+                    "type": "string"
+                },
                 "message": {
                     "type": "string",
-                    "example": "Некорректные данные"
+                    "example": "Приглашение успешно создано"
                 }
             }
         },
-        "auth.LoginRequest": {
+        "invitation.DeleteResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "message": {
                     "type": "string",
-                    "example": "firulvv@mail.ru"
-                },
-                "password": {
+                    "example": "Приглашение отозвано"
+                }
+            }
+        },
+        "invitation.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
                     "type": "string",
-                    "example": "123456"
+                    "example": "Невалидный формат JSON"
                 }
             }
         },
-        "auth.LoginResponse": {
+        "invitation.GetResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                    "$ref": "#/definitions/invitation.Invitation"
                 },
                 "message": {
                     "type": "string",
-                    "example": "Пользователь успешно авторизован"
+                    "example": "Приглашение отправлено повторно"
                 }
             }
         },
-        "auth.LogoutResponse": {
+        "invitation.Invitation": {
             "type": "object",
+            "required": [
+                "email",
+                "token"
+            ],
             "properties": {
-                "message": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role и Username применяются к учётной записи при регистрации.",
+                    "enum": [
+                        "USER",
+                        "EDITOR",
+                        "SUPERADMIN"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.Role"
+                        }
+                    ]
+                },
+                "status": {
+                    "$ref": "#/definitions/invitation.Status"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "used": {
+                    "type": "boolean",
+                    "default": false
+                },
+                "usedAt": {
+                    "type": "string"
+                },
+                "username": {
                     "type": "string",
-                    "example": "Успешный выход из системы"
+                    "minLength": 3
                 }
             }
         },
-        "auth.OnlineListResponse": {
+        "invitation.ListResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/invitation.Invitation"
                     }
                 },
                 "message": {
                     "type": "string",
-                    "example": "Список ID-шников онлайн пользователей"
+                    "example": "Список приглашений"
                 }
             }
         },
-        "auth.ProfileResponse": {
+        "invitation.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "used",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusUsed",
+                "StatusExpired"
+            ]
+        },
+        "mfa.ChallengeRequest": {
             "type": "object",
+            "required": [
+                "mfaToken"
+            ],
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/user.User"
-                },
-                "message": {
+                "code": {
+                    "description": "Code — код из приложения или резервный код. Для начала обязательного\nподключения (/auth/login/mfa/setup) не нужен.",
                     "type": "string",
-                    "example": "Данные о пользователе"
+                    "example": "123456"
+                },
+                "mfaToken": {
+                    "type": "string"
                 }
             }
         },
-        "auth.RefreshResponse": {
+        "mfa.ChallengeResponse": {
             "type": "object",
             "properties": {
                 "data": {
@@ -1135,20 +6732,33 @@ const docTemplate = `{
                 },
                 "message": {
                     "type": "string",
-                    "example": "Токен успешно обновлён"
+                    "example": "Введите код двухфакторной аутентификации"
                 }
             }
         },
-        "auth.RegisterRequest": {
+        "mfa.CodeRequest": {
             "type": "object",
+            "required": [
+                "code"
+            ],
             "properties": {
-                "email": {
+                "code": {
                     "type": "string",
-                    "example": "firulvv@mail.ru"
-                },
-                "inviteToken": {
+                    "example": "123456"
+                }
+            }
+        },
+        "mfa.DisableRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code — код из приложения или резервный код.",
                     "type": "string",
-                    "example": "token"
+                    "example": "123456"
                 },
                 "password": {
                     "type": "string",
@@ -1156,187 +6766,391 @@ const docTemplate = `{
                 }
             }
         },
-        "auth.RegisterResponse": {
+        "mfa.EnrollResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/user.User"
+                    "$ref": "#/definitions/mfa.Enrollment"
                 },
                 "message": {
                     "type": "string",
-                    "example": "Пользователь успешно зарегистрирован"
+                    "example": "Отсканируйте QR-код в приложении-аутентификаторе и подтвердите кодом"
                 }
             }
         },
-        "cargo.Cargo": {
+        "mfa.Enrollment": {
             "type": "object",
-            "required": [
-                "cargoNumber",
-                "driver",
-                "transportationInfo",
-                "truckId"
-            ],
             "properties": {
-                "cargoNumber": {
+                "otpauthUri": {
+                    "type": "string",
+                    "example": "otpauth://totp/Myakos:firulvv@mail.ru?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP\u0026issuer=Myakos"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "mfa.LoginConfirmResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/mfa.LoginConfirmation"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Двухфакторная аутентификация включена. Сохраните резервные коды"
+                }
+            }
+        },
+        "mfa.LoginConfirmation": {
+            "type": "object",
+            "properties": {
+                "access_token": {
                     "type": "string"
                 },
-                "cargoPhotos": {
+                "recovery_codes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/cargo.CargoPhoto"
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "mfa.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 },
-                "createdAt": {
-                    "type": "string"
-                },
-                "date": {
-                    "type": "string"
-                },
-                "driver": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "loadUnloadDate": {
-                    "type": "string"
-                },
-                "paymentStatus": {
-                    "type": "string"
-                },
-                "payoutAmount": {
-                    "type": "number"
-                },
-                "payoutDate": {
-                    "type": "string"
-                },
-                "payoutTerms": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "example": "Двухфакторная аутентификация включена. Сохраните резервные коды"
+                }
+            }
+        },
+        "permission.Definition": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Создание грузов"
                 },
-                "transportationInfo": {
-                    "type": "string"
+                "name": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/permission.Permission"
+                        }
+                    ],
+                    "example": "cargo:create"
                 },
-                "truckId": {
-                    "type": "string"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.Role"
+                    }
                 }
             }
         },
-        "cargo.CargoPhoto": {
+        "permission.ErrorResponse": {
             "type": "object",
             "properties": {
-                "cargoId": {
-                    "type": "string"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Неизвестное разрешение"
                 }
             }
         },
-        "cargo.CreateResponse": {
+        "permission.ListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/cargo.Cargo"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/permission.Definition"
+                    }
                 },
                 "message": {
                     "type": "string",
-                    "example": "Груз успешно создан"
+                    "example": "Разрешения и роли"
                 }
             }
         },
-        "cargo.DeleteResponse": {
+        "permission.Permission": {
+            "type": "string",
+            "enum": [
+                "cargo:view",
+                "cargo:create",
+                "cargo:update",
+                "cargo:delete",
+                "cargo:transition",
+                "cargo:view_all",
+                "finance:view",
+                "truck:view",
+                "truck:manage",
+                "driver:view",
+                "driver:manage",
+                "counterparty:view",
+                "counterparty:manage",
+                "schedule:view",
+                "trash:view",
+                "trash:restore",
+                "trash:purge",
+                "user:view",
+                "user:manage",
+                "invitation:manage",
+                "permission:manage",
+                "apikey:manage",
+                "user:impersonate"
+            ],
+            "x-enum-varnames": [
+                "CargoView",
+                "CargoCreate",
+                "CargoUpdate",
+                "CargoDelete",
+                "CargoTransition",
+                "CargoViewAll",
+                "FinanceView",
+                "TruckView",
+                "TruckManage",
+                "DriverView",
+                "DriverManage",
+                "CounterpartyView",
+                "CounterpartyManage",
+                "ScheduleView",
+                "TrashView",
+                "TrashRestore",
+                "TrashPurge",
+                "UserView",
+                "UserManage",
+                "InvitationManage",
+                "PermissionManage",
+                "APIKeyManage",
+                "UserImpersonate"
+            ]
+        },
+        "permission.RoleResponse": {
             "type": "object",
             "properties": {
-                "data": {},
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/permission.Permission"
+                    }
+                },
                 "message": {
                     "type": "string",
-                    "example": "Груз успешно удалён"
+                    "example": "Разрешения роли обновлены"
                 }
             }
         },
-        "cargo.ErrorResponse": {
+        "permission.SetRoleRequest": {
             "type": "object",
+            "required": [
+                "permissions"
+            ],
             "properties": {
-                "data": {},
-                "message": {
-                    "type": "string",
-                    "example": "Невалидный формат JSON"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/permission.Permission"
+                    }
                 }
             }
         },
-        "cargo.GetResponse": {
+        "session.ListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/cargo.Cargo"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/session.Session"
+                    }
                 },
                 "message": {
                     "type": "string",
-                    "example": "Данные о грузе"
+                    "example": "Активные сессии"
                 }
             }
         },
-        "cargo.ListResponse": {
+        "session.RevokeAllResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/cargo.Cargo"
+                        "type": "string"
                     }
                 },
                 "message": {
                     "type": "string",
-                    "example": "Список всех грузов"
+                    "example": "Сессии завершены"
                 }
             }
         },
-        "invitation.CreateRequest": {
+        "session.Session": {
             "type": "object",
-            "required": [
-                "email"
-            ],
             "properties": {
-                "email": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current — сессия, из которой сделан запрос.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
                     "type": "string",
-                    "example": "firulvv@mail.ru"
+                    "example": "203.0.113.7"
+                },
+                "lastRefreshedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"
+                },
+                "userId": {
+                    "type": "string"
                 }
             }
         },
-        "invitation.CreateResponse": {
+        "trash.ErrorResponse": {
             "type": "object",
             "properties": {
-                "data": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "объект не найден в корзине"
+                }
+            }
+        },
+        "trash.Item": {
+            "type": "object",
+            "properties": {
+                "deletedAt": {
                     "type": "string"
                 },
-                "message": {
+                "deletedBy": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string",
-                    "example": "Приглашение успешно создано"
+                    "example": "1234"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/trash.ItemType"
+                        }
+                    ],
+                    "example": "cargo"
                 }
             }
         },
-        "invitation.ErrorResponse": {
+        "trash.ItemType": {
+            "type": "string",
+            "enum": [
+                "cargo",
+                "truck"
+            ],
+            "x-enum-varnames": [
+                "TypeCargo",
+                "TypeTruck"
+            ]
+        },
+        "trash.ListResponse": {
             "type": "object",
             "properties": {
-                "data": {},
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/trash.Item"
+                    }
+                },
                 "message": {
                     "type": "string",
-                    "example": "Невалидный формат JSON"
+                    "example": "Корзина"
+                }
+            }
+        },
+        "truck.Attachment": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
         "truck.CreateRequest": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "licensePlate": {
+                    "type": "string",
+                    "example": "А123ВС77"
+                },
+                "make": {
+                    "type": "string",
+                    "example": "КАМАЗ"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "54901"
+                },
                 "name": {
                     "type": "string",
                     "example": "Машина"
+                },
+                "payloadKg": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "vin": {
+                    "type": "string",
+                    "example": "1M8GDM9AXKP042788"
+                },
+                "volumeM3": {
+                    "type": "number",
+                    "example": 82
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2021
+                }
+            }
+        },
+        "truck.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/truck.Truck"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Машина успешно создана"
                 }
             }
         },
@@ -1354,10 +7168,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/truck.Truck"
-                    }
+                    "$ref": "#/definitions/truck.Truck"
                 },
                 "message": {
                     "type": "string",
@@ -1382,15 +7193,125 @@ const docTemplate = `{
         },
         "truck.Truck": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "createdAt": {
                     "type": "string"
                 },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/truck.Attachment"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
+                "licensePlate": {
+                    "type": "string",
+                    "example": "А123ВС77"
+                },
+                "make": {
+                    "type": "string",
+                    "example": "КАМАЗ"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "54901"
+                },
                 "name": {
                     "type": "string"
+                },
+                "payloadKg": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/truck.Attachment"
+                    }
+                },
+                "vin": {
+                    "type": "string",
+                    "example": "1M8GDM9AXKP042788"
+                },
+                "volumeM3": {
+                    "type": "number",
+                    "example": 82
+                },
+                "year": {
+                    "type": "integer",
+                    "maximum": 2100,
+                    "minimum": 1950,
+                    "example": 2021
+                }
+            }
+        },
+        "truck.UpdateTruckInput": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "licensePlate": {
+                    "type": "string",
+                    "example": "А123ВС77"
+                },
+                "make": {
+                    "type": "string",
+                    "example": "КАМАЗ"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "54901"
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Машина"
+                },
+                "payloadKg": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "vin": {
+                    "type": "string",
+                    "example": "1M8GDM9AXKP042788"
+                },
+                "volumeM3": {
+                    "type": "number",
+                    "example": 82
+                },
+                "year": {
+                    "type": "integer",
+                    "maximum": 2100,
+                    "minimum": 1950,
+                    "example": 2021
+                }
+            }
+        },
+        "user.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "currentPassword",
+                "newPassword"
+            ],
+            "properties": {
+                "currentPassword": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "example": "N3wPassw0rd"
                 }
             }
         },
@@ -1414,6 +7335,18 @@ const docTemplate = `{
                 }
             }
         },
+        "user.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "firulvv@mail.ru"
+                }
+            }
+        },
         "user.GetResponse": {
             "type": "object",
             "properties": {
@@ -1441,19 +7374,80 @@ const docTemplate = `{
                 }
             }
         },
+        "user.PasswordResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Пароль изменён"
+                }
+            }
+        },
+        "user.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "N3wPassw0rd"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "8c5b3f..."
+                }
+            }
+        },
         "user.Role": {
             "type": "string",
             "enum": [
                 "USER",
                 "EDITOR",
-                "SUPERADMIN"
+                "SUPERADMIN",
+                "SERVICE"
             ],
             "x-enum-varnames": [
                 "RoleUser",
                 "RoleEditor",
-                "RoleSuperAdmin"
+                "RoleSuperAdmin",
+                "RoleService"
             ]
         },
+        "user.SetTrucksRequest": {
+            "type": "object",
+            "required": [
+                "truckIds"
+            ],
+            "properties": {
+                "truckIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "c8169351-f6d8-4058-af4a-8ead3363fd92"
+                    ]
+                }
+            }
+        },
+        "user.TrucksResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Машины пользователя"
+                }
+            }
+        },
         "user.UpdateRequest": {
             "type": "object",
             "required": [
@@ -1522,7 +7516,7 @@ const docTemplate = `{
     },
     "securityDefinitions": {
         "BearerAuth": {
-            "description": "Bearer token for authenticated requests",
+            "description": "\"Bearer \u003caccess token\u003e\" or \"Bearer \u003cAPI key\u003e\"; API keys are also accepted in the X-API-Key header",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -0,0 +1,132 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	apikeyDomain "test-project/internal/domain/apikey"
+	"test-project/internal/domain/auth"
+	"test-project/internal/domain/permission"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/internal/validator"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	uc        usecase.APIKeyUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
+}
+
+func NewHandler(uc usecase.APIKeyUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, deps: deps, validator: v}
+}
+
+func RegisterAPIKeyRoutes(r *mux.Router, deps *auth.Deps) {
+	v, err := validator.New()
+	if err != nil {
+		log.Fatal("Ошибка инициализации валидатора:", err)
+	}
+
+	h := NewHandler(deps.APIKeys, deps, v)
+
+	r.Handle("/api-keys", middleware.Require(deps, permission.APIKeyManage, h.List)).Methods(http.MethodGet)
+	r.Handle("/api-keys", middleware.Require(deps, permission.APIKeyManage, h.Create)).Methods(http.MethodPost)
+	r.Handle("/api-keys/{id}", middleware.Require(deps, permission.APIKeyManage, h.Revoke)).Methods(http.MethodDelete)
+}
+
+// List returns all API keys
+// @Summary List API keys
+// @Description Returns all API keys, including revoked and expired ones. The keys themselves are never returned, only their first characters. Requires apikey:manage.
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} apikey.ListResponse "API keys"
+// @Failure 401 {object} apikey.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apikey.ErrorResponse "Internal server error"
+// @Router /api-keys [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.uc.List()
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Ключи API", list, h.deps.Logger)
+}
+
+// Create issues an API key
+// @Summary Create an API key
+// @Description Issues a key for an integration. The key is returned only in this response; send it as the X-API-Key header or as "Authorization: Bearer <key>". Requests with the key act as a SERVICE principal with exactly the listed permissions. apikey:manage cannot be granted to a key. Requires apikey:manage.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body apikey.CreateRequest true "Name, permissions and expiry"
+// @Security BearerAuth
+// @Success 201 {object} apikey.CreateResponse "Key created"
+// @Failure 400 {object} apikey.ErrorResponse "Invalid JSON, validation error, unknown permission or expiry in the past"
+// @Failure 401 {object} apikey.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apikey.ErrorResponse "Internal server error"
+// @Router /api-keys [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req apikeyDomain.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	created, err := h.uc.Create(req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, permission.ErrUnknown),
+			errors.Is(err, apikeyDomain.ErrExpiry),
+			errors.Is(err, apikeyDomain.ErrSelfManage):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	utils.JSON(w, http.StatusCreated, "Ключ API создан. Сохраните его: повторно он показан не будет", created, h.deps.Logger)
+}
+
+// Revoke revokes an API key
+// @Summary Revoke an API key
+// @Description Revokes a key; requests with it are rejected immediately. Requires apikey:manage.
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Security BearerAuth
+// @Success 200 {object} apikey.RevokeResponse "Key revoked"
+// @Failure 401 {object} apikey.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apikey.ErrorResponse "Key not found or already revoked"
+// @Failure 500 {object} apikey.ErrorResponse "Internal server error"
+// @Router /api-keys/{id} [delete]
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.Revoke(mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, apikeyDomain.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Ключ API отозван", nil, h.deps.Logger)
+}
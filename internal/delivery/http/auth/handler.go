@@ -2,12 +2,20 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"test-project/config"
 	"test-project/internal/domain/auth"
+	"test-project/internal/domain/mfa"
+	"test-project/internal/domain/session"
+	"test-project/internal/domain/user"
 	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/internal/validator"
+	"test-project/pkg"
 	"test-project/utils"
 	"time"
 
@@ -15,31 +23,98 @@ import (
 )
 
 type Handler struct {
-	deps auth.Deps
+	deps      auth.Deps
+	sessions  usecase.SessionUsecase
+	passwords usecase.PasswordUsecase
+	mfa       usecase.MfaUsecase
+	validator *validator.Validator
 }
 
-func NewHandler(deps *auth.Deps) *Handler {
+func NewHandler(deps *auth.Deps, sessions usecase.SessionUsecase, passwords usecase.PasswordUsecase, mfa usecase.MfaUsecase, v *validator.Validator) *Handler {
 	return &Handler{
-		deps: *deps,
+		deps:      *deps,
+		sessions:  sessions,
+		passwords: passwords,
+		mfa:       mfa,
+		validator: v,
 	}
 }
 
 func RegisterCargoRoute(r *mux.Router, deps *auth.Deps) {
-	h := NewHandler(deps)
+	v, err := validator.New()
+	if err != nil {
+		log.Fatal("Ошибка инициализации валидатора:", err)
+	}
+
+	sessionRepo := session.NewPostgresSessionRepo(deps.DB)
+	policy := user.PasswordPolicy{
+		MinLength:      config.Envs.PASSWORD_MIN_LENGTH,
+		RequireDigit:   config.Envs.PASSWORD_REQUIRE_DIGIT,
+		RequireUpper:   config.Envs.PASSWORD_REQUIRE_UPPER,
+		RequireSpecial: config.Envs.PASSWORD_REQUIRE_SPECIAL,
+	}
+	passwords := usecase.NewPasswordUsecase(
+		user.NewPostgresUserRepo(deps.DB),
+		user.NewPostgresPasswordResetRepo(deps.DB),
+		sessionRepo,
+		deps.Redis,
+		policy,
+		config.Envs.PASSWORD_RESET_TTL,
+		v,
+	)
+	mfaUC := usecase.NewMfaUsecase(
+		mfa.NewPostgresRepo(deps.DB),
+		user.NewPostgresUserRepo(deps.DB),
+		config.Envs.MFA_ISSUER,
+		config.Envs.MFA_REQUIRED_FOR_SUPERADMIN,
+	)
+	h := NewHandler(deps, usecase.NewSessionUsecase(sessionRepo, deps.Redis), passwords, mfaUC, v)
+
+	loginLimit := middleware.RateLimit(deps, middleware.RateRule{
+		Name:     "login",
+		PerIP:    config.Envs.RATE_LIMIT_LOGIN_IP,
+		PerEmail: config.Envs.RATE_LIMIT_LOGIN_EMAIL,
+	})
+	registerLimit := middleware.RateLimit(deps, middleware.RateRule{
+		Name:  "register",
+		PerIP: config.Envs.RATE_LIMIT_REGISTER_IP,
+	})
+	magicLinkLimit := middleware.RateLimit(deps, middleware.RateRule{
+		Name:     "magic-link",
+		PerIP:    config.Envs.RATE_LIMIT_MAGIC_LINK_IP,
+		PerEmail: config.Envs.RATE_LIMIT_MAGIC_LINK_EMAIL,
+	})
 
-	r.HandleFunc("/auth/register", h.register).Methods(http.MethodPost)
-	r.HandleFunc("/auth/login", h.login).Methods(http.MethodPost)
-	r.HandleFunc("/auth/logout", h.logout).Methods(http.MethodPost)
-	r.HandleFunc("/auth/refresh", h.refresh).Methods(http.MethodPost)
+	r.Handle("/auth/register", middleware.Public(registerLimit(http.HandlerFunc(h.register)))).Methods(http.MethodPost)
+	r.Handle("/auth/login", middleware.Public(loginLimit(http.HandlerFunc(h.login)))).Methods(http.MethodPost)
+	r.Handle("/auth/login/mfa", middleware.Public(loginLimit(http.HandlerFunc(h.loginMFA)))).Methods(http.MethodPost)
+	r.Handle("/auth/login/mfa/setup", middleware.Public(loginLimit(http.HandlerFunc(h.loginMFASetup)))).Methods(http.MethodPost)
+	r.Handle("/auth/login/mfa/confirm", middleware.Public(loginLimit(http.HandlerFunc(h.loginMFAConfirm)))).Methods(http.MethodPost)
+	r.Handle("/auth/magic-link", middleware.Public(magicLinkLimit(http.HandlerFunc(h.magicLink)))).Methods(http.MethodPost)
+	r.Handle("/auth/magic-link/verify", middleware.Public(loginLimit(http.HandlerFunc(h.verifyMagicLink)))).Methods(http.MethodPost)
+	r.Handle("/auth/logout", middleware.Public(http.HandlerFunc(h.logout))).Methods(http.MethodPost)
+	r.Handle("/auth/refresh", middleware.Public(http.HandlerFunc(h.refresh))).Methods(http.MethodPost)
+	r.Handle("/auth/password/forgot", middleware.Public(http.HandlerFunc(h.forgotPassword))).Methods(http.MethodPost)
+	r.Handle("/auth/password/reset", middleware.Public(http.HandlerFunc(h.resetPassword))).Methods(http.MethodPost)
+	r.Handle("/auth/password/change", middleware.JwtMiddleware(deps, middleware.DenyImpersonation(deps, h.changePassword))).Methods(http.MethodPost)
+	registerOIDCRoutes(r, deps, loginLimit)
+
+	r.Handle("/auth/mfa/enroll", middleware.JwtMiddleware(deps, middleware.DenyImpersonation(deps, h.enrollMFA))).Methods(http.MethodPost)
+	r.Handle("/auth/mfa/confirm", middleware.JwtMiddleware(deps, middleware.DenyImpersonation(deps, h.confirmMFA))).Methods(http.MethodPost)
+	r.Handle("/auth/mfa/disable", middleware.JwtMiddleware(deps, middleware.DenyImpersonation(deps, h.disableMFA))).Methods(http.MethodPost)
 
 	r.Handle("/validate-token", middleware.JwtMiddleware(deps, h.validateToken)).Methods(http.MethodPost)
 	r.Handle("/profile", middleware.JwtMiddleware(deps, h.profile)).Methods(http.MethodGet)
 	r.Handle("/auth/online", middleware.JwtMiddleware(deps, h.onlineList)).Methods(http.MethodGet)
+
+	r.Handle("/auth/sessions", middleware.JwtMiddleware(deps, h.listSessions)).Methods(http.MethodGet)
+	r.Handle("/auth/sessions", middleware.JwtMiddleware(deps, middleware.DenyImpersonation(deps, h.revokeOtherSessions))).Methods(http.MethodDelete)
+	r.Handle("/auth/sessions/{id}", middleware.JwtMiddleware(deps, middleware.DenyImpersonation(deps, h.revokeSession))).Methods(http.MethodDelete)
 }
 
 // refresh handles token refresh
 // @Summary Refresh access token
-// @Description Refreshes access token using refresh token stored in cookie
+// @Description Refreshes access token using refresh token stored in cookie. The refresh token is rotated on every call; presenting an already rotated token revokes the whole login session.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -54,38 +129,32 @@ func (h *Handler) refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, role, err := h.deps.JwtService.ValidateRefresh(cookie.Value)
-
-	if err != nil {
-		utils.JSON(w, http.StatusUnauthorized, "Невалидный refresh токен", nil, h.deps.Logger)
-		http.SetCookie(w, &http.Cookie{
-			Name:     "refresh_token",
-			Value:    "",
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   config.Envs.APP_ENV == "production",
-			SameSite: http.SameSiteLaxMode,
-			MaxAge:   -1,
-			Domain:   config.GetCookieDomain(),
-		})
-		return
-	}
-
-	// Генерируем новые access и refresh токены
-	newAccessToken, err := h.deps.JwtService.GenerateAccess(userID, role)
+	newAccessToken, newRefreshToken, err := h.deps.AuthService.Refresh(cookie.Value)
 	if err != nil {
-		utils.JSON(w, http.StatusInternalServerError, "Ошибка генерации access токена", nil, h.deps.Logger)
-		return
-	}
-	newRefreshToken, err := h.deps.JwtService.GenerateRefresh(userID, role)
-	if err != nil {
-		utils.JSON(w, http.StatusInternalServerError, "Ошибка генерации refresh токена", nil, h.deps.Logger)
+		if !errors.Is(err, usecase.ErrRefreshInvalid) && !errors.Is(err, usecase.ErrRefreshReused) {
+			utils.JSON(w, http.StatusInternalServerError, "Ошибка обновления токена", nil, h.deps.Logger)
+			return
+		}
+		if errors.Is(err, usecase.ErrRefreshReused) {
+			h.deps.Logger.Warn("повторное использование refresh токена, семейство отозвано")
+		}
+		clearRefreshCookie(w)
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
+	setRefreshCookie(w, newRefreshToken)
+
+	utils.JSON(w, http.StatusOK, "Токен успешно обновлён", map[string]string{
+		"access_token": newAccessToken,
+	}, h.deps.Logger)
+}
+
+// setRefreshCookie кладёт refresh-токен в httpOnly-куку.
+func setRefreshCookie(w http.ResponseWriter, token string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "refresh_token",
-		Value:    newRefreshToken,
+		Value:    token,
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   config.Envs.APP_ENV == "production",
@@ -93,21 +162,31 @@ func (h *Handler) refresh(w http.ResponseWriter, r *http.Request) {
 		MaxAge:   int((7 * 24 * time.Hour).Seconds()), // 7 дней
 		Domain:   config.GetCookieDomain(),
 	})
+}
 
-	utils.JSON(w, http.StatusOK, "Токен успешно обновлён", map[string]string{
-		"access_token": newAccessToken,
-	}, h.deps.Logger)
+func clearRefreshCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   config.Envs.APP_ENV == "production",
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+		Domain:   config.GetCookieDomain(),
+	})
 }
 
 // register handles user registration
 // @Summary Register a new user
-// @Description Registers a new user with email and password
+// @Description Registers a new user with email and password by an invitation. The account gets the role and username set in the invitation; the invitation can be used only once. The password must satisfy the password policy (PASSWORD_*).
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body auth.RegisterRequest true "User credentials"
 // @Success 201 {object} auth.RegisterResponse "User successfully registered"
-// @Failure 400 {object} auth.ErrorResponse "Invalid input"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input or password violates the policy"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
 // @Router /auth/register [post]
 func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 	var req struct{ Email, InviteToken, Password string }
@@ -125,7 +204,12 @@ func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	u, err := h.deps.AuthService.Register(req.Email, req.Password)
+	if err := h.passwords.CheckPolicy(req.Password); err != nil {
+		h.passwordError(w, err)
+		return
+	}
+
+	u, err := h.deps.AuthService.Register(req.InviteToken, req.Email, req.Password)
 	if err != nil {
 		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
 		return
@@ -136,14 +220,16 @@ func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
 
 // login handles user authentication
 // @Summary User login
-// @Description Authenticates a user and returns access and refresh tokens
+// @Description Authenticates a user and returns access and refresh tokens. If the user has two-factor authentication enabled (or it is mandatory for the role but not set up yet), returns an MFA token and the next step instead: "verify" — finish with /auth/login/mfa, "setup" — enroll with /auth/login/mfa/setup and /auth/login/mfa/confirm. The MFA token lives 5 minutes.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body auth.LoginRequest true "User credentials"
 // @Success 200 {object} auth.LoginResponse "User successfully authenticated"
+// @Success 202 {object} mfa.ChallengeResponse "Second factor required"
 // @Failure 400 {object} auth.ErrorResponse "Invalid input"
 // @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts or account temporarily locked; see Retry-After"
 // @Router /auth/login [post]
 func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -156,30 +242,286 @@ func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.deps.AuthService.Login(req.Email, req.Password)
+	res, err := h.deps.AuthService.Login(req.Email, req.Password, r.UserAgent(), utils.ClientIP(r))
 	if err != nil {
+		var locked *usecase.LockedError
+		if errors.As(err, &locked) {
+			h.accountLocked(w, locked)
+			return
+		}
 		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
+	h.firstFactorResult(w, res)
+}
+
+// firstFactorResult отвечает на вход после первого фактора: 202 с
+// MFA-токеном, если нужен второй фактор, иначе access-токен и кука.
+func (h *Handler) firstFactorResult(w http.ResponseWriter, res usecase.LoginResult) {
+	if res.MFAToken != "" {
+		msg := "Введите код двухфакторной аутентификации"
+		if res.MFAStep == usecase.MFAPurposeSetup {
+			msg = "Для входа необходимо подключить двухфакторную аутентификацию"
+		}
+		utils.JSON(w, http.StatusAccepted, msg, map[string]string{
+			"mfa_token": res.MFAToken,
+			"mfa_step":  res.MFAStep,
+		}, h.deps.Logger)
+		return
+	}
+
 	// w.Header().Set("Authorization", "Bearer "+token)
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    refreshToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   config.Envs.APP_ENV == "production",
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   int((7 * 24 * time.Hour).Seconds()),
-		Domain:   config.GetCookieDomain(),
-	})
+	setRefreshCookie(w, res.RefreshToken)
 
 	utils.JSON(w, http.StatusOK, "Пользователь успешно авторизован", map[string]string{
-		"access_token": accessToken,
+		"access_token": res.AccessToken,
 	}, h.deps.Logger)
 }
 
+// accountLocked отвечает 429, пока вход заблокирован после неудачных попыток.
+func (h *Handler) accountLocked(w http.ResponseWriter, err *usecase.LockedError) {
+	middleware.RetryAfter(w, err.RetryAfter)
+	utils.JSON(w, http.StatusTooManyRequests, err.Error(), nil, h.deps.Logger)
+}
+
+// mfaError переводит ошибки двухфакторной аутентификации в HTTP-ответ.
+func (h *Handler) mfaError(w http.ResponseWriter, err error) {
+	var locked *usecase.LockedError
+
+	switch {
+	case errors.As(err, &locked):
+		h.accountLocked(w, locked)
+	case errors.Is(err, mfa.ErrChallengeFailed):
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, mfa.ErrInvalidCode),
+		errors.Is(err, mfa.ErrNotEnrolled),
+		errors.Is(err, mfa.ErrAlreadyEnabled),
+		errors.Is(err, mfa.ErrNotStarted),
+		errors.Is(err, mfa.ErrRequired),
+		errors.Is(err, user.ErrWrongPassword):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+	default:
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+	}
+}
+
+// decodeChallenge читает и валидирует тело запроса шага MFA-входа.
+func (h *Handler) decodeChallenge(w http.ResponseWriter, r *http.Request, needCode bool) (mfa.ChallengeRequest, bool) {
+	var req mfa.ChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return req, false
+	}
+	errs := h.validator.Validate(req)
+	if needCode && strings.TrimSpace(req.Code) == "" {
+		errs = append(errs, "Code обязательное поле")
+	}
+	if len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return req, false
+	}
+	return req, true
+}
+
+// loginMFA finishes login with a second factor
+// @Summary Finish login with a 2FA code
+// @Description Exchanges the MFA token from /auth/login (step "verify") and a code from the authenticator app or a one-time recovery code for access and refresh tokens.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body mfa.ChallengeRequest true "MFA token and code"
+// @Success 200 {object} auth.LoginResponse "User successfully authenticated"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input or wrong code"
+// @Failure 401 {object} auth.ErrorResponse "Expired MFA token"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
+// @Router /auth/login/mfa [post]
+func (h *Handler) loginMFA(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeChallenge(w, r, true)
+	if !ok {
+		return
+	}
+
+	res, err := h.deps.AuthService.LoginMFA(req.MFAToken, req.Code, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		h.mfaError(w, err)
+		return
+	}
+
+	setRefreshCookie(w, res.RefreshToken)
+
+	utils.JSON(w, http.StatusOK, "Пользователь успешно авторизован", map[string]string{
+		"access_token": res.AccessToken,
+	}, h.deps.Logger)
+}
+
+// loginMFASetup starts mandatory 2FA enrollment during login
+// @Summary Start mandatory 2FA enrollment during login
+// @Description For accounts that must use 2FA but have not set it up (step "setup" from /auth/login): returns a new TOTP secret and otpauth URI for the authenticator app.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body mfa.ChallengeRequest true "MFA token"
+// @Success 200 {object} mfa.EnrollResponse "Secret and otpauth URI"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input"
+// @Failure 401 {object} auth.ErrorResponse "Expired MFA token"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
+// @Router /auth/login/mfa/setup [post]
+func (h *Handler) loginMFASetup(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeChallenge(w, r, false)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.deps.AuthService.LoginMFASetup(req.MFAToken)
+	if err != nil {
+		h.mfaError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Отсканируйте QR-код в приложении-аутентификаторе и подтвердите кодом", enrollment, h.deps.Logger)
+}
+
+// loginMFAConfirm confirms mandatory 2FA enrollment and finishes login
+// @Summary Confirm 2FA enrollment and finish login
+// @Description Confirms the enrollment started with /auth/login/mfa/setup by the first code from the app, enables 2FA and logs in. Recovery codes are returned once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body mfa.ChallengeRequest true "MFA token and code"
+// @Success 200 {object} mfa.LoginConfirmResponse "Access token and recovery codes"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input, wrong code or enrollment not started"
+// @Failure 401 {object} auth.ErrorResponse "Expired MFA token"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
+// @Router /auth/login/mfa/confirm [post]
+func (h *Handler) loginMFAConfirm(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeChallenge(w, r, true)
+	if !ok {
+		return
+	}
+
+	res, err := h.deps.AuthService.LoginMFAConfirm(req.MFAToken, req.Code, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		h.mfaError(w, err)
+		return
+	}
+
+	setRefreshCookie(w, res.RefreshToken)
+
+	utils.JSON(w, http.StatusOK, "Двухфакторная аутентификация включена. Сохраните резервные коды", mfa.LoginConfirmation{
+		AccessToken:   res.AccessToken,
+		RecoveryCodes: res.RecoveryCodes,
+	}, h.deps.Logger)
+}
+
+// enrollMFA starts 2FA enrollment
+// @Summary Start 2FA enrollment
+// @Description Generates a new TOTP secret and otpauth URI for the caller. 2FA is enabled only after /auth/mfa/confirm. Not available while impersonating a user.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} mfa.EnrollResponse "Secret and otpauth URI"
+// @Failure 400 {object} auth.ErrorResponse "2FA already enabled"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/mfa/enroll [post]
+func (h *Handler) enrollMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	enrollment, err := h.mfa.Enroll(userID)
+	if err != nil {
+		h.mfaError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Отсканируйте QR-код в приложении-аутентификаторе и подтвердите кодом", enrollment, h.deps.Logger)
+}
+
+// confirmMFA enables 2FA
+// @Summary Confirm 2FA enrollment
+// @Description Enables 2FA after checking the first code from the authenticator app. Returns one-time recovery codes; they are shown only once. Not available while impersonating a user.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body mfa.CodeRequest true "Code from the app"
+// @Success 200 {object} mfa.RecoveryCodesResponse "Recovery codes"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input, wrong code, enrollment not started or 2FA already enabled"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/mfa/confirm [post]
+func (h *Handler) confirmMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	var req mfa.CodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	codes, err := h.mfa.Confirm(userID, req.Code)
+	if err != nil {
+		h.mfaError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Двухфакторная аутентификация включена. Сохраните резервные коды", codes, h.deps.Logger)
+}
+
+// disableMFA turns 2FA off
+// @Summary Disable 2FA
+// @Description Disables 2FA for the caller. Requires the password and a code from the app or a recovery code. Not allowed when 2FA is mandatory for the role. Not available while impersonating a user.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body mfa.DisableRequest true "Password and code"
+// @Success 200 {object} auth.LogoutResponse "2FA disabled"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input, wrong password or code, 2FA not enabled or mandatory"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/mfa/disable [post]
+func (h *Handler) disableMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	var req mfa.DisableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	if err := h.mfa.Disable(userID, req.Password, req.Code); err != nil {
+		h.mfaError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Двухфакторная аутентификация отключена", nil, h.deps.Logger)
+}
+
 // onlineList retrieves a list of online user IDs
 // @Summary List online users
 // @Description Retrieves a list of user IDs who are currently online
@@ -208,23 +550,22 @@ func (h *Handler) onlineList(w http.ResponseWriter, r *http.Request) {
 
 // logout handles user logout
 // @Summary User logout
-// @Description Logs out a user and clears the refresh token cookie
+// @Description Logs out a user: revokes the refresh token family server-side and clears the refresh token cookie
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Success 200 {object} auth.LogoutResponse "User successfully logged out"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
 // @Router /auth/logout [post]
 func (h *Handler) logout(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   config.Envs.APP_ENV == "production",
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
-		Domain:   config.GetCookieDomain(),
-	})
+	clearRefreshCookie(w)
+
+	if cookie, err := r.Cookie("refresh_token"); err == nil {
+		if err := h.deps.AuthService.Logout(cookie.Value); err != nil {
+			utils.JSON(w, http.StatusInternalServerError, "Не удалось завершить сессию: "+err.Error(), nil, h.deps.Logger)
+			return
+		}
+	}
 
 	utils.JSON(w, http.StatusOK, "Успешный выход из системы", nil, h.deps.Logger)
 }
@@ -278,7 +619,7 @@ func (h *Handler) validateToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, _, err := h.deps.JwtService.ValidateAccess(parts[1])
+	_, err := h.deps.JwtService.ValidateAccess(parts[1])
 
 	if err != nil {
 		utils.JSON(w, http.StatusUnauthorized, err.Error(), map[string]bool{"isValid": false}, h.deps.Logger)
@@ -287,3 +628,218 @@ func (h *Handler) validateToken(w http.ResponseWriter, r *http.Request) {
 
 	utils.JSON(w, http.StatusOK, "token is valid", map[string]bool{"isValid": true}, h.deps.Logger)
 }
+
+// listSessions lists the caller's active sessions
+// @Summary List my sessions
+// @Description Lists the caller's logged-in devices: user agent, IP, login time and last token refresh. The session of the current access token is marked as current.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} session.ListResponse "Active sessions"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/sessions [get]
+func (h *Handler) listSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	list, err := h.sessions.List(userID, middleware.GetSessionID(r.Context()))
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Активные сессии", list, h.deps.Logger)
+}
+
+// revokeSession signs out one of the caller's sessions
+// @Summary Sign out a session
+// @Description Ends one of the caller's sessions: its refresh token stops working and its access tokens are rejected. Not available while impersonating a user.
+// @Tags auth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Security BearerAuth
+// @Success 200 {object} auth.LogoutResponse "Session ended"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 404 {object} auth.ErrorResponse "Session not found"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) revokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	if err := h.sessions.Revoke(userID, mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Сессия завершена", nil, h.deps.Logger)
+}
+
+// revokeOtherSessions signs out everywhere except the current session
+// @Summary Sign out everywhere else
+// @Description Ends all of the caller's sessions except the one of the current access token. Not available while impersonating a user.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} session.RevokeAllResponse "IDs of ended sessions"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/sessions [delete]
+func (h *Handler) revokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	// без текущей сессии (старый токен) завершить «все остальные» нельзя —
+	// иначе пользователь выкинет и себя
+	current := middleware.GetSessionID(r.Context())
+	if current == "" {
+		utils.JSON(w, http.StatusUnauthorized, "Войдите заново, чтобы управлять сессиями", nil, h.deps.Logger)
+		return
+	}
+
+	ids, err := h.sessions.RevokeOthers(userID, current)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Остальные сессии завершены", ids, h.deps.Logger)
+}
+
+// passwordError переводит ошибки сброса и смены пароля в HTTP-ответ.
+func (h *Handler) passwordError(w http.ResponseWriter, err error) {
+	var policy *user.PolicyError
+
+	switch {
+	case errors.As(err, &policy):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), policy.Violations, h.deps.Logger)
+	case errors.Is(err, user.ErrResetTokenInvalid),
+		errors.Is(err, user.ErrWrongPassword),
+		errors.Is(err, user.ErrSamePassword):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+	default:
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+	}
+}
+
+// forgotPassword sends a password reset link
+// @Summary Request a password reset
+// @Description Emails a single-use, time-limited password reset link (PASSWORD_RESET_TTL). The response is the same whether or not the email is registered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body user.ForgotPasswordRequest true "Email"
+// @Success 200 {object} user.PasswordResponse "Reset link sent if the email is registered"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/password/forgot [post]
+func (h *Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req user.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	token, err := h.passwords.Forgot(req)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	if token != "" {
+		resetLink := fmt.Sprintf("%s/reset-password?token=%s", config.Envs.FRONT_URI, token)
+		if err := pkg.SendPasswordReset(req.Email, resetLink, config.Envs.PASSWORD_RESET_TTL); err != nil {
+			utils.JSON(w, http.StatusInternalServerError, "Не удалось отправить письмо: "+err.Error(), nil, h.deps.Logger)
+			return
+		}
+	}
+
+	utils.JSON(w, http.StatusOK, "Если этот email зарегистрирован, на него отправлена ссылка для сброса пароля", nil, h.deps.Logger)
+}
+
+// resetPassword sets a new password by reset token
+// @Summary Reset password
+// @Description Sets a new password using the token from the reset link. The token works once; all sessions of the user are ended.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body user.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} user.PasswordResponse "Password changed"
+// @Failure 400 {object} auth.ErrorResponse "Invalid or expired token, or password violates the policy"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/password/reset [post]
+func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var req user.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	if err := h.passwords.Reset(req); err != nil {
+		h.passwordError(w, err)
+		return
+	}
+
+	clearRefreshCookie(w)
+	utils.JSON(w, http.StatusOK, "Пароль изменён, войдите с новым паролем", nil, h.deps.Logger)
+}
+
+// changePassword changes the caller's password
+// @Summary Change password
+// @Description Changes the caller's password. Requires the current password; all other sessions are ended. Not available while impersonating a user.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} user.PasswordResponse "Password changed"
+// @Failure 400 {object} auth.ErrorResponse "Wrong current password or password violates the policy"
+// @Failure 401 {object} auth.ErrorResponse "Unauthorized"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/password/change [post]
+func (h *Handler) changePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	var req user.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	if err := h.passwords.Change(userID, middleware.GetSessionID(r.Context()), req); err != nil {
+		h.passwordError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Пароль изменён, остальные сессии завершены", nil, h.deps.Logger)
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"test-project/config"
+	"test-project/internal/domain/auth"
+	"test-project/internal/usecase"
+	"test-project/pkg"
+	"test-project/pkg/oidc"
+	"test-project/utils"
+
+	"go.uber.org/zap"
+)
+
+// magicNonceCookie привязывает ссылку для входа к браузеру, где её запросили:
+// без куки ссылку из перехваченного письма не открыть.
+const magicNonceCookie = "magic_nonce"
+
+func setMagicNonceCookie(w http.ResponseWriter, nonce string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicNonceCookie,
+		Value:    nonce,
+		Path:     "/api/v1/auth/magic-link",
+		HttpOnly: true,
+		Secure:   config.Envs.APP_ENV == "production",
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+		Domain:   config.GetCookieDomain(),
+	})
+}
+
+// magicLink emails a sign-in link
+// @Summary Request a magic sign-in link
+// @Description Emails a single-use, time-limited sign-in link (MAGIC_LINK_TTL) and sets an httpOnly magic_nonce cookie: the link works only in the browser that requested it. A new request replaces the cookie, so only the latest link works. The response is the same whether or not the email is registered. Rate limited per IP and per email.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.MagicLinkRequest true "Email"
+// @Success 200 {object} auth.MagicLinkResponse "Link sent if the email is registered"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
+// @Failure 500 {object} auth.ErrorResponse "Internal server error"
+// @Router /auth/magic-link [post]
+func (h *Handler) magicLink(w http.ResponseWriter, r *http.Request) {
+	var req auth.MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	token, err := h.deps.AuthService.MagicLink(req.Email, nonce)
+	if err != nil {
+		h.deps.Logger.Error("не удалось выдать ссылку для входа", zap.Error(err))
+		utils.JSON(w, http.StatusInternalServerError, "Не удалось выдать ссылку для входа", nil, h.deps.Logger)
+		return
+	}
+
+	if token != "" {
+		loginLink := fmt.Sprintf("%s/login/magic?token=%s", config.Envs.FRONT_URI, token)
+		if err := pkg.SendMagicLink(req.Email, loginLink, config.Envs.MAGIC_LINK_TTL); err != nil {
+			utils.JSON(w, http.StatusInternalServerError, "Не удалось отправить письмо: "+err.Error(), nil, h.deps.Logger)
+			return
+		}
+	}
+
+	// кука ставится и для незарегистрированного email, чтобы ответы не различались
+	setMagicNonceCookie(w, nonce, int(config.Envs.MAGIC_LINK_TTL.Seconds()))
+	utils.JSON(w, http.StatusOK, "Если этот email зарегистрирован, на него отправлена ссылка для входа", nil, h.deps.Logger)
+}
+
+// verifyMagicLink signs in by a magic link
+// @Summary Sign in with a magic link
+// @Description Exchanges the token from the emailed link for access and refresh tokens. Must be called from the browser that requested the link (magic_nonce cookie). The link works once; opening it in another browser does not use it up. As with /auth/login, users with two-factor authentication get an MFA token and the next step instead.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.MagicLinkVerifyRequest true "Token from the link"
+// @Success 200 {object} auth.LoginResponse "User successfully authenticated"
+// @Success 202 {object} mfa.ChallengeResponse "Second factor required"
+// @Failure 400 {object} auth.ErrorResponse "Invalid input"
+// @Failure 401 {object} auth.ErrorResponse "Link is invalid, expired, already used or opened in another browser"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
+// @Router /auth/magic-link/verify [post]
+func (h *Handler) verifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req auth.MagicLinkVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Некорректные данные", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	nonce := ""
+	if cookie, err := r.Cookie(magicNonceCookie); err == nil {
+		nonce = cookie.Value
+	}
+
+	res, err := h.deps.AuthService.LoginMagicLink(req.Token, nonce, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		if errors.Is(err, usecase.ErrMagicLink) {
+			utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		h.deps.Logger.Error("ошибка входа по ссылке", zap.Error(err))
+		utils.JSON(w, http.StatusInternalServerError, "Не удалось войти по ссылке", nil, h.deps.Logger)
+		return
+	}
+
+	setMagicNonceCookie(w, "", -1)
+	h.firstFactorResult(w, res)
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"test-project/config"
+	"test-project/internal/domain/auth"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/pkg/oidc"
+	"test-project/utils"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// oidcStateCookie хранит state начатого входа: ответ провайдера
+// принимается только в том браузере, где вход начали.
+const oidcStateCookie = "oidc_state"
+
+func setOIDCStateCookie(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/v1/auth/oidc",
+		HttpOnly: true,
+		Secure:   config.Envs.APP_ENV == "production",
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+		Domain:   config.GetCookieDomain(),
+	})
+}
+
+type oidcHandler struct {
+	uc         usecase.OIDCUsecase
+	deps       *auth.Deps
+	successURL string
+}
+
+// registerOIDCRoutes включает вход через провайдера OpenID Connect, если
+// задан OIDC_ISSUER.
+func registerOIDCRoutes(r *mux.Router, deps *auth.Deps, limit func(http.Handler) http.Handler) {
+	if config.Envs.OIDC_ISSUER == "" {
+		return
+	}
+
+	groupRoles, err := usecase.ParseGroupRoles(config.Envs.OIDC_GROUP_ROLES)
+	if err != nil {
+		log.Fatal("Ошибка в OIDC_GROUP_ROLES: ", err)
+	}
+	provider := &oidc.Provider{
+		Issuer:       config.Envs.OIDC_ISSUER,
+		ClientID:     config.Envs.OIDC_CLIENT_ID,
+		ClientSecret: config.Envs.OIDC_CLIENT_SECRET,
+		RedirectURL:  config.Envs.OIDC_REDIRECT_URL,
+		Scopes:       strings.Fields(config.Envs.OIDC_SCOPES),
+		HTTP:         &http.Client{Timeout: 10 * time.Second},
+	}
+	h := &oidcHandler{
+		uc:         usecase.NewOIDCUsecase(provider, deps.AuthService, deps.Redis, config.Envs.OIDC_GROUPS_CLAIM, groupRoles),
+		deps:       deps,
+		successURL: config.Envs.OIDC_SUCCESS_URL,
+	}
+
+	r.Handle("/auth/oidc/login", middleware.Public(limit(http.HandlerFunc(h.login)))).Methods(http.MethodGet)
+	r.Handle("/auth/oidc/callback", middleware.Public(limit(http.HandlerFunc(h.callback)))).Methods(http.MethodGet)
+}
+
+// login starts the OIDC sign-in
+// @Summary Sign in with the corporate identity provider
+// @Description Redirects the browser to the OpenID Connect provider (authorization code flow with PKCE) and sets an httpOnly oidc_state cookie: the callback is accepted only in the same browser. Available when OIDC_ISSUER is configured.
+// @Tags auth
+// @Success 302 "Redirect to the provider"
+// @Failure 429 {object} auth.ErrorResponse "Too many attempts; see Retry-After"
+// @Failure 502 {object} auth.ErrorResponse "Provider unavailable"
+// @Router /auth/oidc/login [get]
+func (h *oidcHandler) login(w http.ResponseWriter, r *http.Request) {
+	target, state, err := h.uc.Start(r.Context())
+	if err != nil {
+		h.deps.Logger.Error("не удалось начать вход через OIDC", zap.Error(err))
+		utils.JSON(w, http.StatusBadGateway, "Провайдер входа недоступен", nil, h.deps.Logger)
+		return
+	}
+
+	setOIDCStateCookie(w, state, int(usecase.OIDCStateTTL.Seconds()))
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// callback finishes the OIDC sign-in
+// @Summary OIDC callback
+// @Description Redirect target registered at the provider. Rejected unless the state matches the oidc_state cookie set by /auth/oidc/login. Exchanges the code, verifies the ID token and signs in the user with the provider's email: an existing account, or a new one created from a pending invitation. With OIDC_GROUP_ROLES set, the role is taken from the provider's groups on every sign-in. On success sets the refresh token cookie and redirects to OIDC_SUCCESS_URL, where the client obtains an access token via /auth/refresh. If the user has two-factor authentication enabled (or it is mandatory for the role), redirects there with mfa_token and mfa_step query parameters instead, to be finished as after /auth/login. On failure redirects there with an oidc_error query parameter.
+// @Tags auth
+// @Param code query string false "Authorization code"
+// @Param state query string true "State from /auth/oidc/login"
+// @Param error query string false "Error reported by the provider"
+// @Success 302 "Redirect to the frontend"
+// @Router /auth/oidc/callback [get]
+func (h *oidcHandler) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	browserState := ""
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		browserState = cookie.Value
+	}
+	setOIDCStateCookie(w, "", -1)
+
+	if e := q.Get("error"); e != "" {
+		h.fail(w, r, "Провайдер отказал во входе: "+e)
+		return
+	}
+
+	res, err := h.uc.Finish(r.Context(), q.Get("code"), q.Get("state"), browserState, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrOIDCState),
+			errors.Is(err, usecase.ErrOIDCEmail),
+			errors.Is(err, usecase.ErrOIDCNoRole),
+			errors.Is(err, usecase.ErrNoAccount):
+			h.fail(w, r, err.Error())
+		case errors.Is(err, oidc.ErrInvalidToken):
+			h.deps.Logger.Warn("ID-токен провайдера отклонён", zap.Error(err))
+			h.fail(w, r, oidc.ErrInvalidToken.Error())
+		default:
+			h.deps.Logger.Error("ошибка входа через OIDC", zap.Error(err))
+			h.fail(w, r, "Не удалось войти через провайдера")
+		}
+		return
+	}
+
+	if res.MFAToken != "" {
+		// второй фактор вводится на фронтенде, как после пароля
+		h.redirect(w, r, url.Values{"mfa_token": {res.MFAToken}, "mfa_step": {res.MFAStep}})
+		return
+	}
+
+	setRefreshCookie(w, res.RefreshToken)
+	http.Redirect(w, r, h.successURL, http.StatusFound)
+}
+
+// fail возвращает браузер на фронтенд с текстом ошибки.
+func (h *oidcHandler) fail(w http.ResponseWriter, r *http.Request, msg string) {
+	h.redirect(w, r, url.Values{"oidc_error": {msg}})
+}
+
+// redirect возвращает браузер на фронтенд с параметрами params.
+func (h *oidcHandler) redirect(w http.ResponseWriter, r *http.Request, params url.Values) {
+	target, err := url.Parse(h.successURL)
+	if err != nil {
+		h.deps.Logger.Error("некорректный OIDC_SUCCESS_URL", zap.Error(err))
+		utils.JSON(w, http.StatusInternalServerError, "Некорректный OIDC_SUCCESS_URL", nil, h.deps.Logger)
+		return
+	}
+	q := target.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	target.RawQuery = q.Encode()
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
@@ -1,12 +1,17 @@
 package cargo
 
 import (
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
+	"test-project/config"
 	"test-project/internal/domain/auth"
 	cargoDomain "test-project/internal/domain/cargo"
-	"test-project/internal/domain/user"
+	counterpartyDomain "test-project/internal/domain/counterparty"
+	driverDomain "test-project/internal/domain/driver"
+	"test-project/internal/domain/permission"
 	"test-project/internal/middleware"
 	"test-project/internal/usecase"
 	"test-project/internal/validator"
@@ -36,14 +41,26 @@ func RegisterCargoRoute(r *mux.Router, deps *auth.Deps) {
 	}
 
 	cargoRepo := cargoDomain.NewPostgresCargoRepo(deps.DB)
-	svc := usecase.NewCargoUsecase(cargoRepo, v)
+	driverRepo := driverDomain.NewPostgresDriverRepo(deps.DB)
+	counterpartyRepo := counterpartyDomain.NewPostgresCounterpartyRepo(deps.DB)
+	conflictMode := cargoDomain.ParseConflictMode(config.Envs.SCHEDULE_CONFLICT_MODE)
+	svc := usecase.NewCargoUsecase(cargoRepo, driverRepo, counterpartyRepo, deps.FileService, v, conflictMode)
 	h := NewHandler(svc, deps, v)
 
-	r.Handle("/cargo", middleware.JwtMiddleware(deps, h.Create)).Methods(http.MethodPost)
-	r.Handle("/cargo", middleware.JwtMiddleware(deps, h.GET)).Methods(http.MethodGet)
-	r.Handle("/cargo/{id}", middleware.JwtMiddleware(deps, h.PATH)).Methods(http.MethodPatch)
-	r.Handle("/cargo/{id}", middleware.JwtMiddleware(deps, h.GETByID)).Methods(http.MethodGet)
-	r.Handle("/cargo/{id}", middleware.JwtMiddleware(deps, h.DELETE)).Methods(http.MethodDelete)
+	r.Handle("/cargo", middleware.Require(deps, permission.CargoCreate, h.Create)).Methods(http.MethodPost)
+	r.Handle("/cargo", middleware.Require(deps, permission.CargoView, h.GET)).Methods(http.MethodGet)
+	r.Handle("/cargo/{id}", middleware.Require(deps, permission.CargoUpdate, h.PATH)).Methods(http.MethodPatch)
+	r.Handle("/cargo/{id}", middleware.Require(deps, permission.CargoView, h.GETByID)).Methods(http.MethodGet)
+	r.Handle("/cargo/{id}", middleware.Require(deps, permission.CargoDelete, h.DELETE)).Methods(http.MethodDelete)
+	r.Handle("/cargo/{id}/transition", middleware.Require(deps, permission.CargoTransition, h.Transition)).Methods(http.MethodPost)
+	r.Handle("/cargo/{id}/transitions", middleware.Require(deps, permission.CargoView, h.Transitions)).Methods(http.MethodGet)
+	r.Handle("/cargo/{id}/history", middleware.Require(deps, permission.CargoView, h.History)).Methods(http.MethodGet)
+	r.Handle("/cargo/{id}/revert/{revisionId}", middleware.Require(deps, permission.CargoUpdate, h.Revert)).Methods(http.MethodPost)
+	r.Handle("/cargo/{id}/stops", middleware.Require(deps, permission.CargoView, h.Stops)).Methods(http.MethodGet)
+	r.Handle("/cargo/{id}/stops", middleware.Require(deps, permission.CargoUpdate, h.AddStop)).Methods(http.MethodPost)
+	r.Handle("/cargo/{id}/stops/order", middleware.Require(deps, permission.CargoUpdate, h.ReorderStops)).Methods(http.MethodPut)
+	r.Handle("/cargo/{id}/stops/{stopId}/complete", middleware.Require(deps, permission.CargoTransition, h.CompleteStop)).Methods(http.MethodPost)
+	r.Handle("/cargo/{id}/stops/{stopId}", middleware.Require(deps, permission.CargoUpdate, h.DeleteStop)).Methods(http.MethodDelete)
 }
 
 // Create handles the creation of a new cargo via form-data
@@ -56,32 +73,31 @@ func RegisterCargoRoute(r *mux.Router, deps *auth.Deps) {
 // @Param cargoNumber        formData string  true  "Номер груза"
 // @Param date               formData string  false "Дата (RFC3339), например 2025-04-30T08:00:00Z"
 // @Param loadUnloadDate     formData string  false "Дата погрузки/разгрузки (2025-04-30T08:00:00Z)"
-// @Param driver             formData string  true  "Водитель"
+// @Param plannedStart       formData string  false "Плановое начало перевозки (RFC3339)"
+// @Param plannedEnd         formData string  false "Плановое окончание перевозки (RFC3339)"
+// @Param driverId           formData string  true  "ID водителя (GET /drivers)"
 // @Param transportationInfo formData string  true  "Информация о перевозке"
 // @Param payoutAmount       formData number  false "Сумма выплаты, например 12345.67"
 // @Param payoutDate         formData string  false "Дата выплаты (RFC3339)"
 // @Param paymentStatus      formData string  false "Статус оплаты"
 // @Param payoutTerms        formData string  false "Условия выплаты"
+// @Param weightKg           formData number  false "Вес груза, кг (не больше грузоподъёмности машины)"
+// @Param volumeM3           formData number  false "Объём груза, м³ (не больше объёма кузова машины)"
+// @Param pallets            formData integer false "Количество паллет"
+// @Param hazardClass        formData string  false "Класс опасности ДОПОГ" Enums(1, 2, 3, 4.1, 4.2, 4.3, 5.1, 5.2, 6.1, 6.2, 7, 8, 9)
 // @Param truckId            formData string  true  "ID машины (c8169351-f6d8-4058-af4a-8ead3363fd92)"
+// @Param customerId         formData string  false "ID заказчика (GET /counterparties)"
+// @Param shipperId          formData string  false "ID грузоотправителя (GET /counterparties)"
+// @Param consigneeId        formData string  false "ID грузополучателя (GET /counterparties)"
 // @Param photos             formData file    false "Фотографии груза (можно выбрать несколько файлов)"
 // @Success 201 {object} cargo.CreateResponse "Груз успешно создан"
 // @Failure 400 {object} cargo.ErrorResponse  "Ошибки валидации или неверный формат данных"
+// @Failure 409 {object} cargo.ConflictResponse "Машина занята в это время (SCHEDULE_CONFLICT_MODE=reject)"
 // @Failure 500 {object} cargo.ErrorResponse  "Внутренняя ошибка сервера"
 // @Router /cargo [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var ctx = r.Context()
 
-	role, err := middleware.GetUserRole(ctx)
-
-	if err != nil {
-		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
-		return
-	}
-
-	if role != user.RoleSuperAdmin && role != user.RoleEditor {
-		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав. Суперадминистраторы и Редакторы могут создавать груз", nil, h.deps.Logger)
-		return
-	}
 	var c cargoDomain.Cargo
 	if err := utils.ParseFormData(r, &c); err != nil {
 		utils.JSON(w, http.StatusBadRequest, "Не удалось распарсить форму: "+err.Error(), nil, h.deps.Logger)
@@ -101,26 +117,74 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	files := r.MultipartForm.File["photos"]
 
-	created, err := h.uc.CreateCargo(c)
+	actorID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	created, err := h.uc.CreateCargo(c, actorID)
 
 	if err != nil {
-		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		h.saveError(w, err)
 		return
 	}
 
-	if len(files) > 0 {
-		if err := h.deps.FileService.UploadMany(ctx, "cargos", created.ID, files); err != nil {
-			utils.JSON(w, http.StatusInternalServerError, "upload files: "+err.Error(), nil, h.deps.Logger)
-			return
-		}
+	if err := h.uc.AttachPhotos(ctx, created.ID, actorID, files); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, "upload files: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	if !finance(r) {
+		created.HideFinance()
+	}
+
+	utils.JSON(w, http.StatusCreated, withConflictWarning("Груз успешно создан", created), created, h.deps.Logger)
+}
+
+// saveError переводит ошибки создания и изменения груза в HTTP-ответ.
+func (h *Handler) saveError(w http.ResponseWriter, err error) {
+	var (
+		conflict *cargoDomain.ScheduleConflictError
+		capacity *cargoDomain.CapacityError
+	)
+
+	switch {
+	case errors.As(err, &conflict):
+		utils.JSON(w, http.StatusConflict, err.Error(), conflict.Conflicts, h.deps.Logger)
+	case errors.As(err, &capacity):
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+err.Error(), capacity.Excess, h.deps.Logger)
+	case errors.Is(err, cargoDomain.ErrNotFound):
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, cargoDomain.ErrStatusNotPatchable),
+		errors.Is(err, cargoDomain.ErrScheduleWindow),
+		errors.Is(err, cargoDomain.ErrTruckNotFound),
+		errors.Is(err, driverDomain.ErrNotFound),
+		errors.Is(err, driverDomain.ErrDismissed),
+		errors.Is(err, counterpartyDomain.ErrNotFound):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+	default:
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
 	}
+}
+
+// finance сообщает, что пользователь запроса видит выплаты по грузам.
+func finance(r *http.Request) bool {
+	return middleware.HasPermission(r.Context(), permission.FinanceView)
+}
 
-	utils.JSON(w, http.StatusCreated, "Груз успешно создан", created, h.deps.Logger)
+// withConflictWarning дополняет сообщение предупреждением о пересечении
+// расписания (режим SCHEDULE_CONFLICT_MODE=warn).
+func withConflictWarning(message string, c cargoDomain.Cargo) string {
+	if len(c.ScheduleConflicts) == 0 {
+		return message
+	}
+	return message + ". Внимание: " + (&cargoDomain.ScheduleConflictError{Conflicts: c.ScheduleConflicts}).Error()
 }
 
 // PATH updates a cargo by ID
 // @Summary Update a cargo by ID
-// @Description Updates a cargo by its ID
+// @Description Updates a cargo by its ID. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
 // @Tags cargo
 // @Accept multipart/form-data
 // @Produce json
@@ -129,36 +193,34 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 // @Param cargoNumber        formData string  false  "Номер груза"
 // @Param date               formData string  false "Дата (RFC3339), например 2025-04-30T08:00:00Z"
 // @Param loadUnloadDate     formData string  false "Дата погрузки/разгрузки (2025-04-30T08:00:00Z)"
-// @Param driver             formData string  false  "Водитель"
+// @Param plannedStart       formData string  false "Плановое начало перевозки (RFC3339)"
+// @Param plannedEnd         formData string  false "Плановое окончание перевозки (RFC3339)"
+// @Param driverId           formData string  false  "ID водителя (GET /drivers)"
 // @Param transportationInfo formData string  false  "Информация о перевозке"
 // @Param payoutAmount       formData number  false "Сумма выплаты, например 12345.67"
 // @Param payoutDate         formData string  false "Дата выплаты (RFC3339)"
 // @Param paymentStatus      formData string  false "Статус оплаты"
 // @Param payoutTerms        formData string  false "Условия выплаты"
+// @Param weightKg           formData number  false "Вес груза, кг (не больше грузоподъёмности машины)"
+// @Param volumeM3           formData number  false "Объём груза, м³ (не больше объёма кузова машины)"
+// @Param pallets            formData integer false "Количество паллет"
+// @Param hazardClass        formData string  false "Класс опасности ДОПОГ" Enums(1, 2, 3, 4.1, 4.2, 4.3, 5.1, 5.2, 6.1, 6.2, 7, 8, 9)
 // @Param truckId            formData string  false  "ID машины (c8169351-f6d8-4058-af4a-8ead3363fd92)"
+// @Param customerId         formData string  false "ID заказчика (GET /counterparties)"
+// @Param shipperId          formData string  false "ID грузоотправителя (GET /counterparties)"
+// @Param consigneeId        formData string  false "ID грузополучателя (GET /counterparties)"
 // @Param photos             formData file    false "Фотографии груза (можно выбрать несколько файлов)"
 // @Success 200 {object} cargo.GetResponse "Cargo updated"
 // @Failure 400 {object} cargo.ErrorResponse "Invalid ID"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo not found"
+// @Failure 409 {object} cargo.ConflictResponse "Truck is busy at that time (SCHEDULE_CONFLICT_MODE=reject)"
 // @Failure 500 {object} cargo.ErrorResponse "Internal server error"
 // @Router /cargo/{id} [patch]
 func (h *Handler) PATH(w http.ResponseWriter, r *http.Request) {
 	// 0. контекст запроса
 	ctx := r.Context()
 
-	// 1. авторизация
-	role, err := middleware.GetUserRole(ctx)
-	if err != nil {
-		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
-		return
-	}
-	if role != user.RoleSuperAdmin && role != user.RoleEditor {
-		utils.JSON(w, http.StatusUnauthorized,
-			"Недостаточно прав. Суперадминистраторы и Редакторы могут обновлять груз",
-			nil, h.deps.Logger)
-		return
-	}
-
-	// 2. парсим id и форму
+	// 1. парсим id и форму
 	id := mux.Vars(r)["id"]
 
 	var updateCargo cargoDomain.UpdateCargoInput
@@ -171,7 +233,7 @@ func (h *Handler) PATH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. вытаскиваем файлы и deletedIds
+	// 2. вытаскиваем файлы и deletedIds
 	if err := r.ParseMultipartForm(32 << 20); err != nil { // 32 МБ
 		utils.JSON(w, http.StatusBadRequest, "multipart parse: "+err.Error(), nil, h.deps.Logger)
 		return
@@ -180,54 +242,106 @@ func (h *Handler) PATH(w http.ResponseWriter, r *http.Request) {
 	deletedIDs := r.MultipartForm.Value["deletedIds"]
 	files := r.MultipartForm.File["photos"]
 
-	// 4. работаем с файловым сервисом
-	if len(deletedIDs) > 0 {
-		if err := h.deps.FileService.DeleteMany(ctx, deletedIDs); err != nil {
-			utils.JSON(w, http.StatusInternalServerError, "delete files: "+err.Error(), nil, h.deps.Logger)
-			return
-		}
+	actorID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
 	}
-	if len(files) > 0 {
-		if err := h.deps.FileService.UploadMany(ctx, "cargos", id, files); err != nil {
-			utils.JSON(w, http.StatusInternalServerError, "upload files: "+err.Error(), nil, h.deps.Logger)
-			return
-		}
+
+	// 3. обновляем сам груз, файлы трогаем только после успешного обновления
+	patched, err := h.uc.PatchCargo(updateCargo, id, actorID, middleware.CargoScope(ctx))
+	if err != nil {
+		h.saveError(w, err)
+		return
 	}
 
-	// 5. обновляем сам груз
-	cargo, err := h.uc.PatchCargo(updateCargo, id)
+	// 4. работаем с фотографиями
+	if err := h.uc.DeletePhotos(ctx, id, actorID, deletedIDs); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, "delete files: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+	if err := h.uc.AttachPhotos(ctx, id, actorID, files); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, "upload files: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	cargo, err := h.uc.GetCargo(id, middleware.CargoScope(ctx))
 	if err != nil {
 		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
 		return
 	}
+	cargo.ScheduleConflicts = patched.ScheduleConflicts
+	if !finance(r) {
+		cargo.HideFinance()
+	}
 
-	utils.JSON(w, http.StatusOK, "Груз успешно обновлён", cargo, h.deps.Logger)
+	utils.JSON(w, http.StatusOK, withConflictWarning("Груз успешно обновлён", cargo), cargo, h.deps.Logger)
 }
 
-// GET retrieves a list of all cargos
-// @Summary List all cargos
-// @Description Retrieves a list of all cargos in the system
+// GET retrieves a filtered, sorted and paginated list of cargos
+// @Summary List cargos
+// @Description Retrieves cargos with filters, sorting and cursor-based pagination. Payout fields, filters and sorting require finance:view. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
 // @Tags cargo
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param truckId            query string false "ID машины"
+// @Param driverId           query string false "ID водителя"
+// @Param driver             query string false "ФИО водителя (поиск по подстроке)"
+// @Param customerId         query string false "ID заказчика"
+// @Param shipperId          query string false "ID грузоотправителя"
+// @Param consigneeId        query string false "ID грузополучателя"
+// @Param paymentStatus      query string false "Статус оплаты"
+// @Param status             query string false "Статус груза" Enums(draft, planned, loading, in_transit, delivered, invoiced, paid, closed, cancelled)
+// @Param dateFrom           query string false "Дата от (RFC3339)"
+// @Param dateTo             query string false "Дата до (RFC3339)"
+// @Param loadUnloadDateFrom query string false "Дата погрузки/разгрузки от (RFC3339)"
+// @Param loadUnloadDateTo   query string false "Дата погрузки/разгрузки до (RFC3339)"
+// @Param payoutDateFrom     query string false "Дата выплаты от (RFC3339)"
+// @Param payoutDateTo       query string false "Дата выплаты до (RFC3339)"
+// @Param payoutAmountMin    query number false "Минимальная сумма выплаты"
+// @Param payoutAmountMax    query number false "Максимальная сумма выплаты"
+// @Param sort               query string false "Поле сортировки" Enums(createdAt, date, loadUnloadDate, payoutDate, payoutAmount, cargoNumber)
+// @Param order              query string false "Направление сортировки" Enums(asc, desc)
+// @Param cursor             query string false "Курсор следующей страницы (nextCursor из предыдущего ответа)"
+// @Param limit              query int    false "Размер страницы (по умолчанию 20, максимум 100)"
 // @Success 200 {object} cargo.ListResponse "List of cargos"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid filter"
 // @Failure 500 {object} cargo.ErrorResponse "Internal server error"
 // @Router /cargo [get]
 func (h *Handler) GET(w http.ResponseWriter, r *http.Request) {
-	cargos, err := h.uc.ListGargos()
+	filter, err := cargoDomain.ParseListFilter(r.URL.Query())
+	if err != nil {
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		return
+	}
+	if !finance(r) && filter.UsesFinance() {
+		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав: фильтры и сортировка по выплатам требуют разрешения "+string(permission.FinanceView), nil, h.deps.Logger)
+		return
+	}
+	filter.Scope = middleware.CargoScope(r.Context())
+
+	cargos, err := h.uc.ListGargos(filter)
 
 	if err != nil {
+		if errors.Is(err, cargoDomain.ErrInvalidFilter) {
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+			return
+		}
 		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
+	if !finance(r) {
+		cargos.HideFinance()
+	}
+
 	utils.JSON(w, http.StatusOK, "Список всех грузов", cargos, h.deps.Logger)
 }
 
 // GETByID retrieves a cargo by ID
 // @Summary Get a cargo by ID
-// @Description Retrieves a cargo by its ID
+// @Description Retrieves a cargo by its ID. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
 // @Tags cargo
 // @Accept json
 // @Produce json
@@ -240,13 +354,17 @@ func (h *Handler) GET(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GETByID(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
-	cargo, err := h.uc.GetCargo(id)
+	cargo, err := h.uc.GetCargo(id, middleware.CargoScope(r.Context()))
 
 	if err != nil {
 		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
+	if !finance(r) {
+		cargo.HideFinance()
+	}
+
 	utils.JSON(w, http.StatusOK, "Данные о грузе", cargo, h.deps.Logger)
 }
 
@@ -260,29 +378,400 @@ func (h *Handler) GETByID(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Success 200 {object} cargo.DeleteResponse "Cargo deleted"
 // @Failure 400 {object} cargo.ErrorResponse "Invalid ID"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo not found or already deleted"
 // @Failure 500 {object} cargo.ErrorResponse "Internal server error"
 // @Router /cargo/{id} [delete]
 func (h *Handler) DELETE(w http.ResponseWriter, r *http.Request) {
-	role, err := middleware.GetUserRole(r.Context())
+	actorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	err = h.uc.DeleteCargo(id, actorID)
 
+	if err != nil {
+		if errors.Is(err, cargoDomain.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Груз с id= "+id+" успешно удален", nil, h.deps.Logger)
+}
+
+// Transition moves a cargo to the next lifecycle status
+// @Summary Change cargo status
+// @Description Moves a cargo to another lifecycle status (draft → planned → loading → in_transit → delivered → invoiced → paid → closed, or cancelled). Only legal transitions allowed for the caller's role are accepted. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Param transition body cargo.TransitionRequest true "Target status and optional comment"
+// @Security BearerAuth
+// @Success 200 {object} cargo.TransitionResponse "Status changed"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid input"
+// @Failure 401 {object} cargo.ErrorResponse "Transition is not allowed for the role"
+// @Failure 409 {object} cargo.ErrorResponse "Illegal transition"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /cargo/{id}/transition [post]
+func (h *Handler) Transition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := middleware.GetUserRole(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+	userID, err := middleware.GetUserID(ctx)
 	if err != nil {
 		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
-	if role != user.RoleSuperAdmin && role != user.RoleEditor {
-		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав. Суперадминистраторы и Редакторы могут удалять груз", nil, h.deps.Logger)
+	var req cargoDomain.TransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
 		return
 	}
 
 	id := mux.Vars(r)["id"]
 
-	err = h.uc.DeleteCargo(id)
+	cargo, err := h.uc.TransitionCargo(id, req, userID, role, middleware.CargoScope(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, cargoDomain.ErrUnknownStatus):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, cargoDomain.ErrTransitionForbidden):
+			utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, cargoDomain.ErrIllegalTransition), errors.Is(err, cargoDomain.ErrStatusConflict):
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	if !finance(r) {
+		cargo.HideFinance()
+	}
+
+	utils.JSON(w, http.StatusOK, "Статус груза изменён", cargo, h.deps.Logger)
+}
+
+// Transitions retrieves the status history of a cargo
+// @Summary Cargo status history
+// @Description Retrieves all status transitions of a cargo with author, time and comment. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Security BearerAuth
+// @Success 200 {object} cargo.TransitionListResponse "Status transitions"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo not found"
+// @Router /cargo/{id}/transitions [get]
+func (h *Handler) Transitions(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	list, err := h.uc.ListTransitions(id, middleware.CargoScope(r.Context()))
+	if err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "История статусов груза", list, h.deps.Logger)
+}
+
+// History retrieves the change history of a cargo
+// @Summary Cargo change history
+// @Description Retrieves all revisions of a cargo (create, patch, status transitions, photo changes, delete, revert) with field-level diff, author and time. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Security BearerAuth
+// @Success 200 {object} cargo.HistoryResponse "Cargo revisions, newest first"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /cargo/{id}/history [get]
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
 
+	revisions, err := h.uc.History(id, middleware.CargoScope(r.Context()))
 	if err != nil {
 		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
-	utils.JSON(w, http.StatusOK, "Груз с id= "+id+" успешно удален", nil, h.deps.Logger)
+	if !finance(r) {
+		for i := range revisions {
+			revisions[i].HideFinance()
+		}
+	}
+
+	utils.JSON(w, http.StatusOK, "История изменений груза", revisions, h.deps.Logger)
+}
+
+// Revert restores a cargo to the state of a revision
+// @Summary Revert a cargo to a revision
+// @Description Restores cargo fields from the snapshot of the given revision. Status and photos are not restored. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Param revisionId path string true "Revision ID"
+// @Security BearerAuth
+// @Success 200 {object} cargo.GetResponse "Cargo reverted"
+// @Failure 400 {object} cargo.ErrorResponse "Revision cannot be reverted"
+// @Failure 401 {object} cargo.ErrorResponse "Unauthorized"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo or revision not found"
+// @Failure 409 {object} cargo.ConflictResponse "Truck is busy at the restored time (SCHEDULE_CONFLICT_MODE=reject)"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /cargo/{id}/revert/{revisionId} [post]
+func (h *Handler) Revert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actorID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	cargo, err := h.uc.Revert(vars["id"], vars["revisionId"], actorID, middleware.CargoScope(ctx))
+	if err != nil {
+		var (
+			conflict *cargoDomain.ScheduleConflictError
+			capacity *cargoDomain.CapacityError
+		)
+
+		switch {
+		case errors.As(err, &conflict):
+			utils.JSON(w, http.StatusConflict, err.Error(), conflict.Conflicts, h.deps.Logger)
+		case errors.As(err, &capacity):
+			utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+err.Error(), capacity.Excess, h.deps.Logger)
+		case errors.Is(err, cargoDomain.ErrRevisionNotFound),
+			errors.Is(err, cargoDomain.ErrNotFound):
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, cargoDomain.ErrRevisionNotRevertable),
+			errors.Is(err, cargoDomain.ErrTruckNotFound):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	if !finance(r) {
+		cargo.HideFinance()
+	}
+
+	utils.JSON(w, http.StatusOK, withConflictWarning("Груз восстановлен из ревизии", cargo), cargo, h.deps.Logger)
+}
+
+// stopError переводит ошибки работы с маршрутом в HTTP-ответ.
+func (h *Handler) stopError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, cargoDomain.ErrStopWindow),
+		errors.Is(err, cargoDomain.ErrStopTimes),
+		errors.Is(err, cargoDomain.ErrStopOrderMismatch):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, cargoDomain.ErrStopNotFound),
+		errors.Is(err, cargoDomain.ErrNotFound):
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, cargoDomain.ErrStopCompleted):
+		utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+	default:
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+	}
+}
+
+// Stops returns the route of a cargo
+// @Summary Get cargo route
+// @Description Returns the ordered list of pickup and delivery stops of a cargo. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Security BearerAuth
+// @Success 200 {object} cargo.StopsResponse "Cargo route"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /cargo/{id}/stops [get]
+func (h *Handler) Stops(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	stops, err := h.uc.ListStops(id, middleware.CargoScope(r.Context()))
+	if err != nil {
+		h.stopError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Маршрут груза", stops, h.deps.Logger)
+}
+
+// AddStop adds a stop to the route of a cargo
+// @Summary Add cargo stop
+// @Description Adds a load or unload stop. Without position the stop is appended to the end of the route. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Param stop body cargo.AddStopRequest true "Stop"
+// @Security BearerAuth
+// @Success 201 {object} cargo.StopsResponse "Updated route"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid input"
+// @Failure 401 {object} cargo.ErrorResponse "Unauthorized"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo not found"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /cargo/{id}/stops [post]
+func (h *Handler) AddStop(w http.ResponseWriter, r *http.Request) {
+	actorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	var req cargoDomain.AddStopRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	stops, err := h.uc.AddStop(id, req, actorID, middleware.CargoScope(r.Context()))
+	if err != nil {
+		h.stopError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusCreated, "Остановка добавлена", stops, h.deps.Logger)
+}
+
+// ReorderStops changes the order of cargo stops
+// @Summary Reorder cargo stops
+// @Description Sets the order of stops. The list must contain every stop of the cargo exactly once. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Param order body cargo.ReorderStopsRequest true "Stop IDs in the new order"
+// @Security BearerAuth
+// @Success 200 {object} cargo.StopsResponse "Updated route"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid input"
+// @Failure 401 {object} cargo.ErrorResponse "Unauthorized"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo not found"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /cargo/{id}/stops/order [put]
+func (h *Handler) ReorderStops(w http.ResponseWriter, r *http.Request) {
+	actorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	var req cargoDomain.ReorderStopsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	stops, err := h.uc.ReorderStops(id, req, actorID, middleware.CargoScope(r.Context()))
+	if err != nil {
+		h.stopError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Порядок остановок изменён", stops, h.deps.Logger)
+}
+
+// CompleteStop marks a cargo stop as completed
+// @Summary Complete cargo stop
+// @Description Records actual arrival and departure times of a stop. Missing times default to now. Available to all roles, like other physical-movement updates. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Param stopId path string true "Stop ID"
+// @Param times body cargo.CompleteStopRequest false "Actual arrival and departure times"
+// @Security BearerAuth
+// @Success 200 {object} cargo.StopResponse "Stop completed"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid input"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo or stop not found"
+// @Failure 409 {object} cargo.ErrorResponse "Stop already completed"
+// @Router /cargo/{id}/stops/{stopId}/complete [post]
+func (h *Handler) CompleteStop(w http.ResponseWriter, r *http.Request) {
+	actorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	// тело необязательно: без него оба времени — текущий момент
+	var req cargoDomain.CompleteStopRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+			return
+		}
+	}
+
+	vars := mux.Vars(r)
+
+	stop, err := h.uc.CompleteStop(vars["id"], vars["stopId"], req, actorID, middleware.CargoScope(r.Context()))
+	if err != nil {
+		h.stopError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Остановка завершена", stop, h.deps.Logger)
+}
+
+// DeleteStop removes a stop from the route of a cargo
+// @Summary Delete cargo stop
+// @Description Removes a stop; following stops move up. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
+// @Tags cargo
+// @Accept json
+// @Produce json
+// @Param id path string true "Cargo ID"
+// @Param stopId path string true "Stop ID"
+// @Security BearerAuth
+// @Success 200 {object} cargo.StopsResponse "Updated route"
+// @Failure 401 {object} cargo.ErrorResponse "Unauthorized"
+// @Failure 404 {object} cargo.ErrorResponse "Cargo or stop not found"
+// @Router /cargo/{id}/stops/{stopId} [delete]
+func (h *Handler) DeleteStop(w http.ResponseWriter, r *http.Request) {
+	actorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	stops, err := h.uc.DeleteStop(vars["id"], vars["stopId"], actorID, middleware.CargoScope(r.Context()))
+	if err != nil {
+		h.stopError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Остановка удалена", stops, h.deps.Logger)
 }
@@ -0,0 +1,217 @@
+package counterparty
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"test-project/internal/domain/auth"
+	counterpartyDomain "test-project/internal/domain/counterparty"
+	"test-project/internal/domain/permission"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/internal/validator"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	uc        usecase.CounterpartyUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
+}
+
+func NewHandler(uc usecase.CounterpartyUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, deps: deps, validator: v}
+}
+
+func RegisterCounterpartyRoutes(r *mux.Router, deps *auth.Deps) {
+	v, err := validator.New()
+	if err != nil {
+		log.Fatal("Ошибка инициализации валидатора:", err)
+	}
+
+	repo := counterpartyDomain.NewPostgresCounterpartyRepo(deps.DB)
+	svc := usecase.NewCounterpartyUsecase(repo, v)
+	h := NewHandler(svc, deps, v)
+
+	r.Handle("/counterparties", middleware.Require(deps, permission.CounterpartyManage, h.Create)).Methods(http.MethodPost)
+	r.Handle("/counterparties", middleware.Require(deps, permission.CounterpartyView, h.GET)).Methods(http.MethodGet)
+	r.Handle("/counterparties/{id}", middleware.Require(deps, permission.CounterpartyView, h.GETById)).Methods(http.MethodGet)
+	r.Handle("/counterparties/{id}", middleware.Require(deps, permission.CounterpartyManage, h.PATCH)).Methods(http.MethodPatch)
+	r.Handle("/counterparties/{id}", middleware.Require(deps, permission.CounterpartyManage, h.DELETE)).Methods(http.MethodDelete)
+}
+
+// Create handles the creation of a new counterparty
+// @Summary Create a new counterparty
+// @Description Creates a customer, shipper or consignee. INN and OGRN are checked by checksum.
+// @Tags counterparties
+// @Accept json
+// @Produce json
+// @Param counterparty body counterparty.CreateRequest true "Counterparty to be created"
+// @Security BearerAuth
+// @Success 201 {object} counterparty.CreateResponse "Counterparty successfully created"
+// @Failure 400 {object} counterparty.ErrorResponse "Invalid JSON format or validation errors"
+// @Failure 401 {object} counterparty.ErrorResponse "Unauthorized"
+// @Failure 409 {object} counterparty.ErrorResponse "Counterparty with the same INN and KPP exists"
+// @Failure 500 {object} counterparty.ErrorResponse "Internal server error"
+// @Router /counterparties [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var c counterpartyDomain.Counterparty
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+
+	if errs := h.validator.Validate(c); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	created, err := h.uc.CreateCounterparty(c)
+	if err != nil {
+		if errors.Is(err, counterpartyDomain.ErrDuplicate) {
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusCreated, "Контрагент успешно создан", created, h.deps.Logger)
+}
+
+// GET searches counterparties
+// @Summary Search counterparties
+// @Description Searches counterparties by INN prefix or by a substring of the legal or short name
+// @Tags counterparties
+// @Accept json
+// @Produce json
+// @Param q     query string false "ИНН (начало) или часть названия"
+// @Param limit query int    false "Максимум записей (по умолчанию 50, максимум 200)"
+// @Security BearerAuth
+// @Success 200 {object} counterparty.ListResponse "List of counterparties"
+// @Failure 400 {object} counterparty.ErrorResponse "Invalid limit"
+// @Failure 500 {object} counterparty.ErrorResponse "Internal server error"
+// @Router /counterparties [get]
+func (h *Handler) GET(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var limit int
+	if s := strings.TrimSpace(q.Get("limit")); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil {
+			utils.JSON(w, http.StatusBadRequest, "параметр limit должен быть числом", nil, h.deps.Logger)
+			return
+		}
+	}
+
+	list, err := h.uc.SearchCounterparties(q.Get("q"), limit)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Список контрагентов", list, h.deps.Logger)
+}
+
+// GETById retrieves a counterparty by ID
+// @Summary Get a counterparty by ID
+// @Description Retrieves a counterparty with contacts and bank details
+// @Tags counterparties
+// @Accept json
+// @Produce json
+// @Param id path string true "Counterparty ID"
+// @Security BearerAuth
+// @Success 200 {object} counterparty.GetResponse "Counterparty found"
+// @Failure 404 {object} counterparty.ErrorResponse "Counterparty not found"
+// @Router /counterparties/{id} [get]
+func (h *Handler) GETById(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	c, err := h.uc.GetCounterparty(id)
+	if err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Контрагент", c, h.deps.Logger)
+}
+
+// PATCH updates a counterparty by ID
+// @Summary Update a counterparty by ID
+// @Description Updates counterparty fields. Contacts and bank details are replaced as a whole.
+// @Tags counterparties
+// @Accept json
+// @Produce json
+// @Param id path string true "Counterparty ID"
+// @Param counterparty body counterparty.UpdateCounterpartyInput true "Fields to update"
+// @Security BearerAuth
+// @Success 200 {object} counterparty.GetResponse "Counterparty updated"
+// @Failure 400 {object} counterparty.ErrorResponse "Invalid JSON format or validation errors"
+// @Failure 401 {object} counterparty.ErrorResponse "Unauthorized"
+// @Failure 404 {object} counterparty.ErrorResponse "Counterparty not found"
+// @Failure 409 {object} counterparty.ErrorResponse "Counterparty with the same INN and KPP exists"
+// @Router /counterparties/{id} [patch]
+func (h *Handler) PATCH(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var input counterpartyDomain.UpdateCounterpartyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(input); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	c, err := h.uc.UpdateCounterparty(id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, counterpartyDomain.ErrNotFound):
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, counterpartyDomain.ErrDuplicate):
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Контрагент успешно обновлён", c, h.deps.Logger)
+}
+
+// DELETE deletes a counterparty by ID
+// @Summary Delete a counterparty by ID
+// @Description Deletes a counterparty that is not referenced by any cargo
+// @Tags counterparties
+// @Accept json
+// @Produce json
+// @Param id path string true "Counterparty ID"
+// @Security BearerAuth
+// @Success 200 {object} counterparty.ErrorResponse "Counterparty deleted"
+// @Failure 401 {object} counterparty.ErrorResponse "Unauthorized"
+// @Failure 404 {object} counterparty.ErrorResponse "Counterparty not found"
+// @Failure 409 {object} counterparty.ErrorResponse "Counterparty is used by cargos"
+// @Router /counterparties/{id} [delete]
+func (h *Handler) DELETE(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.uc.DeleteCounterparty(id); err != nil {
+		switch {
+		case errors.Is(err, counterpartyDomain.ErrNotFound):
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, counterpartyDomain.ErrInUse):
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Контрагент с id= "+id+" успешно удалён", nil, h.deps.Logger)
+}
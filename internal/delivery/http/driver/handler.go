@@ -0,0 +1,212 @@
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"test-project/internal/domain/auth"
+	driverDomain "test-project/internal/domain/driver"
+	"test-project/internal/domain/permission"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/internal/validator"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	uc        usecase.DriverUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
+}
+
+func NewHandler(uc usecase.DriverUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, deps: deps, validator: v}
+}
+
+func RegisterDriverRoutes(r *mux.Router, deps *auth.Deps) {
+	v, err := validator.New()
+	if err != nil {
+		log.Fatal("Ошибка инициализации валидатора:", err)
+	}
+
+	driverRepo := driverDomain.NewPostgresDriverRepo(deps.DB)
+	svc := usecase.NewDriverUsecase(driverRepo, v)
+	h := NewHandler(svc, deps, v)
+
+	r.Handle("/drivers", middleware.Require(deps, permission.DriverManage, h.Create)).Methods(http.MethodPost)
+	r.Handle("/drivers", middleware.Require(deps, permission.DriverView, h.GET)).Methods(http.MethodGet)
+	r.Handle("/drivers/{id}", middleware.Require(deps, permission.DriverView, h.GETById)).Methods(http.MethodGet)
+	r.Handle("/drivers/{id}", middleware.Require(deps, permission.DriverManage, h.PATCH)).Methods(http.MethodPatch)
+	r.Handle("/drivers/{id}", middleware.Require(deps, permission.DriverManage, h.DELETE)).Methods(http.MethodDelete)
+}
+
+// Create handles the creation of a new driver
+// @Summary Create a new driver
+// @Description Creates a driver. Phone must be a Russian number, licence number — series and number of a Russian driving licence.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param driver body driver.CreateRequest true "Driver to be created"
+// @Security BearerAuth
+// @Success 201 {object} driver.CreateResponse "Driver successfully created"
+// @Failure 400 {object} driver.ErrorResponse "Invalid JSON format or validation errors"
+// @Failure 401 {object} driver.ErrorResponse "Unauthorized"
+// @Failure 500 {object} driver.ErrorResponse "Internal server error"
+// @Router /drivers [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	// новый водитель по умолчанию активен
+	d := driverDomain.Driver{Status: driverDomain.StatusActive}
+
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+
+	if errs := h.validator.Validate(d); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	created, err := h.uc.CreateDriver(d)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusCreated, "Водитель успешно создан", created, h.deps.Logger)
+}
+
+// GET retrieves a list of drivers
+// @Summary List drivers
+// @Description Retrieves drivers ordered by full name
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param q      query string false "Поиск по ФИО, телефону или номеру удостоверения"
+// @Param status query string false "Статус водителя" Enums(active, on_leave, dismissed)
+// @Security BearerAuth
+// @Success 200 {object} driver.ListResponse "List of drivers"
+// @Failure 400 {object} driver.ErrorResponse "Invalid filter"
+// @Failure 500 {object} driver.ErrorResponse "Internal server error"
+// @Router /drivers [get]
+func (h *Handler) GET(w http.ResponseWriter, r *http.Request) {
+	var filter driverDomain.ListFilter
+
+	if q := strings.TrimSpace(r.URL.Query().Get("q")); q != "" {
+		filter.Query = &q
+	}
+	if s := strings.TrimSpace(r.URL.Query().Get("status")); s != "" {
+		status := driverDomain.Status(s)
+		if !status.Valid() {
+			utils.JSON(w, http.StatusBadRequest, driverDomain.ErrInvalidStatus.Error(), nil, h.deps.Logger)
+			return
+		}
+		filter.Status = &status
+	}
+
+	drivers, err := h.uc.ListDrivers(filter)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Список водителей", drivers, h.deps.Logger)
+}
+
+// GETById retrieves a driver by ID
+// @Summary Get a driver by ID
+// @Description Retrieves a driver by ID
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Security BearerAuth
+// @Success 200 {object} driver.GetResponse "Driver found"
+// @Failure 404 {object} driver.ErrorResponse "Driver not found"
+// @Router /drivers/{id} [get]
+func (h *Handler) GETById(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	d, err := h.uc.GetDriver(id)
+	if err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Водитель", d, h.deps.Logger)
+}
+
+// PATCH updates a driver by ID
+// @Summary Update a driver by ID
+// @Description Updates driver fields. To retire a driver set status to dismissed.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Param driver body driver.UpdateDriverInput true "Fields to update"
+// @Security BearerAuth
+// @Success 200 {object} driver.GetResponse "Driver updated"
+// @Failure 400 {object} driver.ErrorResponse "Invalid JSON format or validation errors"
+// @Failure 401 {object} driver.ErrorResponse "Unauthorized"
+// @Failure 404 {object} driver.ErrorResponse "Driver not found"
+// @Router /drivers/{id} [patch]
+func (h *Handler) PATCH(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var input driverDomain.UpdateDriverInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(input); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	d, err := h.uc.UpdateDriver(id, input)
+	if err != nil {
+		if errors.Is(err, driverDomain.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Водитель успешно обновлён", d, h.deps.Logger)
+}
+
+// DELETE deletes a driver by ID
+// @Summary Delete a driver by ID
+// @Description Deletes a driver without cargos. Drivers referenced by cargos must be dismissed instead.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Security BearerAuth
+// @Success 200 {object} driver.ErrorResponse "Driver deleted"
+// @Failure 401 {object} driver.ErrorResponse "Unauthorized"
+// @Failure 404 {object} driver.ErrorResponse "Driver not found"
+// @Failure 409 {object} driver.ErrorResponse "Driver has cargos"
+// @Router /drivers/{id} [delete]
+func (h *Handler) DELETE(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.uc.DeleteDriver(id); err != nil {
+		switch {
+		case errors.Is(err, driverDomain.ErrNotFound):
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, driverDomain.ErrHasCargos):
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Водитель с id= "+id+" успешно удалён", nil, h.deps.Logger)
+}
@@ -0,0 +1,77 @@
+package file
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"path/filepath"
+	"test-project/config"
+	"test-project/internal/domain/auth"
+	fileDomain "test-project/internal/domain/file"
+	"test-project/internal/domain/permission"
+	"test-project/internal/middleware"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	deps *auth.Deps
+	dir  string
+}
+
+func NewHandler(deps *auth.Deps, dir string) *Handler {
+	return &Handler{deps: deps, dir: dir}
+}
+
+// RegisterFileRoutes отдаёт загруженные файлы из dir по /uploads/*.
+func RegisterFileRoutes(r *mux.Router, deps *auth.Deps, dir string) {
+	h := NewHandler(deps, dir)
+
+	r.PathPrefix("/uploads/").Handler(middleware.JwtMiddleware(deps, h.Download)).Methods(http.MethodGet)
+}
+
+// Download serves an uploaded file
+// @Summary Download a file
+// @Description Downloads a cargo photo or a truck file as an attachment. Cargo photos require cargo:view, truck files require truck:view. Without cargo:view_all only files of trucks assigned to the caller and of their cargos are available. Files of deleted cargos and trucks are not served to such callers.
+// @Tags files
+// @Produce octet-stream
+// @Param name path string true "File name from the file URL"
+// @Security BearerAuth
+// @Success 200 {file} file "File contents"
+// @Failure 401 {object} file.ErrorResponse "Unauthorized"
+// @Failure 404 {object} file.ErrorResponse "File not found"
+// @Router /uploads/{name} [get]
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := path.Base(r.URL.Path)
+
+	rec, err := h.deps.FileService.Find(ctx, "/uploads/"+name, middleware.CargoScope(ctx))
+	if err != nil {
+		if errors.Is(err, fileDomain.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	need := permission.CargoView
+	if rec.OwnerTable == "trucks" {
+		need = permission.TruckView
+	}
+	if !middleware.HasPermission(ctx, need) {
+		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав: нужно разрешение "+string(need), nil, h.deps.Logger)
+		return
+	}
+
+	// Устанавливаем заголовок Content-Disposition для скачивания
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+
+	// Устанавливаем CORS-заголовки
+	w.Header().Set("Access-Control-Allow-Origin", config.Envs.FRONT_URI)
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+	http.ServeFile(w, r, filepath.Join(h.dir, name))
+}
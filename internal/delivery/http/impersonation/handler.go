@@ -0,0 +1,155 @@
+package impersonation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"test-project/internal/domain/auth"
+	impersonationDomain "test-project/internal/domain/impersonation"
+	"test-project/internal/domain/permission"
+	"test-project/internal/domain/user"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/internal/validator"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	uc        usecase.ImpersonationUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
+}
+
+func NewHandler(uc usecase.ImpersonationUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, deps: deps, validator: v}
+}
+
+func RegisterImpersonationRoutes(r *mux.Router, deps *auth.Deps) {
+	v, err := validator.New()
+	if err != nil {
+		log.Fatal("Ошибка инициализации валидатора:", err)
+	}
+
+	h := NewHandler(deps.Impersonations, deps, v)
+
+	r.Handle("/users/{id}/impersonate", middleware.Require(deps, permission.UserImpersonate, h.Start)).Methods(http.MethodPost)
+	r.Handle("/impersonations", middleware.Require(deps, permission.UserImpersonate, h.List)).Methods(http.MethodGet)
+	r.Handle("/impersonations/{id}", middleware.Require(deps, permission.UserImpersonate, h.Get)).Methods(http.MethodGet)
+}
+
+// Start signs in as another user
+// @Summary Impersonate a user
+// @Description Issues a short-lived access token (IMPERSONATION_TTL) of the user to see the system as they do. The token carries an act claim with the caller and cannot be refreshed; it stops working when the caller's own session ends, when the caller's role changes or their tokens are revoked, or when their role loses user:impersonate. Requests made with it are logged and recorded in the impersonation journal with their response status. While impersonating, user:manage, invitation:manage, permission:manage, apikey:manage and user:impersonate are withheld even if the user's role has them, and changing the password, two-factor authentication or sessions is not allowed. SUPERADMIN users cannot be impersonated. Requires user:impersonate.
+// @Tags impersonations
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body impersonation.StartRequest false "Reason, e.g. a support ticket"
+// @Security BearerAuth
+// @Success 201 {object} impersonation.StartResponse "Impersonation token"
+// @Failure 400 {object} impersonation.ErrorResponse "Invalid JSON, validation error, the caller themselves or a SUPERADMIN"
+// @Failure 401 {object} impersonation.ErrorResponse "Unauthorized, or the caller's token has no session"
+// @Failure 404 {object} impersonation.ErrorResponse "User not found"
+// @Failure 500 {object} impersonation.ErrorResponse "Internal server error"
+// @Router /users/{id}/impersonate [post]
+func (h *Handler) Start(w http.ResponseWriter, r *http.Request) {
+	var req impersonationDomain.StartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	ctx := r.Context()
+	actorID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+	// за ключом API не стоит человек, которого можно указать в журнале
+	if role, _ := middleware.GetUserRole(ctx); role == user.RoleService {
+		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав: ключ API не может входить от имени пользователя", nil, h.deps.Logger)
+		return
+	}
+
+	started, err := h.uc.Start(actorID, middleware.GetSessionID(ctx), mux.Vars(r)["id"], req, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, impersonationDomain.ErrSelf),
+			errors.Is(err, impersonationDomain.ErrSuperAdmin):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, impersonationDomain.ErrUser):
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, impersonationDomain.ErrNoSession):
+			utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	h.deps.Logger.Info("начат вход от имени пользователя",
+		zap.String("impersonation", started.Impersonation.ID),
+		zap.String("actor", actorID),
+		zap.String("user", started.Impersonation.UserID),
+		zap.String("reason", started.Impersonation.Reason),
+	)
+
+	utils.JSON(w, http.StatusCreated, "Выполнен вход от имени пользователя", started, h.deps.Logger)
+}
+
+// List returns the impersonation journal
+// @Summary List impersonations
+// @Description Returns impersonations, newest first. Requires user:impersonate.
+// @Tags impersonations
+// @Produce json
+// @Param user_id query string false "Only impersonations of this user"
+// @Security BearerAuth
+// @Success 200 {object} impersonation.ListResponse "Impersonations"
+// @Failure 401 {object} impersonation.ErrorResponse "Unauthorized"
+// @Failure 500 {object} impersonation.ErrorResponse "Internal server error"
+// @Router /impersonations [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.uc.List(r.URL.Query().Get("user_id"))
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Входы от имени пользователей", list, h.deps.Logger)
+}
+
+// Get returns an impersonation with its requests
+// @Summary Get an impersonation
+// @Description Returns an impersonation with every request made with its token, oldest first. Requires user:impersonate.
+// @Tags impersonations
+// @Produce json
+// @Param id path string true "Impersonation ID"
+// @Security BearerAuth
+// @Success 200 {object} impersonation.GetResponse "Impersonation"
+// @Failure 401 {object} impersonation.ErrorResponse "Unauthorized"
+// @Failure 404 {object} impersonation.ErrorResponse "Impersonation not found"
+// @Failure 500 {object} impersonation.ErrorResponse "Internal server error"
+// @Router /impersonations/{id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	imp, err := h.uc.Get(mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, impersonationDomain.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Вход от имени пользователя", imp, h.deps.Logger)
+}
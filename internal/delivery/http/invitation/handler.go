@@ -2,31 +2,37 @@ package invitation
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"mime"
 	"net/http"
 	"test-project/config"
 	"test-project/internal/domain/auth"
 	invitationDomain "test-project/internal/domain/invitation"
-	"test-project/internal/domain/user"
+	"test-project/internal/domain/permission"
 	"test-project/internal/middleware"
 	"test-project/internal/usecase"
 	"test-project/internal/validator"
 	"test-project/pkg"
 	"test-project/utils"
+	"time"
 
 	"github.com/gorilla/mux"
+	"go.uber.org/zap"
 )
 
 type Handler struct {
 	uc   usecase.InvitationUsecase
 	deps *auth.Deps
+	ttl  time.Duration
 }
 
-func NewHandler(uc usecase.InvitationUsecase, deps *auth.Deps) *Handler {
+func NewHandler(uc usecase.InvitationUsecase, deps *auth.Deps, ttl time.Duration) *Handler {
 	return &Handler{
 		uc:   uc,
 		deps: deps,
+		ttl:  ttl,
 	}
 }
 
@@ -37,75 +43,271 @@ func RegisterInvitationRoutes(r *mux.Router, deps *auth.Deps) {
 	}
 
 	invitationRepo := invitationDomain.NewPostgresCargoRepo(deps.DB)
-	svc := usecase.NewInvitationService(invitationRepo, v)
+	ttl := config.Envs.INVITATION_TTL
+	svc := usecase.NewInvitationService(invitationRepo, v, ttl)
 
-	h := NewHandler(svc, deps)
+	h := NewHandler(svc, deps, ttl)
 
-	r.Handle("/invitation/invite", middleware.JwtMiddleware(deps, h.CREATE)).Methods(http.MethodPost)
+	inviteLimit := middleware.RateLimit(deps, middleware.RateRule{
+		Name:     "invite",
+		PerIP:    config.Envs.RATE_LIMIT_INVITE_IP,
+		PerEmail: config.Envs.RATE_LIMIT_INVITE_EMAIL,
+	})
+
+	r.Handle("/invitation/invite", middleware.Require(deps, permission.InvitationManage, inviteLimit(http.HandlerFunc(h.CREATE)).ServeHTTP)).Methods(http.MethodPost)
+	r.Handle("/invitation/bulk", middleware.Require(deps, permission.InvitationManage, inviteLimit(http.HandlerFunc(h.Bulk)).ServeHTTP)).Methods(http.MethodPost)
+	r.Handle("/invitation", middleware.Require(deps, permission.InvitationManage, h.List)).Methods(http.MethodGet)
+	r.Handle("/invitation/{id}/resend", middleware.Require(deps, permission.InvitationManage, inviteLimit(http.HandlerFunc(h.Resend)).ServeHTTP)).Methods(http.MethodPost)
+	r.Handle("/invitation/{id}", middleware.Require(deps, permission.InvitationManage, h.Revoke)).Methods(http.MethodDelete)
 }
 
 // CREATE handles the creation of a new invitation
 // @Summary Create a new invitation
-// @Description Creates a new invitation with the provided details
+// @Description Creates a new invitation with the provided details. Role (USER by default) and username are applied to the account at registration. An expired or used invitation for the same email is replaced. Requires invitation:manage.
 // @Tags invitation
 // @Accept json
 // @Produce json
 // @Param invitation body invitation.CreateRequest true "Invitation object to be created"
+// @Security BearerAuth
 // @Success 201 {object} invitation.CreateResponse "Invitation successfully created"
-// @Failure 400 {object} invitation.ErrorResponse "Invalid JSON format"
+// @Failure 400 {object} invitation.ErrorResponse "Invalid JSON format, validation error or already invited"
+// @Failure 401 {object} invitation.ErrorResponse "Unauthorized"
+// @Failure 429 {object} invitation.ErrorResponse "Too many invitations; see Retry-After"
 // @Failure 500 {object} invitation.ErrorResponse "Internal server error"
 // @Router /invitation/invite [post]
 func (h *Handler) CREATE(w http.ResponseWriter, r *http.Request) {
-	role, err := middleware.GetUserRole(r.Context())
+	var cargo invitationDomain.Invitation
 
-	if err != nil {
-		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+	if err := json.NewDecoder(r.Body).Decode(&cargo); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
 		return
 	}
 
-	if role != user.RoleSuperAdmin {
-		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав. Суперадминистраторы могут приглашать пользователей", nil, h.deps.Logger)
+	cargo, err := h.invite(cargo)
+
+	if err != nil {
+		h.invitationError(w, err)
 		return
 	}
 
-	var cargo invitationDomain.Invitation
+	utils.JSON(w, http.StatusCreated, "Приглашение успешно отправлено. Время жизни "+pkg.FormatTTL(h.ttl), cargo, h.deps.Logger)
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&cargo); err != nil {
-		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
-		return
+// invite создаёт приглашение и отправляет письмо со ссылкой.
+func (h *Handler) invite(inv invitationDomain.Invitation) (invitationDomain.Invitation, error) {
+	if _, err := h.deps.AuthService.FindByEmail(inv.Email); err == nil {
+		return invitationDomain.Invitation{}, invitationDomain.ErrUserExists
 	}
 
-	_, err = h.deps.AuthService.FindByEmail(cargo.Email)
-	if err == nil {
-		utils.JSON(w, http.StatusBadRequest, "Пользователь с таким email уже существует", nil, h.deps.Logger)
-		return
+	inviteToken, err := h.deps.JwtService.GenerateInvite(inv.Email, h.ttl)
+	if err != nil {
+		return invitationDomain.Invitation{}, err
 	}
 
-	inviteToken, err := h.deps.JwtService.GenerateInvite(cargo.Email)
+	inv.Token = inviteToken
+
+	inv, err = h.uc.CreateInvitation(inv)
 	if err != nil {
-		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return invitationDomain.Invitation{}, err
+	}
+
+	inviteLink := fmt.Sprintf("%s/register?token=%s", config.Envs.FRONT_URI, inviteToken)
+	if err := pkg.SendEmail(inv.Email, inviteLink, h.ttl); err != nil {
+		return inv, fmt.Errorf("%w: %v", invitationDomain.ErrEmailFailed, err)
+	}
+
+	return inv, nil
+}
+
+// Bulk invites a list of addresses
+// @Summary Invite a list of users
+// @Description Invites up to 500 addresses at once. Accepts a JSON array of invitations or CSV (text/csv body, or multipart/form-data with a "file" field) with columns email, role, username; a header row is optional. Returns a result per address: sent, already_user, already_invited, email_failed (the invitation exists and can be resent), invalid or failed (internal error). Requires invitation:manage.
+// @Tags invitation
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param invitations body []invitation.CreateRequest false "Invitations (JSON)"
+// @Param file formData file false "CSV file"
+// @Security BearerAuth
+// @Success 200 {object} invitation.BulkResponse "Result per address"
+// @Failure 400 {object} invitation.ErrorResponse "Invalid or empty list"
+// @Failure 401 {object} invitation.ErrorResponse "Unauthorized"
+// @Failure 429 {object} invitation.ErrorResponse "Too many invitations; see Retry-After"
+// @Router /invitation/bulk [post]
+func (h *Handler) Bulk(w http.ResponseWriter, r *http.Request) {
+	list, err := bulkRequest(r)
+	if err != nil {
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		return
+	}
+	if len(list) == 0 {
+		utils.JSON(w, http.StatusBadRequest, "Список приглашений пуст", nil, h.deps.Logger)
+		return
+	}
+	if len(list) > invitationDomain.MaxBulk {
+		utils.JSON(w, http.StatusBadRequest, fmt.Sprintf("За один раз можно пригласить не больше %d адресов", invitationDomain.MaxBulk), nil, h.deps.Logger)
 		return
 	}
 
-	cargo.Token = inviteToken
+	results := make([]invitationDomain.BulkResult, 0, len(list))
+	sent := 0
+	for _, req := range list {
+		inv, err := h.invite(invitationDomain.Invitation{Email: req.Email, Role: req.Role, Username: req.Username})
 
-	inviteLink := fmt.Sprintf("%s/register?token=%s", config.Envs.FRONT_URI, inviteToken)
+		res := invitationDomain.BulkResult{Email: req.Email, Status: invitationDomain.BulkSent, ID: inv.ID}
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, invitationDomain.ErrUserExists):
+			res.Status = invitationDomain.BulkAlreadyUser
+		case errors.Is(err, invitationDomain.ErrAlreadyInvited):
+			res.Status = invitationDomain.BulkAlreadyInvited
+		case errors.Is(err, invitationDomain.ErrEmailFailed):
+			res.Status = invitationDomain.BulkEmailFailed
+		case errors.Is(err, invitationDomain.ErrValidation):
+			res.Status = invitationDomain.BulkInvalid
+		default:
+			// ошибка базы: остальные адреса всё равно пробуем
+			h.deps.Logger.Error("bulk invitation failed", zap.String("email", req.Email), zap.Error(err))
+			res.Status = invitationDomain.BulkFailed
+		}
+		if err != nil {
+			res.Message = err.Error()
+		}
+		results = append(results, res)
+	}
 
-	fmt.Println(inviteLink)
+	utils.JSON(w, http.StatusOK, fmt.Sprintf("Приглашения обработаны: отправлено %d из %d", sent, len(list)), results, h.deps.Logger)
+}
 
-	cargo, err = h.uc.CreateInvitation(cargo)
+// bulkRequest читает список приглашений из JSON или CSV.
+func bulkRequest(r *http.Request) ([]invitationDomain.CreateRequest, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
-	if err != nil {
+	switch mediaType {
+	case "text/csv":
+		return invitationDomain.ParseCSV(r.Body)
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, errors.New("Прикрепите CSV-файл в поле file")
+		}
+		defer file.Close()
+		return invitationDomain.ParseCSV(file)
+	default:
+		var list []invitationDomain.CreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+			return nil, errors.New("Невалидный формат JSON: ожидается массив приглашений")
+		}
+		return list, nil
+	}
+}
+
+// invitationError переводит ошибки приглашений в HTTP-ответ.
+func (h *Handler) invitationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, invitationDomain.ErrNotFound):
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, invitationDomain.ErrValidation),
+		errors.Is(err, invitationDomain.ErrUserExists),
+		errors.Is(err, invitationDomain.ErrAlreadyInvited),
+		errors.Is(err, invitationDomain.ErrAlreadyUsed),
+		errors.Is(err, invitationDomain.ErrInvalidStatus):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+	default:
 		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+	}
+}
+
+// List returns invitations
+// @Summary List invitations
+// @Description Lists invitations, newest first, optionally filtered by status: pending (waiting for registration), used (account created) or expired. Requires invitation:manage.
+// @Tags invitation
+// @Produce json
+// @Param status query string false "pending, used or expired"
+// @Security BearerAuth
+// @Success 200 {object} invitation.ListResponse "Invitations"
+// @Failure 400 {object} invitation.ErrorResponse "Invalid status"
+// @Failure 401 {object} invitation.ErrorResponse "Unauthorized"
+// @Failure 500 {object} invitation.ErrorResponse "Internal server error"
+// @Router /invitation [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	var status *invitationDomain.Status
+	if v := r.URL.Query().Get("status"); v != "" {
+		s := invitationDomain.Status(v)
+		status = &s
+	}
+
+	list, err := h.uc.ListInvitations(status)
+	if err != nil {
+		h.invitationError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Список приглашений", list, h.deps.Logger)
+}
+
+// Resend sends an invitation again
+// @Summary Resend an invitation
+// @Description Issues a new token for a pending or expired invitation, restarts its lifetime and emails the new link. The previous link stops working. Requires invitation:manage.
+// @Tags invitation
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Security BearerAuth
+// @Success 200 {object} invitation.GetResponse "Invitation resent"
+// @Failure 400 {object} invitation.ErrorResponse "Invitation already used"
+// @Failure 401 {object} invitation.ErrorResponse "Unauthorized"
+// @Failure 404 {object} invitation.ErrorResponse "Invitation not found"
+// @Failure 429 {object} invitation.ErrorResponse "Too many invitations; see Retry-After"
+// @Failure 500 {object} invitation.ErrorResponse "Internal server error"
+// @Router /invitation/{id}/resend [post]
+func (h *Handler) Resend(w http.ResponseWriter, r *http.Request) {
+	inv, err := h.uc.GetInvitation(mux.Vars(r)["id"])
+	if err != nil {
+		h.invitationError(w, err)
 		return
 	}
 
-	err = pkg.SendEmail(cargo.Email, inviteLink)
+	inviteToken, err := h.deps.JwtService.GenerateInvite(inv.Email, h.ttl)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
 
+	inv, err = h.uc.ResendInvitation(inv.ID, inviteToken)
 	if err != nil {
+		h.invitationError(w, err)
+		return
+	}
+
+	inviteLink := fmt.Sprintf("%s/register?token=%s", config.Envs.FRONT_URI, inviteToken)
+	if err := pkg.SendEmail(inv.Email, inviteLink, h.ttl); err != nil {
 		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
 		return
 	}
 
-	utils.JSON(w, http.StatusCreated, "Приглашение успешно отправлено. Время жизни 5 минут", cargo, h.deps.Logger)
+	utils.JSON(w, http.StatusOK, "Приглашение отправлено повторно", inv, h.deps.Logger)
+}
+
+// Revoke deletes an unused invitation
+// @Summary Revoke an invitation
+// @Description Deletes a pending or expired invitation; its link stops working. Used invitations cannot be revoked. Requires invitation:manage.
+// @Tags invitation
+// @Produce json
+// @Param id path string true "Invitation ID"
+// @Security BearerAuth
+// @Success 200 {object} invitation.DeleteResponse "Invitation revoked"
+// @Failure 400 {object} invitation.ErrorResponse "Invitation already used"
+// @Failure 401 {object} invitation.ErrorResponse "Unauthorized"
+// @Failure 404 {object} invitation.ErrorResponse "Invitation not found"
+// @Failure 500 {object} invitation.ErrorResponse "Internal server error"
+// @Router /invitation/{id} [delete]
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.RevokeInvitation(mux.Vars(r)["id"]); err != nil {
+		h.invitationError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Приглашение отозвано", nil, h.deps.Logger)
 }
@@ -0,0 +1,122 @@
+package permission
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"test-project/internal/domain/auth"
+	permissionDomain "test-project/internal/domain/permission"
+	"test-project/internal/domain/user"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/internal/validator"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	uc        usecase.PermissionUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
+}
+
+func NewHandler(uc usecase.PermissionUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, deps: deps, validator: v}
+}
+
+func RegisterPermissionRoutes(r *mux.Router, deps *auth.Deps) {
+	v, err := validator.New()
+	if err != nil {
+		log.Fatal("Ошибка инициализации валидатора:", err)
+	}
+
+	h := NewHandler(deps.Permissions, deps, v)
+
+	r.Handle("/permissions", middleware.Require(deps, permissionDomain.PermissionManage, h.List)).Methods(http.MethodGet)
+	r.Handle("/permissions/me", middleware.JwtMiddleware(deps, h.Me)).Methods(http.MethodGet)
+	r.Handle("/permissions/{role}", middleware.Require(deps, permissionDomain.PermissionManage, h.SetRole)).Methods(http.MethodPut)
+}
+
+// List returns the permission registry
+// @Summary List permissions
+// @Description Returns every permission with its description and the roles it is granted to. Requires permission:manage.
+// @Tags permissions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} permission.ListResponse "Permissions and roles"
+// @Failure 401 {object} permission.ErrorResponse "Unauthorized"
+// @Failure 500 {object} permission.ErrorResponse "Internal server error"
+// @Router /permissions [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.uc.List()
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Разрешения и роли", list, h.deps.Logger)
+}
+
+// Me returns the caller's permissions
+// @Summary My permissions
+// @Description Returns the permissions granted to the caller's role, or to the API key for service clients, so the client can hide unavailable actions
+// @Tags permissions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} permission.RoleResponse "Caller's permissions"
+// @Failure 401 {object} permission.ErrorResponse "Unauthorized"
+// @Router /permissions/me [get]
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	// JwtMiddleware уже положил в контекст права роли или ключа API
+	perms := middleware.Permissions(r.Context())
+	if perms == nil {
+		perms = []permissionDomain.Permission{}
+	}
+
+	utils.JSON(w, http.StatusOK, "Мои разрешения", perms, h.deps.Logger)
+}
+
+// SetRole replaces the permissions of a role
+// @Summary Set role permissions
+// @Description Replaces the whole permission set of a role. Takes effect on the next request of its users. permission:manage cannot be taken away from SUPERADMIN. Requires permission:manage.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param role path string true "Role" Enums(USER, EDITOR, SUPERADMIN)
+// @Param permissions body permission.SetRoleRequest true "New permission set"
+// @Security BearerAuth
+// @Success 200 {object} permission.RoleResponse "Permissions updated"
+// @Failure 400 {object} permission.ErrorResponse "Unknown role or permission"
+// @Failure 401 {object} permission.ErrorResponse "Unauthorized"
+// @Failure 500 {object} permission.ErrorResponse "Internal server error"
+// @Router /permissions/{role} [put]
+func (h *Handler) SetRole(w http.ResponseWriter, r *http.Request) {
+	role := user.Role(strings.ToUpper(mux.Vars(r)["role"]))
+
+	var req permissionDomain.SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	if err := h.uc.SetRole(role, req.Permissions); err != nil {
+		switch {
+		case errors.Is(err, permissionDomain.ErrUnknown),
+			errors.Is(err, permissionDomain.ErrRole),
+			errors.Is(err, permissionDomain.ErrLockout):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Разрешения роли обновлены", req.Permissions, h.deps.Logger)
+}
@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"test-project/internal/domain/auth"
+	cargoDomain "test-project/internal/domain/cargo"
+	"test-project/internal/domain/permission"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/utils"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	uc   usecase.ScheduleUsecase
+	deps *auth.Deps
+}
+
+func NewHandler(uc usecase.ScheduleUsecase, deps *auth.Deps) *Handler {
+	return &Handler{uc: uc, deps: deps}
+}
+
+func RegisterScheduleRoutes(r *mux.Router, deps *auth.Deps) {
+	cargoRepo := cargoDomain.NewPostgresCargoRepo(deps.DB)
+	svc := usecase.NewScheduleUsecase(cargoRepo)
+	h := NewHandler(svc, deps)
+
+	r.Handle("/schedule", middleware.Require(deps, permission.ScheduleView, h.GET)).Methods(http.MethodGet)
+}
+
+// GET returns the truck scheduling board
+// @Summary Truck scheduling board
+// @Description Returns per-truck timelines for the period: cargos by planned start/end and free gaps between them. Cancelled cargos are not shown. Without cargo:view_all only trucks assigned to the caller are shown.
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param from query string true "Начало периода (RFC3339)"
+// @Param to   query string true "Конец периода (RFC3339), не дальше 93 дней от начала"
+// @Security BearerAuth
+// @Success 200 {object} cargo.ScheduleResponse "Scheduling board"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid period"
+// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Router /schedule [get]
+func (h *Handler) GET(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from, errFrom := time.Parse(time.RFC3339, strings.TrimSpace(q.Get("from")))
+	to, errTo := time.Parse(time.RFC3339, strings.TrimSpace(q.Get("to")))
+	if errFrom != nil || errTo != nil {
+		utils.JSON(w, http.StatusBadRequest, cargoDomain.ErrScheduleRange.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	board, err := h.uc.Board(from, to, middleware.CargoScope(r.Context()))
+	if err != nil {
+		if errors.Is(err, cargoDomain.ErrScheduleRange) {
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Расписание машин", board, h.deps.Logger)
+}
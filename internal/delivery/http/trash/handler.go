@@ -0,0 +1,130 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+	"test-project/internal/domain/auth"
+	"test-project/internal/domain/permission"
+	trashDomain "test-project/internal/domain/trash"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	uc   usecase.TrashUsecase
+	deps *auth.Deps
+}
+
+func NewHandler(uc usecase.TrashUsecase, deps *auth.Deps) *Handler {
+	return &Handler{uc: uc, deps: deps}
+}
+
+func RegisterTrashRoutes(r *mux.Router, deps *auth.Deps) {
+	trashRepo := trashDomain.NewPostgresTrashRepo(deps.DB)
+	svc := usecase.NewTrashUsecase(trashRepo, deps.FileService)
+	h := NewHandler(svc, deps)
+
+	r.Handle("/trash", middleware.Require(deps, permission.TrashView, h.List)).Methods(http.MethodGet)
+	r.Handle("/trash/{type}/{id}/restore", middleware.Require(deps, permission.TrashRestore, h.Restore)).Methods(http.MethodPost)
+	r.Handle("/trash/{type}/{id}", middleware.Require(deps, permission.TrashPurge, h.Purge)).Methods(http.MethodDelete)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, trashDomain.ErrUnknownType),
+		errors.Is(err, trashDomain.ErrTruckDeleted),
+		errors.Is(err, trashDomain.ErrTruckHasCargo):
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, trashDomain.ErrNotFound):
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+	case errors.Is(err, trashDomain.ErrTaken):
+		utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+	default:
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+	}
+}
+
+// List retrieves soft-deleted cargos and trucks
+// @Summary List trash
+// @Description Retrieves soft-deleted cargos and trucks, newest first
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} trash.ListResponse "Trash items"
+// @Failure 401 {object} trash.ErrorResponse "Unauthorized"
+// @Failure 500 {object} trash.ErrorResponse "Internal server error"
+// @Router /trash [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.uc.List()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Корзина", items, h.deps.Logger)
+}
+
+// Restore restores a soft-deleted cargo or truck
+// @Summary Restore from trash
+// @Description Restores a soft-deleted cargo or truck
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param type path string true "Object type" Enums(cargo, truck)
+// @Param id path string true "Object ID"
+// @Security BearerAuth
+// @Success 200 {object} trash.ErrorResponse "Restored"
+// @Failure 400 {object} trash.ErrorResponse "Cannot be restored"
+// @Failure 401 {object} trash.ErrorResponse "Unauthorized"
+// @Failure 404 {object} trash.ErrorResponse "Not found in trash"
+// @Failure 409 {object} trash.ErrorResponse "Cargo number, truck name, licence plate or VIN is taken by another object"
+// @Router /trash/{type}/{id}/restore [post]
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actorID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.uc.Restore(trashDomain.ItemType(vars["type"]), vars["id"], actorID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Объект с id= "+vars["id"]+" восстановлен", nil, h.deps.Logger)
+}
+
+// Purge permanently deletes a cargo or truck from trash
+// @Summary Purge from trash
+// @Description Permanently deletes a soft-deleted cargo or truck together with its files. Requires trash:purge.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param type path string true "Object type" Enums(cargo, truck)
+// @Param id path string true "Object ID"
+// @Security BearerAuth
+// @Success 200 {object} trash.ErrorResponse "Purged"
+// @Failure 400 {object} trash.ErrorResponse "Cannot be purged"
+// @Failure 401 {object} trash.ErrorResponse "Unauthorized"
+// @Failure 404 {object} trash.ErrorResponse "Not found in trash"
+// @Router /trash/{type}/{id} [delete]
+func (h *Handler) Purge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+
+	if err := h.uc.Purge(ctx, trashDomain.ItemType(vars["type"]), vars["id"]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Объект с id= "+vars["id"]+" окончательно удалён", nil, h.deps.Logger)
+}
@@ -2,12 +2,15 @@ package truck
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"strconv"
+	"strings"
 	"test-project/internal/domain/auth"
+	"test-project/internal/domain/cargo"
+	"test-project/internal/domain/file"
+	"test-project/internal/domain/permission"
 	truckDomain "test-project/internal/domain/truck"
-	"test-project/internal/domain/user"
 	"test-project/internal/middleware"
 	"test-project/internal/usecase"
 	"test-project/internal/validator"
@@ -17,12 +20,13 @@ import (
 )
 
 type Handler struct {
-	uc   usecase.TruckUsecase
-	deps *auth.Deps
+	uc        usecase.TruckUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
 }
 
-func NewHandler(uc usecase.TruckUsecase, deps *auth.Deps) *Handler {
-	return &Handler{uc: uc, deps: deps}
+func NewHandler(uc usecase.TruckUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, deps: deps, validator: v}
 }
 func RegisterUserRoutes(r *mux.Router, deps *auth.Deps) {
 	v, err := validator.New()
@@ -31,13 +35,17 @@ func RegisterUserRoutes(r *mux.Router, deps *auth.Deps) {
 	}
 
 	truckRepo := truckDomain.NewPostgresTruckRepo(deps.DB)
-	svc := usecase.NewTruckUsecase(truckRepo, v)
-	h := NewHandler(svc, deps)
+	svc := usecase.NewTruckUsecase(truckRepo, deps.FileService, v)
+	h := NewHandler(svc, deps, v)
 
-	r.Handle("/truck", middleware.JwtMiddleware(deps, h.Create)).Methods(http.MethodPost)
-	r.Handle("/truck", middleware.JwtMiddleware(deps, h.GET)).Methods(http.MethodGet)
-	r.Handle("/truck/{id}", middleware.JwtMiddleware(deps, h.GETById)).Methods(http.MethodGet)
-	r.Handle("/truck/{id}/cargos", middleware.JwtMiddleware(deps, h.GETCargos)).Methods(http.MethodGet)
+	r.Handle("/truck", middleware.Require(deps, permission.TruckManage, h.Create)).Methods(http.MethodPost)
+	r.Handle("/truck", middleware.Require(deps, permission.TruckView, h.GET)).Methods(http.MethodGet)
+	r.Handle("/truck/{id}", middleware.Require(deps, permission.TruckView, h.GETById)).Methods(http.MethodGet)
+	r.Handle("/truck/{id}", middleware.Require(deps, permission.TruckManage, h.PATCH)).Methods(http.MethodPatch)
+	r.Handle("/truck/{id}", middleware.Require(deps, permission.TruckManage, h.DELETE)).Methods(http.MethodDelete)
+	r.Handle("/truck/{id}/files", middleware.Require(deps, permission.TruckManage, h.UploadFiles)).Methods(http.MethodPost)
+	r.Handle("/truck/{id}/files/{fileId}", middleware.Require(deps, permission.TruckManage, h.DeleteFile)).Methods(http.MethodDelete)
+	r.Handle("/truck/{id}/cargos", middleware.Require(deps, permission.TruckView, h.GETCargos)).Methods(http.MethodGet)
 }
 
 // Create handles the creation of a new truck
@@ -48,34 +56,36 @@ func RegisterUserRoutes(r *mux.Router, deps *auth.Deps) {
 // @Produce json
 // @Param truck body truck.CreateRequest true "Truck object to be created"
 // @Security BearerAuth
-// @Success 201 {object} cargo.CreateResponse "Cargo successfully created"
-// @Failure 400 {object} cargo.ErrorResponse "Invalid JSON format"
-// @Failure 500 {object} cargo.ErrorResponse "Internal server error"
+// @Success 201 {object} truck.CreateResponse "Truck successfully created"
+// @Failure 400 {object} truck.ErrorResponse "Invalid JSON format or validation errors"
+// @Failure 409 {object} truck.ErrorResponse "Licence plate or VIN already in use"
+// @Failure 500 {object} truck.ErrorResponse "Internal server error"
 // @Router /truck [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
-	role, err := middleware.GetUserRole(r.Context())
+	// новая машина по умолчанию активна
+	truck := truckDomain.Truck{Active: true}
 
-	if err != nil {
-		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
-		return
-	}
-
-	if role != user.RoleSuperAdmin && role != user.RoleEditor {
-		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав. Суперадминистраторы и редакторы могут создавать машины", nil, h.deps.Logger)
+	if err := json.NewDecoder(r.Body).Decode(&truck); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
 		return
 	}
 
-	var truck truckDomain.Truck
-
-	if err := json.NewDecoder(r.Body).Decode(&truck); err != nil {
-		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+	if errs := h.validator.Validate(truck); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
 		return
 	}
 
-	truck, err = h.uc.CreateTruck(truck)
+	truck, err := h.uc.CreateTruck(truck)
 
 	if err != nil {
-		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		switch {
+		case errors.Is(err, truckDomain.ErrValidation):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, truckDomain.ErrDuplicate):
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
 		return
 	}
 
@@ -129,36 +139,215 @@ func (h *Handler) GETById(w http.ResponseWriter, r *http.Request) {
 
 // GETCargos retrieves a list of cargos by truck ID
 // @Summary Get a list of cargos by truck ID
-// @Description Retrieves a list of cargos by their truck ID
+// @Description Retrieves cargos of the truck with the same filters, sorting and cursor-based pagination as GET /cargo. Payout fields, filters and sorting require finance:view. Without cargo:view_all only cargos of trucks assigned to the caller are visible.
 // @Tags truck
 // @Accept json
 // @Produce json
 // @Param id path string true "Truck ID"
-// @Param limit query int false "Limit number of cargos per page"
-// @Param page query int false "Page number for pagination"
+// @Param driverId           query string false "ID водителя"
+// @Param driver             query string false "ФИО водителя (поиск по подстроке)"
+// @Param customerId         query string false "ID заказчика"
+// @Param shipperId          query string false "ID грузоотправителя"
+// @Param consigneeId        query string false "ID грузополучателя"
+// @Param paymentStatus      query string false "Статус оплаты"
+// @Param status             query string false "Статус груза" Enums(draft, planned, loading, in_transit, delivered, invoiced, paid, closed, cancelled)
+// @Param dateFrom           query string false "Дата от (RFC3339)"
+// @Param dateTo             query string false "Дата до (RFC3339)"
+// @Param loadUnloadDateFrom query string false "Дата погрузки/разгрузки от (RFC3339)"
+// @Param loadUnloadDateTo   query string false "Дата погрузки/разгрузки до (RFC3339)"
+// @Param payoutDateFrom     query string false "Дата выплаты от (RFC3339)"
+// @Param payoutDateTo       query string false "Дата выплаты до (RFC3339)"
+// @Param payoutAmountMin    query number false "Минимальная сумма выплаты"
+// @Param payoutAmountMax    query number false "Максимальная сумма выплаты"
+// @Param sort               query string false "Поле сортировки" Enums(createdAt, date, loadUnloadDate, payoutDate, payoutAmount, cargoNumber)
+// @Param order              query string false "Направление сортировки" Enums(asc, desc)
+// @Param cursor             query string false "Курсор следующей страницы (nextCursor из предыдущего ответа)"
+// @Param limit              query int    false "Размер страницы (по умолчанию 20, максимум 100)"
 // @Security BearerAuth
 // @Success 201 {object} cargo.ListResponse "List of cargos"
-// @Failure 400 {object} cargo.ErrorResponse "Invalid ID"
+// @Failure 400 {object} cargo.ErrorResponse "Invalid filter"
 // @Failure 404 {object} cargo.ErrorResponse "Truck not found"
 // @Router /truck/{id}/cargos [get]
 func (h *Handler) GETCargos(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
-	// Пагинация
-	query := r.URL.Query()
-	limitStr := query.Get("limit")
-	pageStr := query.Get("page")
-
-	// Можешь распарсить строки в int
-	limit, _ := strconv.Atoi(limitStr)
-	page, _ := strconv.Atoi(pageStr)
+	filter, err := cargo.ParseListFilter(r.URL.Query())
+	if err != nil {
+		utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		return
+	}
+	finance := middleware.HasPermission(r.Context(), permission.FinanceView)
+	if !finance && filter.UsesFinance() {
+		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав: фильтры и сортировка по выплатам требуют разрешения "+string(permission.FinanceView), nil, h.deps.Logger)
+		return
+	}
+	filter.Scope = middleware.CargoScope(r.Context())
 
-	cargos, err := h.uc.GetTruckCargos(id, limit, page)
+	cargos, err := h.uc.GetTruckCargos(id, filter)
 
 	if err != nil {
+		if errors.Is(err, cargo.ErrInvalidFilter) {
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+			return
+		}
 		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
 		return
 	}
+	if !finance {
+		cargos.HideFinance()
+	}
 
 	utils.JSON(w, http.StatusCreated, "Список грузов", cargos, h.deps.Logger)
 }
+
+// PATCH updates a truck by ID
+// @Summary Update a truck by ID
+// @Description Updates truck attributes. Licence plate must be a Russian plate, VIN must pass checksum validation.
+// @Tags truck
+// @Accept json
+// @Produce json
+// @Param id path string true "Truck ID"
+// @Param truck body truck.UpdateTruckInput true "Fields to update"
+// @Security BearerAuth
+// @Success 200 {object} truck.GetResponse "Truck updated"
+// @Failure 400 {object} truck.ErrorResponse "Invalid JSON format or validation errors"
+// @Failure 401 {object} truck.ErrorResponse "Unauthorized"
+// @Failure 404 {object} truck.ErrorResponse "Truck not found"
+// @Failure 409 {object} truck.ErrorResponse "Licence plate or VIN already in use"
+// @Failure 500 {object} truck.ErrorResponse "Internal server error"
+// @Router /truck/{id} [patch]
+func (h *Handler) PATCH(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var input truckDomain.UpdateTruckInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(input); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	truck, err := h.uc.UpdateTruck(id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, truckDomain.ErrNotFound):
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, truckDomain.ErrValidation):
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+		case errors.Is(err, truckDomain.ErrDuplicate):
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+		default:
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		}
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Машина успешно обновлена", truck, h.deps.Logger)
+}
+
+// DELETE deletes a truck by ID
+// @Summary Delete a truck by ID
+// @Description Moves a truck to the trash. Trucks with unfinished cargos cannot be deleted.
+// @Tags truck
+// @Accept json
+// @Produce json
+// @Param id path string true "Truck ID"
+// @Security BearerAuth
+// @Success 200 {object} truck.ErrorResponse "Truck deleted"
+// @Failure 401 {object} truck.ErrorResponse "Unauthorized"
+// @Failure 404 {object} truck.ErrorResponse "Truck not found"
+// @Failure 409 {object} truck.ErrorResponse "Truck has unfinished cargos"
+// @Router /truck/{id} [delete]
+func (h *Handler) DELETE(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actorID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.uc.DeleteTruck(id, actorID); err != nil {
+		if errors.Is(err, truckDomain.ErrTruckHasActiveCargos) {
+			utils.JSON(w, http.StatusConflict, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Машина с id= "+id+" успешно удалена", nil, h.deps.Logger)
+}
+
+// UploadFiles attaches photos and documents to a truck
+// @Summary Upload truck files
+// @Description Attaches photos and documents (registration, insurance, etc.) to a truck
+// @Tags truck
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Truck ID"
+// @Param photos    formData file false "Фотографии машины (можно выбрать несколько файлов)"
+// @Param documents formData file false "Документы машины (можно выбрать несколько файлов)"
+// @Security BearerAuth
+// @Success 200 {object} truck.GetResponse "Files uploaded"
+// @Failure 400 {object} truck.ErrorResponse "Invalid form"
+// @Failure 401 {object} truck.ErrorResponse "Unauthorized"
+// @Failure 500 {object} truck.ErrorResponse "Internal server error"
+// @Router /truck/{id}/files [post]
+func (h *Handler) UploadFiles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil { // 32 МБ
+		utils.JSON(w, http.StatusBadRequest, "multipart parse: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.uc.AttachFiles(ctx, id, file.KindPhoto, r.MultipartForm.File["photos"]); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, "upload photos: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+	if err := h.uc.AttachFiles(ctx, id, file.KindDocument, r.MultipartForm.File["documents"]); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, "upload documents: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	truck, err := h.uc.GetTruck(id)
+	if err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Файлы машины загружены", truck, h.deps.Logger)
+}
+
+// DeleteFile removes a photo or document from a truck
+// @Summary Delete truck file
+// @Description Removes a photo or document attached to a truck
+// @Tags truck
+// @Accept json
+// @Produce json
+// @Param id path string true "Truck ID"
+// @Param fileId path string true "File ID"
+// @Security BearerAuth
+// @Success 200 {object} truck.ErrorResponse "File deleted"
+// @Failure 401 {object} truck.ErrorResponse "Unauthorized"
+// @Failure 500 {object} truck.ErrorResponse "Internal server error"
+// @Router /truck/{id}/files/{fileId} [delete]
+func (h *Handler) DeleteFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+
+	if err := h.uc.DeleteFiles(ctx, vars["id"], []string{vars["fileId"]}); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, "delete files: "+err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Файл машины удалён", nil, h.deps.Logger)
+}
@@ -2,9 +2,13 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
 	"test-project/internal/domain/auth"
+	"test-project/internal/domain/permission"
+	"test-project/internal/domain/session"
 	"test-project/internal/domain/user"
 	"test-project/internal/middleware"
 	"test-project/internal/usecase"
@@ -15,12 +19,14 @@ import (
 )
 
 type Handler struct {
-	uc   usecase.UserUsecase
-	deps *auth.Deps
+	uc        usecase.UserUsecase
+	sessions  usecase.SessionUsecase
+	deps      *auth.Deps
+	validator *validator.Validator
 }
 
-func NewHandler(uc usecase.UserUsecase, deps *auth.Deps) *Handler {
-	return &Handler{uc: uc, deps: deps}
+func NewHandler(uc usecase.UserUsecase, sessions usecase.SessionUsecase, deps *auth.Deps, v *validator.Validator) *Handler {
+	return &Handler{uc: uc, sessions: sessions, deps: deps, validator: v}
 }
 
 func RegisterUserRoutes(r *mux.Router, deps *auth.Deps) {
@@ -30,18 +36,27 @@ func RegisterUserRoutes(r *mux.Router, deps *auth.Deps) {
 	}
 
 	userRepo := user.NewPostgresUserRepo(deps.DB)
-	svc := usecase.NewUserUsecase(userRepo, v)
-	h := NewHandler(svc, deps)
+	svc := usecase.NewUserUsecase(userRepo, v, deps.Redis)
+	sessions := usecase.NewSessionUsecase(session.NewPostgresSessionRepo(deps.DB), deps.Redis)
+	h := NewHandler(svc, sessions, deps, v)
 
-	r.Handle("/users", middleware.JwtMiddleware(deps, h.List)).Methods(http.MethodGet)
-	r.HandleFunc("/users/{id}", h.Get).Methods(http.MethodGet)
-	r.HandleFunc("/users/{id}", h.Delete).Methods(http.MethodDelete)
-	r.HandleFunc("/users/{id}", h.PATCH).Methods(http.MethodPatch)
+	r.Handle("/users", middleware.Require(deps, permission.UserView, h.List)).Methods(http.MethodGet)
+	r.Handle("/users/{id}", middleware.Require(deps, permission.UserView, h.Get)).Methods(http.MethodGet)
+	r.Handle("/users/{id}", middleware.Require(deps, permission.UserManage, h.Delete)).Methods(http.MethodDelete)
+	r.Handle("/users/{id}", middleware.Require(deps, permission.UserManage, h.PATCH)).Methods(http.MethodPatch)
+
+	r.Handle("/users/{id}/sessions", middleware.Require(deps, permission.UserManage, h.Sessions)).Methods(http.MethodGet)
+	r.Handle("/users/{id}/sessions", middleware.Require(deps, permission.UserManage, h.RevokeSessions)).Methods(http.MethodDelete)
+	r.Handle("/users/{id}/sessions/{sessionId}", middleware.Require(deps, permission.UserManage, h.RevokeSession)).Methods(http.MethodDelete)
+	r.Handle("/users/{id}/lock", middleware.Require(deps, permission.UserManage, h.Unlock)).Methods(http.MethodDelete)
+
+	r.Handle("/users/{id}/trucks", middleware.Require(deps, permission.UserView, h.Trucks)).Methods(http.MethodGet)
+	r.Handle("/users/{id}/trucks", middleware.Require(deps, permission.UserManage, h.SetTrucks)).Methods(http.MethodPut)
 }
 
 // List retrieves a list of all users
 // @Summary List all users
-// @Description Retrieves a list of all users in the system
+// @Description Retrieves a list of all users in the system. Requires user:view.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -61,11 +76,12 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 
 // Get retrieves a user by ID
 // @Summary Get a user by ID
-// @Description Retrieves a user by their ID
+// @Description Retrieves a user by their ID. Requires user:view.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Security BearerAuth
 // @Success 201 {object} user.GetResponse "User found"
 // @Failure 400 {object} user.ErrorResponse "Invalid ID"
 // @Failure 404 {object} user.ErrorResponse "User not found"
@@ -84,31 +100,21 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 
 // Delete deletes a user by ID
 // @Summary Delete a user by ID
-// @Description Deletes a user by their ID
+// @Description Deletes a user by their ID. Requires user:manage.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Security BearerAuth
 // @Success 201 {object} user.DeleteResponse "User deleted"
 // @Failure 400 {object} user.ErrorResponse "Invalid ID"
 // @Failure 404 {object} user.ErrorResponse "User not found"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
 // @Router /users/{id} [delete]
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
-	role, err := middleware.GetUserRole(r.Context())
-
-	if err != nil {
-		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, h.deps.Logger)
-		return
-	}
-
-	if role != user.RoleSuperAdmin {
-		utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав. Суперадминистраторы могут удалять пользователей", nil, h.deps.Logger)
-		return
-	}
-
 	id := mux.Vars(r)["id"]
 
-	err = h.uc.DeleteUser(id)
+	err := h.uc.DeleteUser(id)
 
 	if err != nil {
 		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
@@ -120,26 +126,28 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 
 // PATCH updates a user by ID
 // @Summary Update a user by ID
-// @Description Updates a user by their ID
+// @Description Updates a user by their ID. Requires user:manage.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Security BearerAuth
 // @Param user body user.UpdateRequest true "User object to be updated"
 // @Success 201 {object} user.UpdateResponse "User updated"
 // @Failure 400 {object} user.ErrorResponse "Invalid ID"
 // @Failure 404 {object} user.ErrorResponse "User not found"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
 // @Router /users/{id} [patch]
 func (h *Handler) PATCH(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
-	var user user.UpdateUser
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var input user.UpdateUser
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
 		return
 	}
 
-	err := h.uc.UpdateUser(id, user)
+	err := h.uc.UpdateUser(id, input)
 	if err != nil {
 		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
 		return
@@ -147,3 +155,180 @@ func (h *Handler) PATCH(w http.ResponseWriter, r *http.Request) {
 
 	utils.JSON(w, http.StatusCreated, "Пользователь с id= "+id+" успешно обновлен", nil, h.deps.Logger)
 }
+
+// Sessions lists active sessions of a user
+// @Summary List user sessions
+// @Description Lists active sessions of any user. Requires user:manage.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} session.ListResponse "Active sessions"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
+// @Failure 404 {object} user.ErrorResponse "User not found"
+// @Failure 500 {object} user.ErrorResponse "Internal server error"
+// @Router /users/{id}/sessions [get]
+func (h *Handler) Sessions(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if _, err := h.uc.GetUser(id); err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	list, err := h.sessions.List(id, middleware.GetSessionID(r.Context()))
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Активные сессии", list, h.deps.Logger)
+}
+
+// RevokeSession ends one session of a user
+// @Summary End a user session
+// @Description Ends one session of any user. Requires user:manage.
+// @Tags users
+// @Produce json
+// @Param id        path string true "User ID"
+// @Param sessionId path string true "Session ID"
+// @Security BearerAuth
+// @Success 200 {object} user.DeleteResponse "Session ended"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
+// @Failure 404 {object} user.ErrorResponse "Session not found"
+// @Failure 500 {object} user.ErrorResponse "Internal server error"
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.sessions.Revoke(vars["id"], vars["sessionId"]); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Сессия завершена", nil, h.deps.Logger)
+}
+
+// RevokeSessions ends all sessions of a user
+// @Summary End all user sessions
+// @Description Signs a user out everywhere. Requires user:manage.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} session.RevokeAllResponse "IDs of ended sessions"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
+// @Failure 500 {object} user.ErrorResponse "Internal server error"
+// @Router /users/{id}/sessions [delete]
+func (h *Handler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	ids, err := h.sessions.RevokeOthers(mux.Vars(r)["id"], "")
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Все сессии пользователя завершены", ids, h.deps.Logger)
+}
+
+// Unlock lifts a login lockout
+// @Summary Unlock a user account
+// @Description Lifts the temporary login lockout set after too many failed login attempts and resets the failure counter. Requires user:manage.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} user.DeleteResponse "Account unlocked"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
+// @Failure 404 {object} user.ErrorResponse "User not found"
+// @Failure 500 {object} user.ErrorResponse "Internal server error"
+// @Router /users/{id}/lock [delete]
+func (h *Handler) Unlock(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if _, err := h.uc.GetUser(id); err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	if err := h.deps.AuthService.UnlockAccount(id); err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Учётная запись разблокирована", nil, h.deps.Logger)
+}
+
+// Trucks lists trucks assigned to a user
+// @Summary List user trucks
+// @Description Returns IDs of the trucks assigned to a user. Users without cargo:view_all see only cargos of these trucks. Requires user:view.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} user.TrucksResponse "Assigned truck IDs"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
+// @Failure 404 {object} user.ErrorResponse "User not found"
+// @Failure 500 {object} user.ErrorResponse "Internal server error"
+// @Router /users/{id}/trucks [get]
+func (h *Handler) Trucks(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if _, err := h.uc.GetUser(id); err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	ids, err := h.uc.Trucks(id)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Машины пользователя", ids, h.deps.Logger)
+}
+
+// SetTrucks replaces trucks assigned to a user
+// @Summary Assign trucks to a user
+// @Description Replaces the whole list of trucks assigned to a user; an empty list removes all assignments. Requires user:manage.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param trucks body user.SetTrucksRequest true "Truck IDs"
+// @Security BearerAuth
+// @Success 200 {object} user.TrucksResponse "Assigned truck IDs"
+// @Failure 400 {object} user.ErrorResponse "Invalid JSON, validation errors or unknown truck"
+// @Failure 401 {object} user.ErrorResponse "Unauthorized"
+// @Failure 404 {object} user.ErrorResponse "User not found"
+// @Failure 500 {object} user.ErrorResponse "Internal server error"
+// @Router /users/{id}/trucks [put]
+func (h *Handler) SetTrucks(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req user.SetTrucksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSON(w, http.StatusBadRequest, "Невалидный формат JSON", nil, h.deps.Logger)
+		return
+	}
+	if errs := h.validator.Validate(req); len(errs) > 0 {
+		utils.JSON(w, http.StatusBadRequest, "Ошибки валидации: "+strings.Join(errs, "; "), nil, h.deps.Logger)
+		return
+	}
+
+	if _, err := h.uc.GetUser(id); err != nil {
+		utils.JSON(w, http.StatusNotFound, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	ids, err := h.uc.SetTrucks(id, req.TruckIDs)
+	if err != nil {
+		if errors.Is(err, user.ErrTruckNotFound) {
+			utils.JSON(w, http.StatusBadRequest, err.Error(), nil, h.deps.Logger)
+			return
+		}
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, h.deps.Logger)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, "Машины пользователя обновлены", ids, h.deps.Logger)
+}
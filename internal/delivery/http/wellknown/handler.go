@@ -0,0 +1,45 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"net/http"
+	"test-project/internal/domain/auth"
+	"test-project/internal/middleware"
+	"test-project/internal/usecase"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	deps *auth.Deps
+}
+
+func NewHandler(deps *auth.Deps) *Handler {
+	return &Handler{deps: deps}
+}
+
+// RegisterWellKnownRoutes регистрирует стандартные адреса /.well-known/*.
+// Они живут в корне, а не под /api/v1, и отдают ответ в формате стандарта,
+// без обёртки utils.JSON, поэтому в swagger не описаны.
+func RegisterWellKnownRoutes(r *mux.Router, deps *auth.Deps) {
+	h := NewHandler(deps)
+
+	r.Handle("/.well-known/jwks.json", middleware.Public(http.HandlerFunc(h.JWKS))).Methods(http.MethodGet)
+}
+
+type jwksResponse struct {
+	Keys []usecase.JWK `json:"keys"`
+}
+
+// JWKS публикует открытые ключи access-токенов (RFC 7517), чтобы другие
+// сервисы проверяли токены без обращения к нам. После ротации старый ключ
+// остаётся в списке, пока лежит в каталоге ключей.
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if err := json.NewEncoder(w).Encode(jwksResponse{Keys: h.deps.JwtService.AccessJWKS()}); err != nil {
+		h.deps.Logger.Error("не удалось отправить JWKS", zap.Error(err))
+	}
+}
@@ -2,17 +2,30 @@ package router
 
 import (
 	"log"
-	"net/http"
 	"os"
-	"path/filepath"
 	"test-project/config"
+	"test-project/internal/delivery/http/apikey"
 	"test-project/internal/delivery/http/auth"
 	"test-project/internal/delivery/http/cargo"
+	"test-project/internal/delivery/http/counterparty"
+	"test-project/internal/delivery/http/driver"
+	"test-project/internal/delivery/http/file"
+	"test-project/internal/delivery/http/impersonation"
 	"test-project/internal/delivery/http/invitation"
+	"test-project/internal/delivery/http/permission"
+	"test-project/internal/delivery/http/schedule"
+	"test-project/internal/delivery/http/trash"
 	"test-project/internal/delivery/http/truck"
 	"test-project/internal/delivery/http/user"
+	"test-project/internal/delivery/http/wellknown"
+	apikeyDomain "test-project/internal/domain/apikey"
 	authDomain "test-project/internal/domain/auth"
-	"test-project/internal/domain/file"
+	fileDomain "test-project/internal/domain/file"
+	impersonationDomain "test-project/internal/domain/impersonation"
+	invitationDomain "test-project/internal/domain/invitation"
+	mfaDomain "test-project/internal/domain/mfa"
+	permissionDomain "test-project/internal/domain/permission"
+	sessionDomain "test-project/internal/domain/session"
 	userDomain "test-project/internal/domain/user"
 	"test-project/internal/middleware"
 	"test-project/internal/redis"
@@ -36,23 +49,6 @@ func Setup(pool *pgxpool.Pool, logger *zap.Logger, jwtService *usecase.JwtUsecas
 
 	subrouter := r.PathPrefix("/api/v1").Subrouter()
 
-	// Настройка статического файлового сервера для папки uploads
-	// Маршрут /api/v1/uploads/ будет обслуживать файлы из ./uploads
-	fileServer := http.FileServer(http.Dir("./uploads"))
-	subrouter.PathPrefix("/uploads/").Handler(http.StripPrefix("/api/v1/uploads/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Устанавливаем заголовок Content-Disposition для скачивания
-		filename := filepath.Base(r.URL.Path)
-		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
-
-		// Устанавливаем CORS-заголовки
-		w.Header().Set("Access-Control-Allow-Origin", config.Envs.FRONT_URI) // Укажите ваш фронтенд URL, например, http://localhost:3000
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		// Обслуживаем файл
-		fileServer.ServeHTTP(w, r)
-	})))
-
 	swaggerUsername := config.Envs.SWAGGER_LOGIN
 	swaggerPassword := config.Envs.SWAGGER_PASS
 	swaggerHandler := middleware.AuthSwagger(httpSwagger.WrapHandler, swaggerUsername, swaggerPassword)
@@ -60,25 +56,59 @@ func Setup(pool *pgxpool.Pool, logger *zap.Logger, jwtService *usecase.JwtUsecas
 	subrouter.PathPrefix("/swagger/").Handler(swaggerHandler)
 
 	userRepo := userDomain.NewPostgresUserRepo(pool)
-	authSvc := usecase.NewService(userRepo, jwtService, redisService)
+	sessionRepo := sessionDomain.NewPostgresSessionRepo(pool)
+	mfaSvc := usecase.NewMfaUsecase(mfaDomain.NewPostgresRepo(pool), userRepo, config.Envs.MFA_ISSUER, config.Envs.MFA_REQUIRED_FOR_SUPERADMIN)
+	lockoutPolicy := usecase.LockoutPolicy{
+		MaxFailures: config.Envs.LOGIN_MAX_FAILURES,
+		Duration:    config.Envs.LOGIN_LOCKOUT,
+	}
+	invitationRepo := invitationDomain.NewPostgresCargoRepo(pool)
+	authSvc := usecase.NewService(userRepo, sessionRepo, invitationRepo, mfaSvc, jwtService, redisService, lockoutPolicy, config.Envs.MAGIC_LINK_TTL)
 
-	fs := file.Local{Dir: "./uploads", BaseURL: "/uploads"}
-	fileSvc := usecase.NewFileService(fs, file.NewRepo(pool))
+	fs := fileDomain.Local{Dir: "./uploads", BaseURL: "/uploads"}
+	fileSvc := usecase.NewFileService(fs, fileDomain.NewRepo(pool))
+	permissionRepo := permissionDomain.NewPostgresRepo(pool)
 
 	deps := &authDomain.Deps{
-		Logger:      logger,
-		JwtService:  jwtService,
-		AuthService: authSvc,
-		FileService: fileSvc,
-		Redis:       redisService,
-		DB:          pool,
+		Logger:         logger,
+		JwtService:     jwtService,
+		AuthService:    authSvc,
+		FileService:    fileSvc,
+		Redis:          redisService,
+		DB:             pool,
+		Permissions:    usecase.NewPermissionUsecase(permissionRepo, redisService),
+		APIKeys:        usecase.NewAPIKeyUsecase(apikeyDomain.NewPostgresRepo(pool), permissionRepo),
+		Impersonations: usecase.NewImpersonationUsecase(impersonationDomain.NewPostgresRepo(pool), userRepo, jwtService, redisService, config.Envs.IMPERSONATION_TTL),
 	}
 
+	// общий лимит на все маршруты API (RATE_LIMIT_API, по умолчанию выключен);
+	// маршруты входа, регистрации и приглашений лимитируются строже у себя
+	subrouter.Use(middleware.RateLimit(deps, middleware.RateRule{
+		Name:  "api",
+		PerIP: config.Envs.RATE_LIMIT_API,
+	}))
+
 	user.RegisterUserRoutes(subrouter, deps)
 	truck.RegisterUserRoutes(subrouter, deps)
 	cargo.RegisterCargoRoute(subrouter, deps)
 	auth.RegisterCargoRoute(subrouter, deps)
 	invitation.RegisterInvitationRoutes(subrouter, deps)
+	trash.RegisterTrashRoutes(subrouter, deps)
+	driver.RegisterDriverRoutes(subrouter, deps)
+	counterparty.RegisterCounterpartyRoutes(subrouter, deps)
+	schedule.RegisterScheduleRoutes(subrouter, deps)
+	permission.RegisterPermissionRoutes(subrouter, deps)
+	file.RegisterFileRoutes(subrouter, deps, "./uploads")
+	apikey.RegisterAPIKeyRoutes(subrouter, deps)
+	impersonation.RegisterImpersonationRoutes(subrouter, deps)
+	wellknown.RegisterWellKnownRoutes(r, deps)
+
+	// маршрут без явной политики доступа — ошибка конфигурации, а не
+	// открытый по недосмотру эндпоинт
+	if err := middleware.CheckPolicies(r); err != nil {
+		log.Fatalf("проверка политик доступа: %v", err)
+	}
 
-	return subrouter
+	// отдаётся корневой роутер: кроме /api/v1 на нём живут /.well-known/*
+	return r
 }
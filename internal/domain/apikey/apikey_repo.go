@@ -0,0 +1,117 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"test-project/internal/domain/permission"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepo(db *pgxpool.Pool) Repository {
+	return &PostgresRepo{db: db}
+}
+
+const keyColumns = `
+    k.id,
+    k.name,
+    k.prefix,
+    k.permissions,
+    k.expires_at,
+    k.last_used_at,
+    k.created_by,
+    k.created_at,
+    k.revoked_at`
+
+func scanKey(row pgx.Row) (APIKey, error) {
+	var (
+		k     APIKey
+		perms []string
+	)
+	err := row.Scan(
+		&k.ID,
+		&k.Name,
+		&k.Prefix,
+		&perms,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.CreatedBy,
+		&k.CreatedAt,
+		&k.RevokedAt,
+	)
+	k.Permissions = make([]permission.Permission, len(perms))
+	for i, p := range perms {
+		k.Permissions[i] = permission.Permission(p)
+	}
+	return k, err
+}
+
+func (r *PostgresRepo) Create(k APIKey, keyHash string) (APIKey, error) {
+	perms := make([]string, len(k.Permissions))
+	for i, p := range k.Permissions {
+		perms[i] = string(p)
+	}
+
+	return scanKey(r.db.QueryRow(context.Background(),
+		`INSERT INTO api_keys AS k (name, prefix, key_hash, permissions, expires_at, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING`+keyColumns,
+		k.Name, k.Prefix, keyHash, perms, k.ExpiresAt, k.CreatedBy))
+}
+
+func (r *PostgresRepo) FindAll() ([]APIKey, error) {
+	rows, err := r.db.Query(context.Background(),
+		"SELECT"+keyColumns+`
+		   FROM api_keys k
+		  ORDER BY k.created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []APIKey{}
+	for rows.Next() {
+		k, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresRepo) FindActiveByHash(keyHash string) (APIKey, error) {
+	k, err := scanKey(r.db.QueryRow(context.Background(),
+		"SELECT"+keyColumns+`
+		   FROM api_keys k
+		  WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND k.expires_at > now()`, keyHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return APIKey{}, ErrInvalid
+	}
+	return k, err
+}
+
+func (r *PostgresRepo) Revoke(id string) error {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE api_keys SET revoked_at = now() WHERE id::text = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepo) Touch(id string) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE api_keys
+		    SET last_used_at = now()
+		  WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`, id)
+	return err
+}
@@ -0,0 +1,73 @@
+package apikey
+
+import (
+	"errors"
+	"test-project/internal/domain/permission"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("ключ API не найден")
+	ErrInvalid  = errors.New("ключ API недействителен: он неизвестен, отозван или истёк")
+	ErrExpiry   = errors.New("срок действия ключа должен быть в будущем")
+	// ErrSelfManage — ключ не может выпускать другие ключи, иначе отзыв
+	// утёкшего ключа ничего бы не решал.
+	ErrSelfManage = errors.New("ключу API нельзя выдать право apikey:manage")
+)
+
+// APIKey — ключ, по которому к API обращается интеграция, а не человек.
+// Сам ключ не хранится: только его sha256 и первые символы для узнавания.
+type APIKey struct {
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name" example:"Бухгалтерия"`
+	Prefix      string                  `json:"prefix" example:"tpk_3f9a1c"`
+	Permissions []permission.Permission `json:"permissions"`
+	ExpiresAt   time.Time               `json:"expiresAt"`
+	LastUsedAt  *time.Time              `json:"lastUsedAt"`
+	CreatedBy   *string                 `json:"createdBy"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	RevokedAt   *time.Time              `json:"revokedAt"`
+}
+
+type Repository interface {
+	Create(k APIKey, keyHash string) (APIKey, error)
+	// FindAll возвращает все ключи, включая отозванные и истёкшие.
+	FindAll() ([]APIKey, error)
+	// FindActiveByHash ищет неотозванный и неистёкший ключ.
+	FindActiveByHash(keyHash string) (APIKey, error)
+	Revoke(id string) error
+	// Touch отмечает использование ключа. Чтобы не писать в БД на каждый
+	// запрос, время обновляется не чаще раза в минуту.
+	Touch(id string) error
+}
+
+type CreateRequest struct {
+	Name        string                  `json:"name" validate:"required,min=3,max=100" example:"Бухгалтерия"`
+	Permissions []permission.Permission `json:"permissions" validate:"required,min=1"`
+	ExpiresAt   time.Time               `json:"expiresAt" validate:"required" example:"2027-01-01T00:00:00Z"`
+}
+
+// Created — только что выпущенный ключ. Key показывается один раз.
+type Created struct {
+	APIKey
+	Key string `json:"key" example:"tpk_3f9a1c..."`
+}
+
+type ListResponse struct {
+	Message string   `json:"message" example:"Ключи API"`
+	Data    []APIKey `json:"data"`
+}
+
+type CreateResponse struct {
+	Message string  `json:"message" example:"Ключ API создан. Сохраните его: повторно он показан не будет"`
+	Data    Created `json:"data"`
+}
+
+type RevokeResponse struct {
+	Message string `json:"message" example:"Ключ API отозван"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"ключ API не найден"`
+	Data    interface{} `json:"data"`
+}
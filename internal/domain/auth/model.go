@@ -20,6 +20,18 @@ type LoginRequest struct {
 	Password string `json:"password" example:"123456"`
 }
 
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email" example:"firulvv@mail.ru"`
+}
+
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" validate:"required" example:"eyJhbGciOiJFZERTQSIs..."`
+}
+
+type MagicLinkResponse struct {
+	Message string `json:"message" example:"Если этот email зарегистрирован, на него отправлена ссылка для входа"`
+}
+
 type RegisterResponse struct {
 	Message string    `json:"message" example:"Пользователь успешно зарегистрирован"`
 	Data    user.User `json:"data"`
@@ -61,4 +73,8 @@ type Deps struct {
 	Redis       *redis.Client
 	DB          *pgxpool.Pool
 	FileService *usecase.FileService
+	Permissions usecase.PermissionUsecase
+	APIKeys     usecase.APIKeyUsecase
+	// Impersonations пишет журнал запросов, сделанных от имени пользователя.
+	Impersonations usecase.ImpersonationUsecase
 }
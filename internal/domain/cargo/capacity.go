@@ -0,0 +1,78 @@
+package cargo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrOverCapacity  = errors.New("груз не помещается в машину")
+	ErrTruckNotFound = errors.New("машина груза не найдена")
+)
+
+// CapacityExcess — на сколько груз превышает возможности машины по одному параметру.
+type CapacityExcess struct {
+	Field  string  `json:"field" example:"weightKg"`
+	Cargo  float64 `json:"cargo" example:"22000"`
+	Limit  float64 `json:"limit" example:"20000"`
+	Excess float64 `json:"excess" example:"2000"`
+}
+
+// CapacityError перечисляет превышения грузоподъёмности и объёма кузова.
+type CapacityError struct {
+	Excess []CapacityExcess
+}
+
+func (e *CapacityError) Error() string {
+	messages := make([]string, len(e.Excess))
+	for i, x := range e.Excess {
+		switch x.Field {
+		case "weightKg":
+			messages[i] = fmt.Sprintf("вес груза %s кг превышает грузоподъёмность машины %s кг на %s кг",
+				formatAmount(x.Cargo), formatAmount(x.Limit), formatAmount(x.Excess))
+		case "volumeM3":
+			messages[i] = fmt.Sprintf("объём груза %s м³ превышает объём кузова машины %s м³ на %s м³",
+				formatAmount(x.Cargo), formatAmount(x.Limit), formatAmount(x.Excess))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *CapacityError) Unwrap() error {
+	return ErrOverCapacity
+}
+
+// CheckCapacity сравнивает вес и объём груза с грузоподъёмностью и объёмом
+// кузова машины. Если у груза или машины значение не указано, оно не проверяется.
+func CheckCapacity(weightKg, volumeM3, payloadKg, truckVolumeM3 *float64) error {
+	var excess []CapacityExcess
+
+	if weightKg != nil && payloadKg != nil && *weightKg > *payloadKg {
+		excess = append(excess, CapacityExcess{
+			Field: "weightKg", Cargo: *weightKg, Limit: *payloadKg, Excess: roundAmount(*weightKg - *payloadKg),
+		})
+	}
+	if volumeM3 != nil && truckVolumeM3 != nil && *volumeM3 > *truckVolumeM3 {
+		excess = append(excess, CapacityExcess{
+			Field: "volumeM3", Cargo: *volumeM3, Limit: *truckVolumeM3, Excess: roundAmount(*volumeM3 - *truckVolumeM3),
+		})
+	}
+
+	if len(excess) > 0 {
+		return &CapacityError{Excess: excess}
+	}
+	return nil
+}
+
+// roundAmount округляет до сотых, чтобы не показывать погрешность вычитания.
+func roundAmount(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// formatAmount печатает число без лишних нулей, с точностью до сотых.
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(roundAmount(v), 'f', -1, 64)
+}
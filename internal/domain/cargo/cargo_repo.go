@@ -19,187 +19,174 @@ func NewPostgresCargoRepo(db *pgxpool.Pool) CargoRepository {
 	return &PostgresCargoRepo{db: db}
 }
 
-func (r *PostgresCargoRepo) Create(c Cargo) (Cargo, error) {
-	err := r.db.QueryRow(context.Background(),
+// rowQuerier — общее у пула и транзакции, чтобы чтение и запись ревизии
+// работали и внутри транзакции изменения груза.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// writeRevision пишет ревизию, построенную по состоянию после изменения, в транзакции tx.
+func writeRevision(ctx context.Context, tx pgx.Tx, rv Revise, after Cargo) error {
+	if rv == nil {
+		return nil
+	}
+	rev := rv(after)
+	if rev == nil {
+		return nil
+	}
+	if _, err := insertRevision(ctx, tx, *rev); err != nil {
+		return fmt.Errorf("record cargo revision: %w", err)
+	}
+	return nil
+}
+
+// RecordRevision перечитывает груз в транзакции tx и пишет по нему ревизию —
+// для изменений груза, которые выполняют другие репозитории (корзина).
+func RecordRevision(ctx context.Context, tx pgx.Tx, cargoID string, rv Revise) error {
+	after, err := findByID(ctx, tx, cargoID)
+	if err != nil {
+		return err
+	}
+	return writeRevision(ctx, tx, rv, after)
+}
+
+func (r *PostgresCargoRepo) Create(c Cargo, check TruckCheck, rv Revise) (Cargo, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Cargo{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
 		`INSERT INTO cargos 
-	(cargoNumber, date, loadUnloadDate, driver, transportationInfo, payoutAmount, payoutDate, paymentStatus, payoutTerms, truckId) 
+	(cargoNumber, date, loadUnloadDate, driver_id, transportationInfo, payoutAmount, payoutDate, paymentStatus, payoutTerms, truckId, customer_id, shipper_id, consignee_id, planned_start, planned_end, weight_kg, volume_m3, pallets, hazard_class) 
 	VALUES 
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
-	RETURNING id, cargoNumber, date, loadUnloadDate, driver, transportationInfo, payoutAmount, payoutDate, paymentStatus, payoutTerms, "createdAt", truckId`,
-		c.CargoNumber, c.Date, c.LoadUnloadDate, c.Driver, c.TransportationInfo, c.PayoutAmount, c.PayoutDate, c.PaymentStatus, c.PayoutTerms, c.TruckID,
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19) 
+	RETURNING id, cargoNumber, date, loadUnloadDate, planned_start, planned_end, driver_id, transportationInfo, payoutAmount, payoutDate, paymentStatus, payoutTerms, weight_kg, volume_m3, pallets, hazard_class, status, "createdAt", truckId, customer_id, shipper_id, consignee_id`,
+		c.CargoNumber, c.Date, c.LoadUnloadDate, c.DriverID, c.TransportationInfo, c.PayoutAmount, c.PayoutDate, c.PaymentStatus, c.PayoutTerms, c.TruckID,
+		c.CustomerID, c.ShipperID, c.ConsigneeID, c.PlannedStart, c.PlannedEnd, c.WeightKg, c.VolumeM3, c.Pallets, c.HazardClass,
 	).Scan(
 		&c.ID,
 		&c.CargoNumber,
 		&c.Date,
 		&c.LoadUnloadDate,
-		&c.Driver,
+		&c.PlannedStart,
+		&c.PlannedEnd,
+		&c.DriverID,
 		&c.TransportationInfo,
 		&c.PayoutAmount,
 		&c.PayoutDate,
 		&c.PaymentStatus,
 		&c.PayoutTerms,
+		&c.WeightKg,
+		&c.VolumeM3,
+		&c.Pallets,
+		&c.HazardClass,
+		&c.Status,
 		&c.CreatedAt,
 		&c.TruckID,
+		&c.CustomerID,
+		&c.ShipperID,
+		&c.ConsigneeID,
 	)
 
 	if err != nil {
 		return Cargo{}, err
 	}
 
+	if err := checkTruck(ctx, tx, check, c); err != nil {
+		return Cargo{}, err
+	}
+	if err := writeRevision(ctx, tx, rv, c); err != nil {
+		return Cargo{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Cargo{}, err
+	}
 	return c, nil
 }
 
-func (r *PostgresCargoRepo) FindAll() ([]Cargo, error) {
-	const query = `
-SELECT
-    c.id,
-    c.cargonumber          AS "cargoNumber",
-    c.date,
-    c.loadunloaddate       AS "loadUnloadDate",
-    c.driver,
-    c.transportationinfo   AS "transportationInfo",
-    c.payoutamount         AS "payoutAmount",
-    c.payoutdate           AS "payoutDate",
-    c.paymentstatus        AS "paymentStatus",
-    c.payoutterms          AS "payoutTerms",
-    c."createdAt",
-    c.truckid              AS "truckId",
-    COALESCE(
-      json_agg(
-        json_build_object(
-          'id',        f.id,
-          'url',       f.url,
-          'cargoId',   f.owner_id,
-          'createdAt', f.created_at
-        )
-        ORDER BY f.created_at
-      ) FILTER (WHERE f.id IS NOT NULL),
-      '[]'
-    ) AS photos_json
-FROM   cargos c
-LEFT   JOIN files f
-       ON f.owner_table = 'cargos'
-      AND f.owner_id    = c.id
-GROUP  BY c.id
-ORDER  BY c."createdAt" DESC;
-`
-
-	rows, err := r.db.Query(context.Background(), query)
+func (r *PostgresCargoRepo) FindAll(f ListFilter) (ListResult, error) {
+	if err := f.normalize(); err != nil {
+		return ListResult{}, err
+	}
+
+	countSQL, countArgs, pageSQL, pageArgs, err := buildListQueries(f)
 	if err != nil {
-		return nil, fmt.Errorf("query cargos: %w", err)
+		return ListResult{}, err
 	}
-	defer rows.Close()
 
-	var result []Cargo
+	result := ListResult{Items: []Cargo{}}
 
-	for rows.Next() {
-		var (
-			c          Cargo
-			photosJSON []byte
-		)
-
-		if err := rows.Scan(
-			&c.ID,
-			&c.CargoNumber,
-			&c.Date,
-			&c.LoadUnloadDate,
-			&c.Driver,
-			&c.TransportationInfo,
-			&c.PayoutAmount,
-			&c.PayoutDate,
-			&c.PaymentStatus,
-			&c.PayoutTerms,
-			&c.CreatedAt,
-			&c.TruckID,
-			&photosJSON,
-		); err != nil {
-			return nil, fmt.Errorf("scan cargo: %w", err)
-		}
+	if err := r.db.QueryRow(context.Background(), countSQL, countArgs...).Scan(&result.Total); err != nil {
+		return ListResult{}, fmt.Errorf("count cargos: %w", err)
+	}
 
-		// распаковываем JSON-массив файлов
-		if err := json.Unmarshal(photosJSON, &c.CargoPhotos); err != nil {
-			return nil, fmt.Errorf("unmarshal photos: %w", err)
-		}
+	rows, err := r.db.Query(context.Background(), pageSQL, pageArgs...)
+	if err != nil {
+		return ListResult{}, fmt.Errorf("query cargos: %w", err)
+	}
+	defer rows.Close()
 
-		result = append(result, c)
+	for rows.Next() {
+		c, err := scanCargo(rows)
+		if err != nil {
+			return ListResult{}, fmt.Errorf("scan cargo: %w", err)
+		}
+		result.Items = append(result.Items, c)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration: %w", err)
+		return ListResult{}, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	// выбрали limit+1 запись — значит, есть следующая страница
+	if len(result.Items) > f.Limit {
+		result.Items = result.Items[:f.Limit]
+		next := nextCursor(f, result.Items[f.Limit-1])
+		result.NextCursor = &next
 	}
 
 	return result, nil
 }
 
 func (r *PostgresCargoRepo) FindByID(id string) (Cargo, error) {
-	const q = `
-SELECT
-    c.id,
-    c.cargonumber          AS "cargoNumber",
-    c.date,
-    c.loadunloaddate       AS "loadUnloadDate",
-    c.driver,
-    c.transportationinfo   AS "transportationInfo",
-    c.payoutamount         AS "payoutAmount",
-    c.payoutdate           AS "payoutDate",
-    c.paymentstatus        AS "paymentStatus",
-    c.payoutterms          AS "payoutTerms",
-    c."createdAt",
-    c.truckid              AS "truckId",
-    COALESCE(
-      json_agg(
-        json_build_object(
-          'id',        f.id,
-          'url',       f.url,
-          'cargoId',   f.owner_id,
-          'createdAt', f.created_at
-        )
-        ORDER BY f.created_at
-      ) FILTER (WHERE f.id IS NOT NULL),
-      '[]'
-    ) AS photos_json
-FROM   cargos c
-LEFT   JOIN files f
-       ON f.owner_table = 'cargos'
-      AND f.owner_id    = c.id
-WHERE  c.id = $1
-GROUP  BY c.id;`
+	return findByID(context.Background(), r.db, id)
+}
 
-	var (
-		c          Cargo
-		photosJSON []byte
-	)
+func findByID(ctx context.Context, db rowQuerier, id string) (Cargo, error) {
+	q := "SELECT" + cargoColumns + "\nFROM cargos c\nWHERE c.id = $1 AND c.deleted_at IS NULL"
 
-	err := r.db.QueryRow(context.Background(), q, id).Scan(
-		&c.ID,
-		&c.CargoNumber,
-		&c.Date,
-		&c.LoadUnloadDate,
-		&c.Driver,
-		&c.TransportationInfo,
-		&c.PayoutAmount,
-		&c.PayoutDate,
-		&c.PaymentStatus,
-		&c.PayoutTerms,
-		&c.CreatedAt,
-		&c.TruckID,
-		&photosJSON,
-	)
+	c, err := scanCargo(db.QueryRow(ctx, q, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return Cargo{}, fmt.Errorf("cargo with id=%s not found", id)
+			return Cargo{}, fmt.Errorf("%w: id=%s", ErrNotFound, id)
 		}
 		return Cargo{}, err
 	}
 
-	if err := json.Unmarshal(photosJSON, &c.CargoPhotos); err != nil {
-		return Cargo{}, fmt.Errorf("decode photos: %w", err)
+	return c, nil
+}
+
+func (r *PostgresCargoRepo) FindVisible(id string, scope Scope) (Cargo, error) {
+	q := &listQuery{where: []string{"c.deleted_at IS NULL"}}
+	q.where = append(q.where, "c.id = "+q.arg(id))
+	scope.condition(q)
+
+	c, err := scanCargo(r.db.QueryRow(context.Background(), "SELECT"+cargoColumns+"\nFROM cargos c"+q.whereSQL(), q.args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Cargo{}, fmt.Errorf("%w: id=%s", ErrNotFound, id)
+		}
+		return Cargo{}, err
 	}
 
 	return c, nil
 }
 
-func (r *PostgresCargoRepo) Update(c UpdateCargoInput, id string) (Cargo, error) {
+func (r *PostgresCargoRepo) Update(c UpdateCargoInput, id string, check TruckCheck, rv Revise) (Cargo, error) {
 	query := "UPDATE cargos SET "
 	args := []interface{}{}
 	i := 1
@@ -219,9 +206,19 @@ func (r *PostgresCargoRepo) Update(c UpdateCargoInput, id string) (Cargo, error)
 		args = append(args, *c.LoadUnloadDate)
 		i++
 	}
-	if c.Driver != nil {
-		query += fmt.Sprintf("driver = $%d, ", i)
-		args = append(args, *c.Driver)
+	if c.PlannedStart != nil {
+		query += fmt.Sprintf("planned_start = $%d, ", i)
+		args = append(args, *c.PlannedStart)
+		i++
+	}
+	if c.PlannedEnd != nil {
+		query += fmt.Sprintf("planned_end = $%d, ", i)
+		args = append(args, *c.PlannedEnd)
+		i++
+	}
+	if c.DriverID != nil {
+		query += fmt.Sprintf("driver_id = $%d, ", i)
+		args = append(args, *c.DriverID)
 		i++
 	}
 	if c.TransportationInfo != nil {
@@ -249,32 +246,322 @@ func (r *PostgresCargoRepo) Update(c UpdateCargoInput, id string) (Cargo, error)
 		args = append(args, *c.PayoutTerms)
 		i++
 	}
+	if c.WeightKg != nil {
+		query += fmt.Sprintf("weight_kg = $%d, ", i)
+		args = append(args, *c.WeightKg)
+		i++
+	}
+	if c.VolumeM3 != nil {
+		query += fmt.Sprintf("volume_m3 = $%d, ", i)
+		args = append(args, *c.VolumeM3)
+		i++
+	}
+	if c.Pallets != nil {
+		query += fmt.Sprintf("pallets = $%d, ", i)
+		args = append(args, *c.Pallets)
+		i++
+	}
+	if c.HazardClass != nil {
+		query += fmt.Sprintf("hazard_class = $%d, ", i)
+		args = append(args, *c.HazardClass)
+		i++
+	}
 	if c.TruckID != nil {
 		query += fmt.Sprintf("truckId = $%d, ", i)
 		args = append(args, *c.TruckID)
 		i++
 	}
+	if c.CustomerID != nil {
+		query += fmt.Sprintf("customer_id = $%d, ", i)
+		args = append(args, *c.CustomerID)
+		i++
+	}
+	if c.ShipperID != nil {
+		query += fmt.Sprintf("shipper_id = $%d, ", i)
+		args = append(args, *c.ShipperID)
+		i++
+	}
+	if c.ConsigneeID != nil {
+		query += fmt.Sprintf("consignee_id = $%d, ", i)
+		args = append(args, *c.ConsigneeID)
+		i++
+	}
+
+	// нечего обновлять — например, в запросе были только файлы
+	if len(args) == 0 {
+		return r.FindByID(id)
+	}
 
 	// убрать последнюю запятую
 	query = strings.TrimSuffix(query, ", ")
 	// добавить WHERE
-	query += fmt.Sprintf(" WHERE id = $%d", i)
+	query += fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL", i)
 	args = append(args, id)
 
-	_, err := r.db.Exec(context.Background(), query, args...)
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return Cargo{}, err
 	}
+	defer tx.Rollback(ctx)
 
-	cargo, err := r.FindByID(id)
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return Cargo{}, err
+	}
+
+	cargo, err := findByID(ctx, tx, id)
 	if err != nil {
 		return Cargo{}, err
 	}
 
+	if err := checkTruck(ctx, tx, check, cargo); err != nil {
+		return Cargo{}, err
+	}
+	if err := writeRevision(ctx, tx, rv, cargo); err != nil {
+		return Cargo{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Cargo{}, err
+	}
 	return cargo, nil
 }
 
-func (r *PostgresCargoRepo) Delete(id string) error {
-	_, err := r.db.Exec(context.Background(), "DELETE FROM cargos WHERE id=$1", id)
-	return err
+// Delete помечает груз удалённым. Окончательно груз удаляется из корзины.
+func (r *PostgresCargoRepo) Delete(id string, deletedBy string, rv Revise) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := findByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE cargos SET deleted_at = now(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		id, deletedBy)
+	if err != nil {
+		return err
+	}
+	// груз успел удалить параллельный запрос — второй ревизии удаления не будет
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: id=%s", ErrNotFound, id)
+	}
+
+	if err := writeRevision(ctx, tx, rv, deleted); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ChangeStatus переводит груз из статуса from в to и записывает переход в журнал.
+// Если статус успел измениться, возвращается ErrStatusConflict.
+func (r *PostgresCargoRepo) ChangeStatus(id string, from, to Status, userID string, comment *string, rv Revise) (StatusTransition, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return StatusTransition{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE cargos SET status = $1 WHERE id = $2 AND status = $3 AND deleted_at IS NULL`,
+		string(to), id, string(from))
+	if err != nil {
+		return StatusTransition{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return StatusTransition{}, ErrStatusConflict
+	}
+
+	t := StatusTransition{CargoID: id, From: from, To: to, UserID: userID, Comment: comment}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO cargo_status_transitions (cargo_id, from_status, to_status, user_id, comment)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at`,
+		id, string(from), string(to), userID, comment,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return StatusTransition{}, err
+	}
+
+	after, err := findByID(ctx, tx, id)
+	if err != nil {
+		return StatusTransition{}, err
+	}
+	if err := writeRevision(ctx, tx, rv, after); err != nil {
+		return StatusTransition{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return StatusTransition{}, err
+	}
+	return t, nil
+}
+
+func (r *PostgresCargoRepo) FindTransitions(cargoID string) ([]StatusTransition, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT id, cargo_id, from_status, to_status, user_id, comment, created_at
+		   FROM cargo_status_transitions
+		  WHERE cargo_id = $1
+		  ORDER BY created_at`, cargoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []StatusTransition{}
+	for rows.Next() {
+		var t StatusTransition
+		if err := rows.Scan(&t.ID, &t.CargoID, &t.From, &t.To, &t.UserID, &t.Comment, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresCargoRepo) Replace(id string, c Cargo, check TruckCheck, rv Revise) (Cargo, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Cargo{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`UPDATE cargos
+		    SET cargoNumber = $1,
+		        date = $2,
+		        loadUnloadDate = $3,
+		        driver_id = $4,
+		        transportationInfo = $5,
+		        payoutAmount = $6,
+		        payoutDate = $7,
+		        paymentStatus = $8,
+		        payoutTerms = $9,
+		        truckId = $10,
+		        customer_id = $11,
+		        shipper_id = $12,
+		        consignee_id = $13,
+		        planned_start = $14,
+		        planned_end = $15,
+		        weight_kg = $16,
+		        volume_m3 = $17,
+		        pallets = $18,
+		        hazard_class = $19
+		  WHERE id = $20 AND deleted_at IS NULL`,
+		c.CargoNumber, c.Date, c.LoadUnloadDate, c.DriverID, c.TransportationInfo,
+		c.PayoutAmount, c.PayoutDate, c.PaymentStatus, c.PayoutTerms, c.TruckID,
+		c.CustomerID, c.ShipperID, c.ConsigneeID, c.PlannedStart, c.PlannedEnd,
+		c.WeightKg, c.VolumeM3, c.Pallets, c.HazardClass, id,
+	)
+	if err != nil {
+		return Cargo{}, err
+	}
+
+	after, err := findByID(ctx, tx, id)
+	if err != nil {
+		return Cargo{}, err
+	}
+
+	if err := checkTruck(ctx, tx, check, after); err != nil {
+		return Cargo{}, err
+	}
+	if err := writeRevision(ctx, tx, rv, after); err != nil {
+		return Cargo{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Cargo{}, err
+	}
+	return after, nil
+}
+
+func insertRevision(ctx context.Context, db rowQuerier, rev Revision) (Revision, error) {
+	diff, err := json.Marshal(rev.Diff)
+	if err != nil {
+		return Revision{}, fmt.Errorf("marshal diff: %w", err)
+	}
+
+	err = db.QueryRow(ctx,
+		`INSERT INTO cargo_revisions (cargo_id, action, diff, snapshot, user_id)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at`,
+		rev.CargoID, string(rev.Action), diff, []byte(rev.Snapshot), rev.UserID,
+	).Scan(&rev.ID, &rev.CreatedAt)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	return rev, nil
+}
+
+const revisionColumns = `id, cargo_id, action, diff, snapshot, user_id, created_at`
+
+func scanRevision(row pgx.Row) (Revision, error) {
+	var (
+		rev      Revision
+		diffJSON []byte
+		snapshot []byte
+	)
+
+	if err := row.Scan(&rev.ID, &rev.CargoID, &rev.Action, &diffJSON, &snapshot, &rev.UserID, &rev.CreatedAt); err != nil {
+		return Revision{}, err
+	}
+	if err := json.Unmarshal(diffJSON, &rev.Diff); err != nil {
+		return Revision{}, fmt.Errorf("unmarshal diff: %w", err)
+	}
+	rev.Snapshot = snapshot
+
+	return rev, nil
+}
+
+// FindRevisions возвращает историю груза. История удалённого груза тоже
+// доступна, поэтому видимость проверяется по cargos без учёта deleted_at.
+func (r *PostgresCargoRepo) FindRevisions(cargoID string, scope Scope) ([]Revision, error) {
+	q := &listQuery{}
+	q.where = append(q.where, "c.id = "+q.arg(cargoID))
+	scope.condition(q)
+
+	rows, err := r.db.Query(context.Background(),
+		`SELECT `+revisionColumns+`
+		   FROM cargo_revisions
+		  WHERE cargo_id = $1
+		    AND EXISTS (SELECT 1 FROM cargos c`+q.whereSQL()+`)
+		  ORDER BY created_at DESC, id`, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Revision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rev)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresCargoRepo) FindRevision(cargoID, revisionID string) (Revision, error) {
+	rev, err := scanRevision(r.db.QueryRow(context.Background(),
+		`SELECT `+revisionColumns+`
+		   FROM cargo_revisions
+		  WHERE cargo_id = $1 AND id = $2`, cargoID, revisionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Revision{}, ErrRevisionNotFound
+		}
+		return Revision{}, err
+	}
+	return rev, nil
 }
@@ -0,0 +1,125 @@
+package cargo
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ParseListFilter разбирает query-параметры запроса списка грузов.
+func ParseListFilter(q url.Values) (ListFilter, error) {
+	var (
+		f   ListFilter
+		err error
+	)
+
+	f.Driver = optString(q, "driver")
+	f.PaymentStatus = optString(q, "paymentStatus")
+
+	ids := []struct {
+		name string
+		dst  **string
+	}{
+		{"truckId", &f.TruckID},
+		{"driverId", &f.DriverID},
+		{"customerId", &f.CustomerID},
+		{"shipperId", &f.ShipperID},
+		{"consigneeId", &f.ConsigneeID},
+	}
+	for _, id := range ids {
+		if *id.dst, err = optUUID(q, id.name); err != nil {
+			return ListFilter{}, err
+		}
+	}
+
+	if s := optString(q, "status"); s != nil {
+		st := Status(*s)
+		if !st.Valid() {
+			return ListFilter{}, fmt.Errorf("%w: %w", ErrInvalidFilter, ErrUnknownStatus)
+		}
+		f.Status = &st
+	}
+
+	times := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"dateFrom", &f.DateFrom},
+		{"dateTo", &f.DateTo},
+		{"loadUnloadDateFrom", &f.LoadUnloadDateFrom},
+		{"loadUnloadDateTo", &f.LoadUnloadDateTo},
+		{"payoutDateFrom", &f.PayoutDateFrom},
+		{"payoutDateTo", &f.PayoutDateTo},
+	}
+	for _, t := range times {
+		if *t.dst, err = optTime(q, t.name); err != nil {
+			return ListFilter{}, err
+		}
+	}
+
+	if f.PayoutAmountMin, err = optFloat(q, "payoutAmountMin"); err != nil {
+		return ListFilter{}, err
+	}
+	if f.PayoutAmountMax, err = optFloat(q, "payoutAmountMax"); err != nil {
+		return ListFilter{}, err
+	}
+
+	f.Sort = strings.TrimSpace(q.Get("sort"))
+	f.Order = strings.TrimSpace(q.Get("order"))
+	f.Cursor = strings.TrimSpace(q.Get("cursor"))
+
+	if s := strings.TrimSpace(q.Get("limit")); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil {
+			return ListFilter{}, fmt.Errorf("%w: параметр limit должен быть числом", ErrInvalidFilter)
+		}
+	}
+
+	if err := f.normalize(); err != nil {
+		return ListFilter{}, err
+	}
+	return f, nil
+}
+
+func optString(q url.Values, name string) *string {
+	s := strings.TrimSpace(q.Get(name))
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optUUID(q url.Values, name string) (*string, error) {
+	s := optString(q, name)
+	if s != nil && uuid.Validate(*s) != nil {
+		return nil, fmt.Errorf("%w: параметр %s должен быть UUID", ErrInvalidFilter, name)
+	}
+	return s, nil
+}
+
+func optTime(q url.Values, name string) (*time.Time, error) {
+	s := strings.TrimSpace(q.Get(name))
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: параметр %s должен быть в формате RFC3339", ErrInvalidFilter, name)
+	}
+	return &t, nil
+}
+
+func optFloat(q url.Values, name string) (*float64, error) {
+	s := strings.TrimSpace(q.Get(name))
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: параметр %s должен быть числом", ErrInvalidFilter, name)
+	}
+	return &v, nil
+}
@@ -0,0 +1,55 @@
+package cargo
+
+import "encoding/json"
+
+// financeFields — поля выплат в JSON груза. Их видят только пользователи
+// с разрешением finance:view.
+var financeFields = []string{"payoutAmount", "payoutDate", "paymentStatus", "payoutTerms"}
+
+// HideFinance убирает из груза сведения о выплатах.
+func (c *Cargo) HideFinance() {
+	c.PayoutAmount = nil
+	c.PayoutDate = nil
+	c.PaymentStatus = nil
+	c.PayoutTerms = nil
+}
+
+// HideFinance убирает сведения о выплатах из всех грузов страницы.
+func (r *ListResult) HideFinance() {
+	for i := range r.Items {
+		r.Items[i].HideFinance()
+	}
+}
+
+// HideFinance убирает поля выплат из диффа и снимка ревизии.
+func (r *Revision) HideFinance() {
+	for _, k := range financeFields {
+		delete(r.Diff, k)
+	}
+
+	if len(r.Snapshot) == 0 {
+		return
+	}
+	// снимок, который не удалось разобрать, лучше не отдавать совсем
+	var m map[string]interface{}
+	if err := json.Unmarshal(r.Snapshot, &m); err != nil {
+		r.Snapshot = nil
+		return
+	}
+	for _, k := range financeFields {
+		delete(m, k)
+	}
+	r.Snapshot, _ = json.Marshal(m)
+}
+
+// UsesFinance сообщает, что фильтр или сортировка опираются на выплаты:
+// по ним можно восстановить скрытые суммы и даты.
+func (f ListFilter) UsesFinance() bool {
+	switch f.Sort {
+	case "payoutDate", "payoutAmount":
+		return true
+	}
+	return f.PaymentStatus != nil ||
+		f.PayoutDateFrom != nil || f.PayoutDateTo != nil ||
+		f.PayoutAmountMin != nil || f.PayoutAmountMax != nil
+}
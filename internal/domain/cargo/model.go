@@ -1,6 +1,12 @@
 package cargo
 
-import "time"
+import (
+	"errors"
+	"test-project/internal/domain/file"
+	"time"
+)
+
+var ErrNotFound = errors.New("груз не найден")
 
 type Cargo struct {
 	ID string `json:"id" form:"-"`
@@ -8,17 +14,32 @@ type Cargo struct {
 	CargoNumber        string     `json:"cargoNumber" form:"cargoNumber" validate:"required"`
 	Date               *time.Time `json:"date,omitempty" form:"date" validate:"omitempty"`
 	LoadUnloadDate     *time.Time `json:"loadUnloadDate,omitempty" form:"loadUnloadDate" validate:"omitempty"`
-	Driver             string     `json:"driver" form:"driver" validate:"required"`
+	PlannedStart       *time.Time `json:"plannedStart,omitempty" form:"plannedStart"`
+	PlannedEnd         *time.Time `json:"plannedEnd,omitempty" form:"plannedEnd"`
+	DriverID           *string    `json:"driverId" form:"driverId" validate:"required"`
 	TransportationInfo string     `json:"transportationInfo" form:"transportationInfo" validate:"required"`
 	PayoutAmount       *float64   `json:"payoutAmount,omitempty" form:"payoutAmount" validate:"omitempty,gt=0"`
 	PayoutDate         *time.Time `json:"payoutDate,omitempty" form:"payoutDate" validate:"omitempty"`
 	PaymentStatus      *string    `json:"paymentStatus,omitempty" form:"paymentStatus" validate:"omitempty"`
 	PayoutTerms        *string    `json:"payoutTerms,omitempty" form:"payoutTerms" validate:"omitempty"`
+	WeightKg           *float64   `json:"weightKg,omitempty" form:"weightKg" validate:"omitempty,gt=0" example:"18500"`
+	VolumeM3           *float64   `json:"volumeM3,omitempty" form:"volumeM3" validate:"omitempty,gt=0" example:"64"`
+	Pallets            *int       `json:"pallets,omitempty" form:"pallets" validate:"omitempty,gte=0" example:"33"`
+	HazardClass        *string    `json:"hazardClass,omitempty" form:"hazardClass" validate:"omitempty,hazard_class" example:"3"`
+	Status             Status     `json:"status" form:"-" example:"draft"`
 
 	CreatedAt time.Time `json:"createdAt" form:"-"`
 
 	TruckID     string       `json:"truckId" form:"truckId" validate:"required"`
+	CustomerID  *string      `json:"customerId" form:"customerId"`
+	ShipperID   *string      `json:"shipperId" form:"shipperId"`
+	ConsigneeID *string      `json:"consigneeId" form:"consigneeId"`
 	CargoPhotos []CargoPhoto `json:"cargoPhotos"`
+	Stops       []Stop       `json:"stops"`
+
+	// ScheduleConflicts заполняется при создании и изменении груза в режиме
+	// SCHEDULE_CONFLICT_MODE=warn, если машина в это время уже занята.
+	ScheduleConflicts []ScheduleSlot `json:"scheduleConflicts,omitempty" form:"-"`
 }
 
 type CargoPhoto struct {
@@ -32,34 +53,116 @@ type UpdateCargoInput struct {
 	CargoNumber        *string    `json:"cargoNumber,omitempty" form:"cargoNumber"`
 	Date               *time.Time `json:"date,omitempty" form:"date" `
 	LoadUnloadDate     *time.Time `json:"loadUnloadDate,omitempty" form:"loadUnloadDate"`
-	Driver             *string    `json:"driver,omitempty" form:"driver"`
+	PlannedStart       *time.Time `json:"plannedStart,omitempty" form:"plannedStart"`
+	PlannedEnd         *time.Time `json:"plannedEnd,omitempty" form:"plannedEnd"`
+	DriverID           *string    `json:"driverId,omitempty" form:"driverId"`
 	TransportationInfo *string    `json:"transportationInfo,omitempty" form:"transportationInfo"`
 	PayoutAmount       *float64   `json:"payoutAmount,omitempty" form:"payoutAmount"`
 	PayoutDate         *time.Time `json:"payoutDate,omitempty" form:"payoutDate"`
 	PaymentStatus      *string    `json:"paymentStatus,omitempty" form:"paymentStatus"`
 	PayoutTerms        *string    `json:"payoutTerms,omitempty" form:"payoutTerms"`
+	WeightKg           *float64   `json:"weightKg,omitempty" form:"weightKg" validate:"omitempty,gt=0"`
+	VolumeM3           *float64   `json:"volumeM3,omitempty" form:"volumeM3" validate:"omitempty,gt=0"`
+	Pallets            *int       `json:"pallets,omitempty" form:"pallets" validate:"omitempty,gte=0"`
+	HazardClass        *string    `json:"hazardClass,omitempty" form:"hazardClass" validate:"omitempty,hazard_class"`
 	TruckID            *string    `json:"truckId,omitempty" form:"truckId"`
+	CustomerID         *string    `json:"customerId,omitempty" form:"customerId"`
+	ShipperID          *string    `json:"shipperId,omitempty" form:"shipperId"`
+	ConsigneeID        *string    `json:"consigneeId,omitempty" form:"consigneeId"`
+
+	// Status принимается только для того, чтобы явно отклонить попытку
+	// сменить статус в обход POST /cargo/{id}/transition.
+	Status *string `json:"status,omitempty" form:"status" swaggerignore:"true"`
+}
+
+// ListFilter описывает фильтры, сортировку и курсорную пагинацию списка грузов.
+type ListFilter struct {
+	TruckID       *string
+	DriverID      *string
+	CustomerID    *string
+	ShipperID     *string
+	ConsigneeID   *string
+	Driver        *string
+	PaymentStatus *string
+	Status        *Status
+
+	DateFrom           *time.Time
+	DateTo             *time.Time
+	LoadUnloadDateFrom *time.Time
+	LoadUnloadDateTo   *time.Time
+	PayoutDateFrom     *time.Time
+	PayoutDateTo       *time.Time
+
+	PayoutAmountMin *float64
+	PayoutAmountMax *float64
+
+	// Scope — какие грузы видит пользователь запроса.
+	Scope Scope
+
+	Sort   string
+	Order  string
+	Cursor string
+	Limit  int
+}
+
+type ListResult struct {
+	Items      []Cargo `json:"items"`
+	Total      int64   `json:"total" example:"42"`
+	NextCursor *string `json:"nextCursor" example:"eyJzIjoiY3JlYXRlZEF0In0"`
 }
 
 type CargoRepository interface {
-	Create(cargo Cargo) (Cargo, error)
-	FindAll() ([]Cargo, error)
+	Create(cargo Cargo, check TruckCheck, revise Revise) (Cargo, error)
+	FindAll(filter ListFilter) (ListResult, error)
 	FindByID(id string) (Cargo, error)
-	Update(cargo UpdateCargoInput, id string) (Cargo, error)
-	Delete(id string) error
+	// FindVisible — FindByID с учётом видимости: чужой груз не найден.
+	FindVisible(id string, scope Scope) (Cargo, error)
+	Update(cargo UpdateCargoInput, id string, check TruckCheck, revise Revise) (Cargo, error)
+	Delete(id string, deletedBy string, revise Revise) error
+
+	ChangeStatus(id string, from, to Status, userID string, comment *string, revise Revise) (StatusTransition, error)
+	FindTransitions(cargoID string) ([]StatusTransition, error)
+
+	// Replace перезаписывает все редактируемые поля груза (кроме статуса).
+	Replace(id string, cargo Cargo, check TruckCheck, revise Revise) (Cargo, error)
+	FindRevisions(cargoID string, scope Scope) ([]Revision, error)
+	FindRevision(cargoID, revisionID string) (Revision, error)
+
+	FindStops(cargoID string) ([]Stop, error)
+	AddStop(cargoID string, stop Stop, revise Revise) (Stop, error)
+	ReorderStops(cargoID string, stopIDs []string, revise Revise) ([]Stop, error)
+	CompleteStop(cargoID, stopID string, arrivedAt, departedAt time.Time, revise Revise) (Stop, error)
+	DeleteStop(cargoID, stopID string, revise Revise) error
+
+	// AddPhotos привязывает к грузу уже сохранённые в хранилище файлы.
+	AddPhotos(cargoID string, photos []file.Record, revise Revise) error
+	// DeletePhotos отвязывает файлы груза и возвращает удалённые записи,
+	// чтобы вызывающий убрал их из хранилища.
+	DeletePhotos(cargoID string, fileIDs []string, revise Revise) ([]file.Record, error)
+
+	FindSchedule(from, to time.Time, scope Scope) ([]TruckTimeline, error)
 }
 
 type CreateRequest struct {
 	CargoNumber        string     `json:"cargoNumber" validate:"required" example:"1234"`
-	Driver             string     `json:"driver" validate:"required" example:"Иванов Иван Иванович"`
+	DriverID           string     `json:"driverId" validate:"required" example:"3f1c2a5e-8b7d-4c1e-9f2a-6d5b4c3a2e1f"`
 	TransportationInfo string     `json:"transportationInfo" validate:"required" example:"Грузоперевозка"`
 	TruckID            string     `json:"truckId" validate:"required" example:"1"`
+	CustomerID         *string    `json:"customerId,omitempty" example:"8a1f0c2e-3b4d-4e5f-9a6b-7c8d9e0f1a2b"`
+	ShipperID          *string    `json:"shipperId,omitempty" example:"8a1f0c2e-3b4d-4e5f-9a6b-7c8d9e0f1a2b"`
+	ConsigneeID        *string    `json:"consigneeId,omitempty" example:"8a1f0c2e-3b4d-4e5f-9a6b-7c8d9e0f1a2b"`
 	Date               *time.Time `json:"date,omitempty" example:"2023-01-01T00:00:00Z"`
 	LoadUnloadDate     *time.Time `json:"loadUnloadDate,omitempty" example:"2023-01-01T00:00:00Z"`
+	PlannedStart       *time.Time `json:"plannedStart,omitempty" example:"2023-01-01T08:00:00Z"`
+	PlannedEnd         *time.Time `json:"plannedEnd,omitempty" example:"2023-01-02T18:00:00Z"`
 	PayoutAmount       *float64   `json:"payoutAmount,omitempty" example:"1000"`
 	PayoutDate         *time.Time `json:"payoutDate,omitempty" example:"2023-01-01T00:00:00Z"`
 	PaymentStatus      *string    `json:"paymentStatus,omitempty" example:"paid"`
 	PayoutTerms        *string    `json:"payoutTerms,omitempty" example:"cash"`
+	WeightKg           *float64   `json:"weightKg,omitempty" example:"18500"`
+	VolumeM3           *float64   `json:"volumeM3,omitempty" example:"64"`
+	Pallets            *int       `json:"pallets,omitempty" example:"33"`
+	HazardClass        *string    `json:"hazardClass,omitempty" example:"3"`
 }
 
 type CreateResponse struct {
@@ -68,8 +171,8 @@ type CreateResponse struct {
 }
 
 type ListResponse struct {
-	Message string  `json:"message" example:"Список всех грузов"`
-	Data    []Cargo `json:"data"`
+	Message string     `json:"message" example:"Список всех грузов"`
+	Data    ListResult `json:"data"`
 }
 
 type GetResponse struct {
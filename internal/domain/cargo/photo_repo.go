@@ -0,0 +1,86 @@
+package cargo
+
+import (
+	"context"
+	"test-project/internal/domain/file"
+)
+
+// AddPhotos пишет записи о файлах, уже сохранённых в хранилище, и ревизию
+// в одной транзакции.
+func (r *PostgresCargoRepo) AddPhotos(cargoID string, photos []file.Record, rv Revise) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, p := range photos {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO files(id, owner_id, owner_table, url, kind)
+			 VALUES ($1, $2, 'cargos', $3, $4)`,
+			p.ID, cargoID, p.URL, p.Kind); err != nil {
+			return err
+		}
+	}
+
+	after, err := findByID(ctx, tx, cargoID)
+	if err != nil {
+		return err
+	}
+	if err := writeRevision(ctx, tx, rv, after); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeletePhotos удаляет записи о файлах груза и пишет ревизию в одной
+// транзакции. Чужие файлы из fileIDs не затрагиваются.
+func (r *PostgresCargoRepo) DeletePhotos(cargoID string, fileIDs []string, rv Revise) ([]file.Record, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`DELETE FROM files
+		  WHERE owner_table = 'cargos' AND owner_id = $1 AND id = ANY($2)
+		  RETURNING id, owner_id, owner_table, url, kind`,
+		cargoID, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []file.Record
+	for rows.Next() {
+		var rec file.Record
+		if err := rows.Scan(&rec.ID, &rec.OwnerID, &rec.OwnerTable, &rec.URL, &rec.Kind); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deleted = append(deleted, rec)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	after, err := findByID(ctx, tx, cargoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeRevision(ctx, tx, rv, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
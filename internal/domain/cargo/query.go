@@ -0,0 +1,364 @@
+package cargo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+var ErrInvalidFilter = errors.New("невалидные параметры фильтрации")
+
+// cargoColumns — общий список колонок для всех выборок грузов.
+// Файлы и остановки маршрута подтягиваются коррелированными подзапросами,
+// чтобы LIMIT применялся к самим грузам, а не к результату JOIN.
+const cargoColumns = `
+    c.id,
+    c.cargonumber,
+    c.date,
+    c.loadunloaddate,
+    c.planned_start,
+    c.planned_end,
+    c.driver_id,
+    c.transportationinfo,
+    c.payoutamount,
+    c.payoutdate,
+    c.paymentstatus,
+    c.payoutterms,
+    c.weight_kg,
+    c.volume_m3,
+    c.pallets,
+    c.hazard_class,
+    c.status,
+    c."createdAt",
+    c.truckid,
+    c.customer_id,
+    c.shipper_id,
+    c.consignee_id,
+    COALESCE((
+      SELECT json_agg(
+               json_build_object(
+                 'id',        f.id,
+                 'url',       f.url,
+                 'cargoId',   f.owner_id,
+                 'createdAt', f.created_at
+               )
+               ORDER BY f.created_at
+             )
+        FROM files f
+       WHERE f.owner_table = 'cargos'
+         AND f.owner_id    = c.id
+    ), '[]') AS photos_json,
+    COALESCE((
+      SELECT json_agg(
+               json_build_object(
+                 'id',          s.id,
+                 'cargoId',     s.cargo_id,
+                 'position',    s.position,
+                 'type',        s.type,
+                 'address',     s.address,
+                 'lat',         s.lat,
+                 'lng',         s.lng,
+                 'plannedFrom', s.planned_from,
+                 'plannedTo',   s.planned_to,
+                 'arrivedAt',   s.arrived_at,
+                 'departedAt',  s.departed_at,
+                 'createdAt',   s.created_at
+               )
+               ORDER BY s.position
+             )
+        FROM cargo_stops s
+       WHERE s.cargo_id = c.id
+    ), '[]') AS stops_json`
+
+func scanCargo(row pgx.Row) (Cargo, error) {
+	var (
+		c          Cargo
+		photosJSON []byte
+		stopsJSON  []byte
+	)
+
+	if err := row.Scan(
+		&c.ID,
+		&c.CargoNumber,
+		&c.Date,
+		&c.LoadUnloadDate,
+		&c.PlannedStart,
+		&c.PlannedEnd,
+		&c.DriverID,
+		&c.TransportationInfo,
+		&c.PayoutAmount,
+		&c.PayoutDate,
+		&c.PaymentStatus,
+		&c.PayoutTerms,
+		&c.WeightKg,
+		&c.VolumeM3,
+		&c.Pallets,
+		&c.HazardClass,
+		&c.Status,
+		&c.CreatedAt,
+		&c.TruckID,
+		&c.CustomerID,
+		&c.ShipperID,
+		&c.ConsigneeID,
+		&photosJSON,
+		&stopsJSON,
+	); err != nil {
+		return Cargo{}, err
+	}
+
+	// распаковываем JSON-массивы файлов и остановок
+	if err := json.Unmarshal(photosJSON, &c.CargoPhotos); err != nil {
+		return Cargo{}, fmt.Errorf("unmarshal photos: %w", err)
+	}
+	if err := json.Unmarshal(stopsJSON, &c.Stops); err != nil {
+		return Cargo{}, fmt.Errorf("unmarshal stops: %w", err)
+	}
+
+	return c, nil
+}
+
+// sortColumn описывает поле, по которому разрешена сортировка.
+// value возвращает значение поля для курсора (nil, если в БД NULL).
+type sortColumn struct {
+	expr  string
+	cast  string
+	value func(c Cargo) *string
+}
+
+var sortColumns = map[string]sortColumn{
+	"createdAt": {`c."createdAt"`, "timestamp", func(c Cargo) *string { return timeCursor(&c.CreatedAt) }},
+	"date":      {"c.date", "timestamp", func(c Cargo) *string { return timeCursor(c.Date) }},
+	"loadUnloadDate": {"c.loadunloaddate", "timestamp", func(c Cargo) *string {
+		return timeCursor(c.LoadUnloadDate)
+	}},
+	"payoutDate":   {"c.payoutdate", "timestamp", func(c Cargo) *string { return timeCursor(c.PayoutDate) }},
+	"payoutAmount": {"c.payoutamount", "numeric", func(c Cargo) *string { return floatCursor(c.PayoutAmount) }},
+	"cargoNumber":  {"c.cargonumber", "text", func(c Cargo) *string { return &c.CargoNumber }},
+}
+
+func timeCursor(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339Nano)
+	return &s
+}
+
+func floatCursor(f *float64) *string {
+	if f == nil {
+		return nil
+	}
+	s := strconv.FormatFloat(*f, 'f', -1, 64)
+	return &s
+}
+
+type cursor struct {
+	Sort  string  `json:"s"`
+	Order string  `json:"o"`
+	Value *string `json:"v"`
+	ID    string  `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return cursor{}, err
+	}
+	if uuid.Validate(c.ID) != nil {
+		return cursor{}, errors.New("invalid id")
+	}
+	col, ok := sortColumns[c.Sort]
+	if !ok {
+		return cursor{}, errors.New("unknown sort")
+	}
+	if c.Value != nil {
+		switch col.cast {
+		case "timestamp":
+			_, err = time.Parse(time.RFC3339Nano, *c.Value)
+		case "numeric":
+			_, err = strconv.ParseFloat(*c.Value, 64)
+		}
+		if err != nil {
+			return cursor{}, err
+		}
+	}
+	return c, nil
+}
+
+// listQuery собирает WHERE-условия и аргументы для выборки списка грузов.
+type listQuery struct {
+	where []string
+	args  []interface{}
+}
+
+func (q *listQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *listQuery) whereSQL() string {
+	return " WHERE " + strings.Join(q.where, " AND ")
+}
+
+// normalize проставляет значения по умолчанию и проверяет сортировку, лимит и курсор.
+func (f *ListFilter) normalize() error {
+	if f.Sort == "" {
+		f.Sort = "createdAt"
+	}
+	if _, ok := sortColumns[f.Sort]; !ok {
+		return fmt.Errorf("%w: сортировка по полю %q не поддерживается", ErrInvalidFilter, f.Sort)
+	}
+
+	f.Order = strings.ToLower(f.Order)
+	if f.Order == "" {
+		f.Order = "desc"
+	}
+	if f.Order != "asc" && f.Order != "desc" {
+		return fmt.Errorf("%w: направление сортировки должно быть asc или desc", ErrInvalidFilter)
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultListLimit
+	}
+	if f.Limit > MaxListLimit {
+		f.Limit = MaxListLimit
+	}
+
+	if f.Cursor != "" {
+		cur, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return fmt.Errorf("%w: невалидный курсор", ErrInvalidFilter)
+		}
+		if cur.Sort != f.Sort || cur.Order != f.Order {
+			return fmt.Errorf("%w: курсор не соответствует сортировке", ErrInvalidFilter)
+		}
+	}
+	return nil
+}
+
+// buildListQueries возвращает запрос подсчёта (без курсора) и запрос страницы.
+// Запрос страницы выбирает на одну запись больше лимита, чтобы понять,
+// есть ли следующая страница. Фильтр должен быть нормализован.
+func buildListQueries(f ListFilter) (countSQL string, countArgs []interface{}, pageSQL string, pageArgs []interface{}, err error) {
+	q := &listQuery{where: []string{"c.deleted_at IS NULL"}}
+	f.Scope.condition(q)
+
+	if f.TruckID != nil {
+		q.where = append(q.where, "c.truckid = "+q.arg(*f.TruckID))
+	}
+	if f.DriverID != nil {
+		q.where = append(q.where, "c.driver_id = "+q.arg(*f.DriverID))
+	}
+	if f.CustomerID != nil {
+		q.where = append(q.where, "c.customer_id = "+q.arg(*f.CustomerID))
+	}
+	if f.ShipperID != nil {
+		q.where = append(q.where, "c.shipper_id = "+q.arg(*f.ShipperID))
+	}
+	if f.ConsigneeID != nil {
+		q.where = append(q.where, "c.consignee_id = "+q.arg(*f.ConsigneeID))
+	}
+	if f.Driver != nil {
+		q.where = append(q.where,
+			"EXISTS (SELECT 1 FROM drivers d WHERE d.id = c.driver_id AND d.full_name ILIKE "+q.arg("%"+*f.Driver+"%")+")")
+	}
+	if f.PaymentStatus != nil {
+		q.where = append(q.where, "c.paymentstatus = "+q.arg(*f.PaymentStatus))
+	}
+	if f.Status != nil {
+		q.where = append(q.where, "c.status = "+q.arg(string(*f.Status)))
+	}
+	if f.DateFrom != nil {
+		q.where = append(q.where, "c.date >= "+q.arg(*f.DateFrom))
+	}
+	if f.DateTo != nil {
+		q.where = append(q.where, "c.date <= "+q.arg(*f.DateTo))
+	}
+	if f.LoadUnloadDateFrom != nil {
+		q.where = append(q.where, "c.loadunloaddate >= "+q.arg(*f.LoadUnloadDateFrom))
+	}
+	if f.LoadUnloadDateTo != nil {
+		q.where = append(q.where, "c.loadunloaddate <= "+q.arg(*f.LoadUnloadDateTo))
+	}
+	if f.PayoutDateFrom != nil {
+		q.where = append(q.where, "c.payoutdate >= "+q.arg(*f.PayoutDateFrom))
+	}
+	if f.PayoutDateTo != nil {
+		q.where = append(q.where, "c.payoutdate <= "+q.arg(*f.PayoutDateTo))
+	}
+	if f.PayoutAmountMin != nil {
+		q.where = append(q.where, "c.payoutamount >= "+q.arg(*f.PayoutAmountMin))
+	}
+	if f.PayoutAmountMax != nil {
+		q.where = append(q.where, "c.payoutamount <= "+q.arg(*f.PayoutAmountMax))
+	}
+
+	countSQL = "SELECT COUNT(*) FROM cargos c" + q.whereSQL()
+	countArgs = append([]interface{}{}, q.args...)
+
+	col := sortColumns[f.Sort]
+	cmp := "<"
+	if f.Order == "asc" {
+		cmp = ">"
+	}
+
+	if f.Cursor != "" {
+		cur, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return "", nil, "", nil, fmt.Errorf("%w: невалидный курсор", ErrInvalidFilter)
+		}
+		if cur.Sort != f.Sort || cur.Order != f.Order {
+			return "", nil, "", nil, fmt.Errorf("%w: курсор не соответствует сортировке", ErrInvalidFilter)
+		}
+
+		id := q.arg(cur.ID)
+		if cur.Value == nil {
+			// NULL-значения идут в конце выборки, дальше только они же
+			q.where = append(q.where, fmt.Sprintf("(%s IS NULL AND c.id %s %s::uuid)", col.expr, cmp, id))
+		} else {
+			v := q.arg(*cur.Value) + "::" + col.cast
+			q.where = append(q.where, fmt.Sprintf(
+				"(%[1]s %[2]s %[3]s OR (%[1]s = %[3]s AND c.id %[2]s %[4]s::uuid) OR %[1]s IS NULL)",
+				col.expr, cmp, v, id,
+			))
+		}
+	}
+
+	dir := strings.ToUpper(f.Order)
+	pageSQL = "SELECT" + cargoColumns + "\nFROM cargos c" + q.whereSQL() +
+		fmt.Sprintf("\nORDER BY %s %s NULLS LAST, c.id %s\nLIMIT %s", col.expr, dir, dir, q.arg(f.Limit+1))
+
+	return countSQL, countArgs, pageSQL, q.args, nil
+}
+
+// nextCursor строит курсор, указывающий на запись после c.
+func nextCursor(f ListFilter, c Cargo) string {
+	return encodeCursor(cursor{
+		Sort:  f.Sort,
+		Order: f.Order,
+		Value: sortColumns[f.Sort].value(c),
+		ID:    c.ID,
+	})
+}
@@ -0,0 +1,111 @@
+package cargo
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+)
+
+type RevisionAction string
+
+const (
+	RevisionCreate      RevisionAction = "create"
+	RevisionPatch       RevisionAction = "patch"
+	RevisionTransition  RevisionAction = "transition"
+	RevisionPhotoAdd    RevisionAction = "photo_add"
+	RevisionPhotoDelete RevisionAction = "photo_delete"
+	RevisionDelete      RevisionAction = "delete"
+	RevisionRestore     RevisionAction = "restore"
+	RevisionRevert      RevisionAction = "revert"
+	RevisionStops       RevisionAction = "stops"
+)
+
+var (
+	ErrRevisionNotFound      = errors.New("ревизия груза не найдена")
+	ErrRevisionNotRevertable = errors.New("ревизия не содержит полного состояния груза и не может быть восстановлена")
+)
+
+// FieldChange — изменение одного поля: значение до и после.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Revision — неизменяемая запись журнала изменений груза.
+// Snapshot хранит состояние груза после изменения (для delete — до удаления).
+type Revision struct {
+	ID        string                 `json:"id"`
+	CargoID   string                 `json:"cargoId"`
+	Action    RevisionAction         `json:"action" example:"patch"`
+	Diff      map[string]FieldChange `json:"diff"`
+	Snapshot  json.RawMessage        `json:"snapshot" swaggertype:"object"`
+	UserID    string                 `json:"userId"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// snapshotSkip — поля, которые не относятся к редактируемому состоянию груза.
+var snapshotSkip = []string{"id", "createdAt", "cargoPhotos", "stops", "scheduleConflicts"}
+
+// Snapshot возвращает редактируемые поля груза в виде map для диффа и хранения.
+func Snapshot(c Cargo) map[string]interface{} {
+	raw, _ := json.Marshal(c)
+
+	var m map[string]interface{}
+	_ = json.Unmarshal(raw, &m)
+
+	for _, k := range snapshotSkip {
+		delete(m, k)
+	}
+	return m
+}
+
+// Diff возвращает поля, значения которых отличаются в before и after.
+// Отсутствующее поле считается равным null.
+func Diff(before, after map[string]interface{}) map[string]FieldChange {
+	diff := map[string]FieldChange{}
+
+	for k, nv := range after {
+		if ov := before[k]; !reflect.DeepEqual(ov, nv) {
+			diff[k] = FieldChange{Old: ov, New: nv}
+		}
+	}
+	for k, ov := range before {
+		if _, ok := after[k]; !ok {
+			diff[k] = FieldChange{Old: ov, New: nil}
+		}
+	}
+	return diff
+}
+
+// Revise строит ревизию по состоянию груза после изменения, для удаления —
+// до него (nil — писать нечего). Репозиторий вызывает её в транзакции изменения и пишет ревизию
+// в той же транзакции, так что изменение не останется без записи в истории.
+type Revise func(after Cargo) *Revision
+
+// NewRevision собирает ревизию из диффа и снимка состояния груза.
+func NewRevision(action RevisionAction, cargoID, userID string, diff map[string]FieldChange, snapshot map[string]interface{}) Revision {
+	raw, _ := json.Marshal(snapshot)
+
+	return Revision{
+		CargoID:  cargoID,
+		Action:   action,
+		Diff:     diff,
+		Snapshot: raw,
+		UserID:   userID,
+	}
+}
+
+// Restore разворачивает снимок ревизии обратно в груз.
+func (r Revision) Restore() (Cargo, error) {
+	var c Cargo
+	if err := json.Unmarshal(r.Snapshot, &c); err != nil {
+		return Cargo{}, err
+	}
+	return c, nil
+}
+
+type HistoryResponse struct {
+	Message string     `json:"message" example:"История изменений груза"`
+	Data    []Revision `json:"data"`
+}
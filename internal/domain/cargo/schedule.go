@@ -0,0 +1,146 @@
+package cargo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConflictMode — что делать, если плановое время груза пересекается
+// с другим грузом той же машины.
+type ConflictMode string
+
+const (
+	ConflictReject ConflictMode = "reject"
+	ConflictWarn   ConflictMode = "warn"
+)
+
+// MaxScheduleRange — максимальный период, который можно запросить в GET /schedule.
+const MaxScheduleRange = 93 * 24 * time.Hour
+
+var (
+	ErrScheduleWindow   = errors.New("укажите плановые начало и окончание перевозки, окончание должно быть позже начала")
+	ErrScheduleConflict = errors.New("машина уже занята другим грузом в это время")
+	ErrScheduleRange    = errors.New("укажите период from и to в формате RFC3339, to позже from, не длиннее 93 дней")
+)
+
+// ParseConflictMode разбирает значение SCHEDULE_CONFLICT_MODE.
+// Неизвестное значение считается reject.
+func ParseConflictMode(s string) ConflictMode {
+	if ConflictMode(strings.ToLower(strings.TrimSpace(s))) == ConflictWarn {
+		return ConflictWarn
+	}
+	return ConflictReject
+}
+
+// ScheduleSlot — время, на которое машина занята грузом.
+type ScheduleSlot struct {
+	CargoID     string    `json:"cargoId"`
+	CargoNumber string    `json:"cargoNumber" example:"1234"`
+	Status      Status    `json:"status" example:"planned"`
+	Start       time.Time `json:"start" example:"2025-04-30T08:00:00Z"`
+	End         time.Time `json:"end" example:"2025-05-01T18:00:00Z"`
+}
+
+// Gap — свободный промежуток в расписании машины.
+type Gap struct {
+	From time.Time `json:"from" example:"2025-05-01T18:00:00Z"`
+	To   time.Time `json:"to" example:"2025-05-03T08:00:00Z"`
+}
+
+// TruckTimeline — расписание одной машины за запрошенный период.
+type TruckTimeline struct {
+	TruckID   string         `json:"truckId"`
+	TruckName string         `json:"truckName" example:"Машина"`
+	Active    bool           `json:"active" example:"true"`
+	Slots     []ScheduleSlot `json:"slots"`
+	Gaps      []Gap          `json:"gaps"`
+	// BusyHours — сколько часов периода машина занята (пересечения считаются один раз).
+	BusyHours float64 `json:"busyHours" example:"34"`
+}
+
+// ScheduleCheck решает, можно ли записать груз при найденных пересечениях:
+// ошибка отменяет запись.
+type ScheduleCheck func(conflicts []ScheduleSlot) error
+
+// TruckCheck — проверки машины груза, которые репозиторий выполняет в
+// транзакции записи, заблокировав машину: параллельная запись на ту же
+// машину или изменение самой машины дождутся этой и увидят её результат.
+type TruckCheck struct {
+	// Capacity — сверить вес и объём груза с грузоподъёмностью и кузовом.
+	Capacity bool
+	// Schedule получает пересечения по плановому времени; nil — не проверять.
+	Schedule ScheduleCheck
+}
+
+// ScheduleConflictError перечисляет грузы, с которыми пересекается назначение.
+type ScheduleConflictError struct {
+	Conflicts []ScheduleSlot
+}
+
+func (e *ScheduleConflictError) Error() string {
+	numbers := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		numbers[i] = c.CargoNumber
+	}
+	return fmt.Sprintf("%s: %s", ErrScheduleConflict, strings.Join(numbers, ", "))
+}
+
+func (e *ScheduleConflictError) Unwrap() error {
+	return ErrScheduleConflict
+}
+
+// ValidWindow проверяет, что плановое время либо не задано совсем,
+// либо задано полностью и окончание позже начала.
+func ValidWindow(start, end *time.Time) bool {
+	if start == nil && end == nil {
+		return true
+	}
+	return start != nil && end != nil && end.After(*start)
+}
+
+// FillGaps вычисляет свободные промежутки и занятость машины в периоде [from, to).
+func (t *TruckTimeline) FillGaps(from, to time.Time) {
+	sort.Slice(t.Slots, func(i, j int) bool { return t.Slots[i].Start.Before(t.Slots[j].Start) })
+
+	t.Gaps = []Gap{}
+	busy := time.Duration(0)
+	cursor := from
+
+	for _, s := range t.Slots {
+		start, end := s.Start, s.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(cursor) {
+			continue
+		}
+		if start.After(cursor) {
+			t.Gaps = append(t.Gaps, Gap{From: cursor, To: start})
+		} else {
+			start = cursor
+		}
+		busy += end.Sub(start)
+		cursor = end
+	}
+	if cursor.Before(to) {
+		t.Gaps = append(t.Gaps, Gap{From: cursor, To: to})
+	}
+
+	t.BusyHours = busy.Hours()
+}
+
+type ScheduleResponse struct {
+	Message string          `json:"message" example:"Расписание машин"`
+	Data    []TruckTimeline `json:"data"`
+}
+
+type ConflictResponse struct {
+	Message string         `json:"message" example:"машина уже занята другим грузом в это время: 1234"`
+	Data    []ScheduleSlot `json:"data"`
+}
@@ -0,0 +1,141 @@
+package cargo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// checkTruck блокирует машину груза и выполняет проверки уже в транзакции
+// записи. Блокировка FOR NO KEY UPDATE не конфликтует с FOR KEY SHARE,
+// который берёт внешний ключ cargos.truckid, поэтому взаимоблокировок нет.
+func checkTruck(ctx context.Context, tx pgx.Tx, check TruckCheck, c Cargo) error {
+	capacity := check.Capacity && (c.WeightKg != nil || c.VolumeM3 != nil)
+	schedule := check.Schedule != nil && c.PlannedStart != nil && c.PlannedEnd != nil
+	if !capacity && !schedule {
+		return nil
+	}
+
+	var payloadKg, volumeM3 *float64
+	err := tx.QueryRow(ctx,
+		`SELECT payload_kg, volume_m3 FROM trucks WHERE id = $1 AND deleted_at IS NULL FOR NO KEY UPDATE`,
+		c.TruckID,
+	).Scan(&payloadKg, &volumeM3)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: id=%s", ErrTruckNotFound, c.TruckID)
+		}
+		return err
+	}
+
+	if capacity {
+		if err := CheckCapacity(c.WeightKg, c.VolumeM3, payloadKg, volumeM3); err != nil {
+			return err
+		}
+	}
+	if !schedule {
+		return nil
+	}
+
+	conflicts, err := findOverlapping(ctx, tx, c.TruckID, *c.PlannedStart, *c.PlannedEnd, c.ID)
+	if err != nil {
+		return err
+	}
+	return check.Schedule(conflicts)
+}
+
+// findOverlapping возвращает неотменённые грузы машины, плановое время
+// которых пересекается с [start, end). Груз excludeID не учитывается.
+func findOverlapping(ctx context.Context, tx pgx.Tx, truckID string, start, end time.Time, excludeID string) ([]ScheduleSlot, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT c.id, c.cargonumber, c.status, c.planned_start, c.planned_end
+		   FROM cargos c
+		  WHERE c.truckid = $1
+		    AND c.deleted_at IS NULL
+		    AND c.status <> 'cancelled'
+		    AND c.planned_start < $3
+		    AND c.planned_end > $2
+		    AND c.id::text <> $4
+		  ORDER BY c.planned_start`,
+		truckID, start, end, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []ScheduleSlot{}
+	for rows.Next() {
+		var s ScheduleSlot
+		if err := rows.Scan(&s.CargoID, &s.CargoNumber, &s.Status, &s.Start, &s.End); err != nil {
+			return nil, err
+		}
+		slots = append(slots, s)
+	}
+	return slots, rows.Err()
+}
+
+// FindSchedule возвращает неудалённые машины с грузами, плановое время
+// которых пересекается с периодом [from, to). Без scope.All — только машины,
+// закреплённые за пользователем. Промежутки не заполняются.
+func (r *PostgresCargoRepo) FindSchedule(from, to time.Time, scope Scope) ([]TruckTimeline, error) {
+	args := []interface{}{from, to}
+	visible := ""
+	if !scope.All {
+		args = append(args, scope.UserID)
+		visible = " AND EXISTS (SELECT 1 FROM user_trucks ut WHERE ut.truck_id = t.id AND ut.user_id::text = $3)"
+	}
+
+	rows, err := r.db.Query(context.Background(),
+		`SELECT t.id, t.name, t.active,
+		        c.id, c.cargonumber, c.status, c.planned_start, c.planned_end
+		   FROM trucks t
+		   LEFT JOIN cargos c
+		     ON c.truckid = t.id
+		    AND c.deleted_at IS NULL
+		    AND c.status <> 'cancelled'
+		    AND c.planned_start < $2
+		    AND c.planned_end > $1
+		  WHERE t.deleted_at IS NULL`+visible+`
+		  ORDER BY t.name, t.id, c.planned_start`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timelines := []TruckTimeline{}
+	for rows.Next() {
+		var (
+			t           TruckTimeline
+			cargoID     *string
+			cargoNumber *string
+			status      *Status
+			start, end  *time.Time
+		)
+		if err := rows.Scan(&t.TruckID, &t.TruckName, &t.Active, &cargoID, &cargoNumber, &status, &start, &end); err != nil {
+			return nil, err
+		}
+
+		// строки одной машины идут подряд
+		if n := len(timelines); n == 0 || timelines[n-1].TruckID != t.TruckID {
+			t.Slots = []ScheduleSlot{}
+			timelines = append(timelines, t)
+		}
+		if cargoID == nil {
+			continue
+		}
+
+		last := &timelines[len(timelines)-1]
+		last.Slots = append(last.Slots, ScheduleSlot{
+			CargoID:     *cargoID,
+			CargoNumber: *cargoNumber,
+			Status:      *status,
+			Start:       *start,
+			End:         *end,
+		})
+	}
+	return timelines, rows.Err()
+}
@@ -0,0 +1,23 @@
+package cargo
+
+// Scope ограничивает видимость грузов. Пользователь без права
+// cargo:view_all видит только грузы машин, закреплённых за ним в
+// user_trucks. Нулевое значение не показывает ничего — так забытое
+// ограничение не открывает все грузы.
+type Scope struct {
+	All    bool
+	UserID string
+}
+
+// AllCargos — без ограничений: для редакторов, администраторов и
+// внутренних проверок.
+var AllCargos = Scope{All: true}
+
+// condition добавляет в q условие видимости груза с псевдонимом c.
+func (s Scope) condition(q *listQuery) {
+	if s.All {
+		return
+	}
+	q.where = append(q.where,
+		"EXISTS (SELECT 1 FROM user_trucks ut WHERE ut.truck_id = c.truckid AND ut.user_id::text = "+q.arg(s.UserID)+")")
+}
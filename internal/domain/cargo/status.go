@@ -0,0 +1,127 @@
+package cargo
+
+import (
+	"errors"
+	"test-project/internal/domain/user"
+	"time"
+)
+
+// Status — этап жизненного цикла груза.
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusPlanned   Status = "planned"
+	StatusLoading   Status = "loading"
+	StatusInTransit Status = "in_transit"
+	StatusDelivered Status = "delivered"
+	StatusInvoiced  Status = "invoiced"
+	StatusPaid      Status = "paid"
+	StatusClosed    Status = "closed"
+	StatusCancelled Status = "cancelled"
+)
+
+var AllStatuses = []Status{
+	StatusDraft, StatusPlanned, StatusLoading, StatusInTransit, StatusDelivered,
+	StatusInvoiced, StatusPaid, StatusClosed, StatusCancelled,
+}
+
+var (
+	ErrUnknownStatus       = errors.New("неизвестный статус груза")
+	ErrIllegalTransition   = errors.New("недопустимый переход статуса груза")
+	ErrTransitionForbidden = errors.New("недостаточно прав для перехода статуса груза")
+	ErrStatusConflict      = errors.New("статус груза был изменён другим запросом, повторите попытку")
+	ErrStatusNotPatchable  = errors.New("статус груза нельзя изменить напрямую, используйте POST /cargo/{id}/transition")
+)
+
+// Интеграции по ключу API (роль SERVICE) проводят те же этапы, что и
+// редакторы, кроме закрытия груза.
+var (
+	staff      = []user.Role{user.RoleEditor, user.RoleSuperAdmin, user.RoleService}
+	everyone   = []user.Role{user.RoleUser, user.RoleEditor, user.RoleSuperAdmin, user.RoleService}
+	superAdmin = []user.Role{user.RoleSuperAdmin}
+)
+
+// transitions — разрешённые переходы и роли, которым они доступны.
+// Физическое перемещение груза могут отмечать все, финансовые этапы и
+// отмену — только редакторы и суперадминистраторы.
+var transitions = map[Status]map[Status][]user.Role{
+	StatusDraft: {
+		StatusPlanned:   staff,
+		StatusCancelled: staff,
+	},
+	StatusPlanned: {
+		StatusDraft:     staff,
+		StatusLoading:   everyone,
+		StatusCancelled: staff,
+	},
+	StatusLoading: {
+		StatusInTransit: everyone,
+		StatusCancelled: staff,
+	},
+	StatusInTransit: {
+		StatusDelivered: everyone,
+	},
+	StatusDelivered: {
+		StatusInvoiced: staff,
+	},
+	StatusInvoiced: {
+		StatusPaid: staff,
+	},
+	StatusPaid: {
+		StatusClosed: superAdmin,
+	},
+}
+
+func (s Status) Valid() bool {
+	for _, st := range AllStatuses {
+		if s == st {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition проверяет, что переход from → to допустим и доступен роли.
+func CanTransition(from, to Status, role user.Role) error {
+	if !to.Valid() {
+		return ErrUnknownStatus
+	}
+
+	roles, ok := transitions[from][to]
+	if !ok {
+		return ErrIllegalTransition
+	}
+
+	for _, r := range roles {
+		if r == role {
+			return nil
+		}
+	}
+	return ErrTransitionForbidden
+}
+
+type StatusTransition struct {
+	ID        string    `json:"id"`
+	CargoID   string    `json:"cargoId"`
+	From      Status    `json:"from" example:"planned"`
+	To        Status    `json:"to" example:"loading"`
+	UserID    string    `json:"userId"`
+	Comment   *string   `json:"comment,omitempty" example:"Машина на погрузке"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TransitionRequest struct {
+	To      Status  `json:"to" validate:"required" example:"loading"`
+	Comment *string `json:"comment,omitempty" example:"Машина на погрузке"`
+}
+
+type TransitionResponse struct {
+	Message string `json:"message" example:"Статус груза изменён"`
+	Data    Cargo  `json:"data"`
+}
+
+type TransitionListResponse struct {
+	Message string             `json:"message" example:"История статусов груза"`
+	Data    []StatusTransition `json:"data"`
+}
@@ -0,0 +1,76 @@
+package cargo
+
+import (
+	"errors"
+	"time"
+)
+
+// StopType — вид остановки маршрута.
+type StopType string
+
+const (
+	StopLoad   StopType = "load"
+	StopUnload StopType = "unload"
+)
+
+var (
+	ErrStopNotFound      = errors.New("остановка маршрута не найдена")
+	ErrStopWindow        = errors.New("конец планового окна остановки раньше его начала")
+	ErrStopOrderMismatch = errors.New("порядок должен содержать все остановки груза ровно по одному разу")
+	ErrStopCompleted     = errors.New("остановка уже завершена")
+	ErrStopTimes         = errors.New("время отправления с остановки раньше времени прибытия")
+)
+
+// Stop — точка погрузки или разгрузки в маршруте груза.
+// Position — порядковый номер остановки, начиная с 1.
+type Stop struct {
+	ID          string     `json:"id"`
+	CargoID     string     `json:"cargoId"`
+	Position    int        `json:"position" example:"1"`
+	Type        StopType   `json:"type" example:"load"`
+	Address     string     `json:"address" example:"г. Москва, ул. Складочная, д. 1"`
+	Lat         *float64   `json:"lat,omitempty" example:"55.7963"`
+	Lng         *float64   `json:"lng,omitempty" example:"37.5921"`
+	PlannedFrom *time.Time `json:"plannedFrom,omitempty" example:"2025-04-30T08:00:00Z"`
+	PlannedTo   *time.Time `json:"plannedTo,omitempty" example:"2025-04-30T12:00:00Z"`
+	ArrivedAt   *time.Time `json:"arrivedAt,omitempty"`
+	DepartedAt  *time.Time `json:"departedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// Completed сообщает, что машина уже покинула остановку.
+func (s Stop) Completed() bool {
+	return s.DepartedAt != nil
+}
+
+type AddStopRequest struct {
+	Type        StopType   `json:"type" validate:"required,oneof=load unload" example:"load"`
+	Address     string     `json:"address" validate:"required" example:"г. Москва, ул. Складочная, д. 1"`
+	Lat         *float64   `json:"lat,omitempty" validate:"omitempty,latitude" example:"55.7963"`
+	Lng         *float64   `json:"lng,omitempty" validate:"omitempty,longitude" example:"37.5921"`
+	PlannedFrom *time.Time `json:"plannedFrom,omitempty" example:"2025-04-30T08:00:00Z"`
+	PlannedTo   *time.Time `json:"plannedTo,omitempty" example:"2025-04-30T12:00:00Z"`
+	// Position — куда вставить остановку; по умолчанию в конец маршрута.
+	Position *int `json:"position,omitempty" validate:"omitempty,gte=1" example:"2"`
+}
+
+type ReorderStopsRequest struct {
+	StopIDs []string `json:"stopIds" validate:"required,min=1,dive,uuid"`
+}
+
+// CompleteStopRequest — фактические времена прибытия и отправления.
+// Не указанные времена считаются текущим моментом.
+type CompleteStopRequest struct {
+	ArrivedAt  *time.Time `json:"arrivedAt,omitempty" example:"2025-04-30T09:15:00Z"`
+	DepartedAt *time.Time `json:"departedAt,omitempty" example:"2025-04-30T10:40:00Z"`
+}
+
+type StopsResponse struct {
+	Message string `json:"message" example:"Маршрут груза"`
+	Data    []Stop `json:"data"`
+}
+
+type StopResponse struct {
+	Message string `json:"message" example:"Остановка завершена"`
+	Data    Stop   `json:"data"`
+}
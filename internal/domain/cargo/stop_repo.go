@@ -0,0 +1,259 @@
+package cargo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const stopColumns = `
+    s.id,
+    s.cargo_id,
+    s.position,
+    s.type,
+    s.address,
+    s.lat,
+    s.lng,
+    s.planned_from,
+    s.planned_to,
+    s.arrived_at,
+    s.departed_at,
+    s.created_at`
+
+func scanStop(row pgx.Row) (Stop, error) {
+	var s Stop
+	err := row.Scan(
+		&s.ID,
+		&s.CargoID,
+		&s.Position,
+		&s.Type,
+		&s.Address,
+		&s.Lat,
+		&s.Lng,
+		&s.PlannedFrom,
+		&s.PlannedTo,
+		&s.ArrivedAt,
+		&s.DepartedAt,
+		&s.CreatedAt,
+	)
+	return s, err
+}
+
+func (r *PostgresCargoRepo) FindStops(cargoID string) ([]Stop, error) {
+	rows, err := r.db.Query(context.Background(),
+		"SELECT"+stopColumns+"\nFROM cargo_stops s\nWHERE s.cargo_id = $1\nORDER BY s.position", cargoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stops := []Stop{}
+	for rows.Next() {
+		s, err := scanStop(rows)
+		if err != nil {
+			return nil, err
+		}
+		stops = append(stops, s)
+	}
+	return stops, rows.Err()
+}
+
+// lockStops блокирует груз, чтобы параллельные изменения маршрута
+// не перемешали позиции остановок.
+func lockStops(ctx context.Context, tx pgx.Tx, cargoID string) error {
+	_, err := tx.Exec(ctx, `SELECT 1 FROM cargos WHERE id = $1 FOR UPDATE`, cargoID)
+	return err
+}
+
+// writeStopsRevision перечитывает груз с новым маршрутом и пишет ревизию в транзакции tx.
+func writeStopsRevision(ctx context.Context, tx pgx.Tx, cargoID string, rv Revise) (Cargo, error) {
+	after, err := findByID(ctx, tx, cargoID)
+	if err != nil {
+		return Cargo{}, err
+	}
+	return after, writeRevision(ctx, tx, rv, after)
+}
+
+// AddStop вставляет остановку на позицию s.Position, сдвигая последующие.
+// Нулевая или слишком большая позиция означает конец маршрута.
+func (r *PostgresCargoRepo) AddStop(cargoID string, s Stop, rv Revise) (Stop, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Stop{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockStops(ctx, tx, cargoID); err != nil {
+		return Stop{}, err
+	}
+
+	var last int
+	if err := tx.QueryRow(ctx,
+		`SELECT COALESCE(max(position), 0) FROM cargo_stops WHERE cargo_id = $1`, cargoID,
+	).Scan(&last); err != nil {
+		return Stop{}, err
+	}
+
+	if s.Position <= 0 || s.Position > last+1 {
+		s.Position = last + 1
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE cargo_stops SET position = position + 1 WHERE cargo_id = $1 AND position >= $2`,
+		cargoID, s.Position); err != nil {
+		return Stop{}, err
+	}
+
+	created, err := scanStop(tx.QueryRow(ctx,
+		`INSERT INTO cargo_stops AS s (cargo_id, position, type, address, lat, lng, planned_from, planned_to)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING`+stopColumns,
+		cargoID, s.Position, string(s.Type), s.Address, s.Lat, s.Lng, s.PlannedFrom, s.PlannedTo,
+	))
+	if err != nil {
+		return Stop{}, err
+	}
+
+	if _, err := writeStopsRevision(ctx, tx, cargoID, rv); err != nil {
+		return Stop{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Stop{}, err
+	}
+	return created, nil
+}
+
+// ReorderStops расставляет остановки в порядке stopIDs. Список должен
+// содержать все остановки груза ровно по одному разу.
+func (r *PostgresCargoRepo) ReorderStops(cargoID string, stopIDs []string, rv Revise) ([]Stop, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockStops(ctx, tx, cargoID); err != nil {
+		return nil, err
+	}
+
+	var matches bool
+	if err := tx.QueryRow(ctx,
+		`SELECT
+		   (SELECT count(*) FROM cargo_stops WHERE cargo_id = $1) = cardinality($2::text[]::uuid[])
+		   AND (SELECT count(DISTINCT id) FROM unnest($2::text[]::uuid[]) AS u(id)) = cardinality($2::text[]::uuid[])
+		   AND NOT EXISTS (
+		     SELECT 1 FROM unnest($2::text[]::uuid[]) AS u(id)
+		      WHERE NOT EXISTS (SELECT 1 FROM cargo_stops WHERE cargo_id = $1 AND id = u.id)
+		   )`,
+		cargoID, stopIDs,
+	).Scan(&matches); err != nil {
+		return nil, err
+	}
+	if !matches {
+		return nil, ErrStopOrderMismatch
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE cargo_stops s
+		    SET position = o.n
+		   FROM unnest($2::text[]::uuid[]) WITH ORDINALITY AS o(id, n)
+		  WHERE s.id = o.id AND s.cargo_id = $1`,
+		cargoID, stopIDs); err != nil {
+		return nil, err
+	}
+
+	after, err := writeStopsRevision(ctx, tx, cargoID, rv)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return after.Stops, nil
+}
+
+// CompleteStop проставляет фактические времена прибытия и отправления.
+func (r *PostgresCargoRepo) CompleteStop(cargoID, stopID string, arrivedAt, departedAt time.Time, rv Revise) (Stop, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Stop{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	s, err := scanStop(tx.QueryRow(ctx,
+		`UPDATE cargo_stops AS s
+		    SET arrived_at = $3, departed_at = $4
+		  WHERE s.cargo_id = $1 AND s.id = $2 AND s.departed_at IS NULL
+		  RETURNING`+stopColumns,
+		cargoID, stopID, arrivedAt, departedAt))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return Stop{}, err
+		}
+
+		// отличаем отсутствующую остановку от уже завершённой
+		var exists bool
+		if err := tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM cargo_stops WHERE cargo_id = $1 AND id = $2)`, cargoID, stopID,
+		).Scan(&exists); err != nil {
+			return Stop{}, err
+		}
+		if exists {
+			return Stop{}, ErrStopCompleted
+		}
+		return Stop{}, ErrStopNotFound
+	}
+
+	if _, err := writeStopsRevision(ctx, tx, cargoID, rv); err != nil {
+		return Stop{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Stop{}, err
+	}
+	return s, nil
+}
+
+// DeleteStop удаляет остановку и сдвигает последующие на её место.
+func (r *PostgresCargoRepo) DeleteStop(cargoID, stopID string, rv Revise) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockStops(ctx, tx, cargoID); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.QueryRow(ctx,
+		`DELETE FROM cargo_stops WHERE cargo_id = $1 AND id = $2 RETURNING position`, cargoID, stopID,
+	).Scan(&position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrStopNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE cargo_stops SET position = position - 1 WHERE cargo_id = $1 AND position > $2`,
+		cargoID, position); err != nil {
+		return err
+	}
+
+	if _, err := writeStopsRevision(ctx, tx, cargoID, rv); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
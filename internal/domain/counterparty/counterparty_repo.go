@@ -0,0 +1,240 @@
+package counterparty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresCounterpartyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCounterpartyRepo(db *pgxpool.Pool) CounterpartyRepository {
+	return &PostgresCounterpartyRepo{db: db}
+}
+
+const counterpartyColumns = `
+    c.id,
+    c.legal_name,
+    c.short_name,
+    c.inn,
+    c.kpp,
+    c.ogrn,
+    c.legal_address,
+    c.postal_address,
+    c.contacts,
+    c.bank,
+    c.created_at`
+
+func scanCounterparty(row pgx.Row) (Counterparty, error) {
+	var (
+		c            Counterparty
+		contactsJSON []byte
+		bankJSON     []byte
+	)
+
+	if err := row.Scan(
+		&c.ID,
+		&c.LegalName,
+		&c.ShortName,
+		&c.INN,
+		&c.KPP,
+		&c.OGRN,
+		&c.LegalAddress,
+		&c.PostalAddress,
+		&contactsJSON,
+		&bankJSON,
+		&c.CreatedAt,
+	); err != nil {
+		return Counterparty{}, err
+	}
+
+	if err := json.Unmarshal(contactsJSON, &c.Contacts); err != nil {
+		return Counterparty{}, fmt.Errorf("unmarshal contacts: %w", err)
+	}
+	if bankJSON != nil {
+		if err := json.Unmarshal(bankJSON, &c.Bank); err != nil {
+			return Counterparty{}, fmt.Errorf("unmarshal bank: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// marshalBank возвращает nil для отсутствующих реквизитов, чтобы в БД был NULL.
+func marshalBank(b *BankDetails) ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return json.Marshal(b)
+}
+
+func (r *PostgresCounterpartyRepo) Create(c Counterparty) (Counterparty, error) {
+	if c.Contacts == nil {
+		c.Contacts = []Contact{}
+	}
+
+	contacts, err := json.Marshal(c.Contacts)
+	if err != nil {
+		return Counterparty{}, fmt.Errorf("marshal contacts: %w", err)
+	}
+	bank, err := marshalBank(c.Bank)
+	if err != nil {
+		return Counterparty{}, fmt.Errorf("marshal bank: %w", err)
+	}
+
+	var id string
+	err = r.db.QueryRow(context.Background(),
+		`INSERT INTO counterparties
+		   (legal_name, short_name, inn, kpp, ogrn, legal_address, postal_address, contacts, bank)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id`,
+		c.LegalName, c.ShortName, c.INN, c.KPP, c.OGRN, c.LegalAddress, c.PostalAddress, contacts, bank,
+	).Scan(&id)
+	if err != nil {
+		return Counterparty{}, err
+	}
+
+	return r.FindByID(id)
+}
+
+func (r *PostgresCounterpartyRepo) Search(query string, limit int) ([]Counterparty, error) {
+	sql := "SELECT" + counterpartyColumns + "\nFROM counterparties c"
+	args := []interface{}{}
+
+	if query != "" {
+		args = append(args, query)
+		sql += `
+WHERE c.inn LIKE $1 || '%'
+   OR c.legal_name ILIKE '%' || $1 || '%'
+   OR c.short_name ILIKE '%' || $1 || '%'`
+	}
+
+	args = append(args, limit)
+	sql += fmt.Sprintf("\nORDER BY c.legal_name, c.id\nLIMIT $%d", len(args))
+
+	rows, err := r.db.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Counterparty{}
+	for rows.Next() {
+		c, err := scanCounterparty(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+
+	return list, rows.Err()
+}
+
+func (r *PostgresCounterpartyRepo) FindByID(id string) (Counterparty, error) {
+	c, err := scanCounterparty(r.db.QueryRow(context.Background(),
+		"SELECT"+counterpartyColumns+"\nFROM counterparties c\nWHERE c.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Counterparty{}, ErrNotFound
+		}
+		return Counterparty{}, err
+	}
+
+	return c, nil
+}
+
+func (r *PostgresCounterpartyRepo) Update(id string, c UpdateCounterpartyInput) (Counterparty, error) {
+	query := "UPDATE counterparties SET "
+	args := []interface{}{}
+	i := 1
+
+	set := func(column string, value interface{}) {
+		query += fmt.Sprintf("%s = $%d, ", column, i)
+		args = append(args, value)
+		i++
+	}
+
+	if c.LegalName != nil {
+		set("legal_name", *c.LegalName)
+	}
+	if c.ShortName != nil {
+		set("short_name", *c.ShortName)
+	}
+	if c.INN != nil {
+		set("inn", *c.INN)
+	}
+	if c.KPP != nil {
+		set("kpp", *c.KPP)
+	}
+	if c.OGRN != nil {
+		set("ogrn", *c.OGRN)
+	}
+	if c.LegalAddress != nil {
+		set("legal_address", *c.LegalAddress)
+	}
+	if c.PostalAddress != nil {
+		set("postal_address", *c.PostalAddress)
+	}
+	if c.Contacts != nil {
+		contacts, err := json.Marshal(*c.Contacts)
+		if err != nil {
+			return Counterparty{}, fmt.Errorf("marshal contacts: %w", err)
+		}
+		set("contacts", contacts)
+	}
+	if c.Bank != nil {
+		bank, err := marshalBank(c.Bank)
+		if err != nil {
+			return Counterparty{}, fmt.Errorf("marshal bank: %w", err)
+		}
+		set("bank", bank)
+	}
+
+	if len(args) == 0 {
+		return r.FindByID(id)
+	}
+
+	// убрать последнюю запятую
+	query = strings.TrimSuffix(query, ", ")
+	query += fmt.Sprintf(" WHERE id = $%d", i)
+	args = append(args, id)
+
+	tag, err := r.db.Exec(context.Background(), query, args...)
+	if err != nil {
+		return Counterparty{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return Counterparty{}, ErrNotFound
+	}
+
+	return r.FindByID(id)
+}
+
+func (r *PostgresCounterpartyRepo) Delete(id string) error {
+	tag, err := r.db.Exec(context.Background(), `DELETE FROM counterparties WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// InUse сообщает, указан ли контрагент хотя бы в одном грузе, включая грузы в корзине.
+func (r *PostgresCounterpartyRepo) InUse(id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(context.Background(),
+		`SELECT EXISTS (
+		   SELECT 1 FROM cargos
+		    WHERE customer_id = $1 OR shipper_id = $1 OR consignee_id = $1
+		 )`, id).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,101 @@
+package counterparty
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound  = errors.New("контрагент не найден")
+	ErrInUse     = errors.New("контрагент указан в грузах и не может быть удалён")
+	ErrDuplicate = errors.New("контрагент с таким ИНН и КПП уже существует")
+)
+
+// Contact — контактное лицо контрагента.
+type Contact struct {
+	Name     string  `json:"name" validate:"required" example:"Петров Пётр"`
+	Position *string `json:"position,omitempty" example:"Логист"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,ru_phone" example:"+79991234567"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email" example:"logist@example.ru"`
+}
+
+// BankDetails — банковские реквизиты контрагента.
+type BankDetails struct {
+	BankName             string  `json:"bankName" validate:"required" example:"ПАО Сбербанк"`
+	BIK                  string  `json:"bik" validate:"required,bik" example:"044525225"`
+	CorrespondentAccount *string `json:"correspondentAccount,omitempty" validate:"omitempty,len=20,numeric" example:"30101810400000000225"`
+	Account              string  `json:"account" validate:"required,len=20,numeric" example:"40702810938000000001"`
+}
+
+type Counterparty struct {
+	ID        string  `json:"id"`
+	LegalName string  `json:"legalName" validate:"required" example:"ООО «Ромашка»"`
+	ShortName *string `json:"shortName,omitempty" example:"Ромашка"`
+
+	INN  string  `json:"inn" validate:"required,inn" example:"7707083893"`
+	KPP  *string `json:"kpp,omitempty" validate:"omitempty,kpp" example:"773601001"`
+	OGRN *string `json:"ogrn,omitempty" validate:"omitempty,ogrn" example:"1027700132195"`
+
+	LegalAddress  *string `json:"legalAddress,omitempty" example:"г. Москва, ул. Вавилова, д. 19"`
+	PostalAddress *string `json:"postalAddress,omitempty" example:"г. Москва, ул. Вавилова, д. 19"`
+
+	Contacts []Contact    `json:"contacts" validate:"dive"`
+	Bank     *BankDetails `json:"bank,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type UpdateCounterpartyInput struct {
+	LegalName     *string      `json:"legalName,omitempty" validate:"omitempty,min=1" example:"ООО «Ромашка»"`
+	ShortName     *string      `json:"shortName,omitempty" example:"Ромашка"`
+	INN           *string      `json:"inn,omitempty" validate:"omitempty,inn" example:"7707083893"`
+	KPP           *string      `json:"kpp,omitempty" validate:"omitempty,kpp" example:"773601001"`
+	OGRN          *string      `json:"ogrn,omitempty" validate:"omitempty,ogrn" example:"1027700132195"`
+	LegalAddress  *string      `json:"legalAddress,omitempty"`
+	PostalAddress *string      `json:"postalAddress,omitempty"`
+	Contacts      *[]Contact   `json:"contacts,omitempty" validate:"omitempty,dive"`
+	Bank          *BankDetails `json:"bank,omitempty"`
+}
+
+type CounterpartyRepository interface {
+	Create(c Counterparty) (Counterparty, error)
+	// Search ищет по началу ИНН или по подстроке названия; пустой запрос
+	// возвращает всех контрагентов.
+	Search(query string, limit int) ([]Counterparty, error)
+	FindByID(id string) (Counterparty, error)
+	Update(id string, input UpdateCounterpartyInput) (Counterparty, error)
+	Delete(id string) error
+	InUse(id string) (bool, error)
+}
+
+type CreateRequest struct {
+	LegalName     string       `json:"legalName" example:"ООО «Ромашка»"`
+	ShortName     *string      `json:"shortName,omitempty" example:"Ромашка"`
+	INN           string       `json:"inn" example:"7707083893"`
+	KPP           *string      `json:"kpp,omitempty" example:"773601001"`
+	OGRN          *string      `json:"ogrn,omitempty" example:"1027700132195"`
+	LegalAddress  *string      `json:"legalAddress,omitempty" example:"г. Москва, ул. Вавилова, д. 19"`
+	PostalAddress *string      `json:"postalAddress,omitempty" example:"г. Москва, ул. Вавилова, д. 19"`
+	Contacts      []Contact    `json:"contacts,omitempty"`
+	Bank          *BankDetails `json:"bank,omitempty"`
+}
+
+type CreateResponse struct {
+	Message string       `json:"message" example:"Контрагент успешно создан"`
+	Data    Counterparty `json:"data"`
+}
+
+type ListResponse struct {
+	Message string         `json:"message" example:"Список контрагентов"`
+	Data    []Counterparty `json:"data"`
+}
+
+type GetResponse struct {
+	Message string       `json:"message" example:"Контрагент"`
+	Data    Counterparty `json:"data"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"контрагент не найден"`
+	Data    interface{} `json:"data"`
+}
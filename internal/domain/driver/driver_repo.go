@@ -0,0 +1,178 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresDriverRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresDriverRepo(db *pgxpool.Pool) DriverRepository {
+	return &PostgresDriverRepo{db: db}
+}
+
+const driverColumns = `
+    d.id,
+    d.full_name,
+    d.phone,
+    d.license_number,
+    d.license_categories,
+    d.license_expiry,
+    d.status,
+    d.created_at`
+
+func scanDriver(row pgx.Row) (Driver, error) {
+	var d Driver
+	err := row.Scan(
+		&d.ID,
+		&d.FullName,
+		&d.Phone,
+		&d.LicenseNumber,
+		&d.LicenseCategories,
+		&d.LicenseExpiry,
+		&d.Status,
+		&d.CreatedAt,
+	)
+	return d, err
+}
+
+func (r *PostgresDriverRepo) Create(d Driver) (Driver, error) {
+	if d.LicenseCategories == nil {
+		d.LicenseCategories = []string{}
+	}
+
+	var id string
+	err := r.db.QueryRow(context.Background(),
+		`INSERT INTO drivers (full_name, phone, license_number, license_categories, license_expiry, status)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id`,
+		d.FullName, d.Phone, d.LicenseNumber, d.LicenseCategories, d.LicenseExpiry, d.Status,
+	).Scan(&id)
+	if err != nil {
+		return Driver{}, err
+	}
+
+	return r.FindByID(id)
+}
+
+func (r *PostgresDriverRepo) FindAll(f ListFilter) ([]Driver, error) {
+	query := "SELECT" + driverColumns + "\nFROM drivers d\nWHERE TRUE"
+	args := []interface{}{}
+
+	if f.Query != nil {
+		args = append(args, "%"+*f.Query+"%")
+		query += fmt.Sprintf(" AND (d.full_name ILIKE $%[1]d OR d.phone ILIKE $%[1]d OR d.license_number ILIKE $%[1]d)", len(args))
+	}
+	if f.Status != nil {
+		args = append(args, *f.Status)
+		query += fmt.Sprintf(" AND d.status = $%d", len(args))
+	}
+	query += "\nORDER BY d.full_name, d.id"
+
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drivers := []Driver{}
+	for rows.Next() {
+		d, err := scanDriver(rows)
+		if err != nil {
+			return nil, err
+		}
+		drivers = append(drivers, d)
+	}
+
+	return drivers, rows.Err()
+}
+
+func (r *PostgresDriverRepo) FindByID(id string) (Driver, error) {
+	d, err := scanDriver(r.db.QueryRow(context.Background(),
+		"SELECT"+driverColumns+"\nFROM drivers d\nWHERE d.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Driver{}, ErrNotFound
+		}
+		return Driver{}, err
+	}
+
+	return d, nil
+}
+
+func (r *PostgresDriverRepo) Update(id string, d UpdateDriverInput) (Driver, error) {
+	query := "UPDATE drivers SET "
+	args := []interface{}{}
+	i := 1
+
+	set := func(column string, value interface{}) {
+		query += fmt.Sprintf("%s = $%d, ", column, i)
+		args = append(args, value)
+		i++
+	}
+
+	if d.FullName != nil {
+		set("full_name", *d.FullName)
+	}
+	if d.Phone != nil {
+		set("phone", *d.Phone)
+	}
+	if d.LicenseNumber != nil {
+		set("license_number", *d.LicenseNumber)
+	}
+	if d.LicenseCategories != nil {
+		set("license_categories", *d.LicenseCategories)
+	}
+	if d.LicenseExpiry != nil {
+		set("license_expiry", *d.LicenseExpiry)
+	}
+	if d.Status != nil {
+		set("status", *d.Status)
+	}
+
+	if len(args) == 0 {
+		return r.FindByID(id)
+	}
+
+	// убрать последнюю запятую
+	query = strings.TrimSuffix(query, ", ")
+	query += fmt.Sprintf(" WHERE id = $%d", i)
+	args = append(args, id)
+
+	tag, err := r.db.Exec(context.Background(), query, args...)
+	if err != nil {
+		return Driver{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return Driver{}, ErrNotFound
+	}
+
+	return r.FindByID(id)
+}
+
+func (r *PostgresDriverRepo) Delete(id string) error {
+	tag, err := r.db.Exec(context.Background(), `DELETE FROM drivers WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// HasCargos сообщает, ссылается ли на водителя хотя бы один груз,
+// включая грузы в корзине.
+func (r *PostgresDriverRepo) HasCargos(id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM cargos WHERE driver_id = $1)`, id).Scan(&exists)
+	return exists, err
+}
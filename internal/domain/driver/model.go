@@ -0,0 +1,91 @@
+package driver
+
+import (
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusOnLeave   Status = "on_leave"
+	StatusDismissed Status = "dismissed"
+)
+
+var (
+	ErrNotFound      = errors.New("водитель не найден")
+	ErrDismissed     = errors.New("водитель уволен, назначьте другого водителя")
+	ErrHasCargos     = errors.New("за водителем закреплены грузы, переведите водителя в статус dismissed вместо удаления")
+	ErrInvalidStatus = errors.New("неизвестный статус водителя, допустимы active, on_leave и dismissed")
+)
+
+func (s Status) Valid() bool {
+	return s == StatusActive || s == StatusOnLeave || s == StatusDismissed
+}
+
+type Driver struct {
+	ID       string  `json:"id"`
+	FullName string  `json:"fullName" validate:"required" example:"Иванов Иван Иванович"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,ru_phone" example:"+79991234567"`
+
+	LicenseNumber     *string    `json:"licenseNumber,omitempty" validate:"omitempty,ru_license" example:"9901123456"`
+	LicenseCategories []string   `json:"licenseCategories" validate:"dive,oneof=A A1 B B1 BE C C1 CE C1E D D1 DE D1E M Tm Tb" example:"C,CE"`
+	LicenseExpiry     *time.Time `json:"licenseExpiry,omitempty" example:"2030-01-01T00:00:00Z"`
+
+	Status    Status    `json:"status" validate:"required,oneof=active on_leave dismissed" example:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type UpdateDriverInput struct {
+	FullName          *string    `json:"fullName,omitempty" validate:"omitempty,min=1" example:"Иванов Иван Иванович"`
+	Phone             *string    `json:"phone,omitempty" validate:"omitempty,ru_phone" example:"+79991234567"`
+	LicenseNumber     *string    `json:"licenseNumber,omitempty" validate:"omitempty,ru_license" example:"9901123456"`
+	LicenseCategories *[]string  `json:"licenseCategories,omitempty" validate:"omitempty,dive,oneof=A A1 B B1 BE C C1 CE C1E D D1 DE D1E M Tm Tb" example:"C,CE"`
+	LicenseExpiry     *time.Time `json:"licenseExpiry,omitempty" example:"2030-01-01T00:00:00Z"`
+	Status            *Status    `json:"status,omitempty" validate:"omitempty,oneof=active on_leave dismissed" example:"on_leave"`
+}
+
+// ListFilter — фильтры списка водителей.
+type ListFilter struct {
+	Query  *string
+	Status *Status
+}
+
+type DriverRepository interface {
+	Create(d Driver) (Driver, error)
+	FindAll(filter ListFilter) ([]Driver, error)
+	FindByID(id string) (Driver, error)
+	Update(id string, input UpdateDriverInput) (Driver, error)
+	Delete(id string) error
+	HasCargos(id string) (bool, error)
+}
+
+type CreateRequest struct {
+	FullName          string     `json:"fullName" example:"Иванов Иван Иванович"`
+	Phone             *string    `json:"phone,omitempty" example:"+79991234567"`
+	LicenseNumber     *string    `json:"licenseNumber,omitempty" example:"9901123456"`
+	LicenseCategories []string   `json:"licenseCategories,omitempty" example:"C,CE"`
+	LicenseExpiry     *time.Time `json:"licenseExpiry,omitempty" example:"2030-01-01T00:00:00Z"`
+	Status            *Status    `json:"status,omitempty" example:"active"`
+}
+
+type CreateResponse struct {
+	Message string `json:"message" example:"Водитель успешно создан"`
+	Data    Driver `json:"data"`
+}
+
+type ListResponse struct {
+	Message string   `json:"message" example:"Список водителей"`
+	Data    []Driver `json:"data"`
+}
+
+type GetResponse struct {
+	Message string `json:"message" example:"Водитель"`
+	Data    Driver `json:"data"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"водитель не найден"`
+	Data    interface{} `json:"data"`
+}
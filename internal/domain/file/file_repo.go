@@ -2,7 +2,9 @@ package file
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,15 +14,15 @@ func NewRepo(db *pgxpool.Pool) Repository { return &pgRepo{db} }
 
 func (r *pgRepo) Create(ctx context.Context, rec Record) error {
 	_, err := r.db.Exec(ctx,
-		`INSERT INTO files(id, owner_id, owner_table, url)
-		 VALUES ($1,$2,$3,$4)`,
-		rec.ID, rec.OwnerID, rec.OwnerTable, rec.URL)
+		`INSERT INTO files(id, owner_id, owner_table, url, kind)
+		 VALUES ($1,$2,$3,$4,$5)`,
+		rec.ID, rec.OwnerID, rec.OwnerTable, rec.URL, rec.Kind)
 	return err
 }
 
 func (r *pgRepo) DeleteByIDs(ctx context.Context, ids []string) ([]Record, error) {
 	rows, err := r.db.Query(ctx,
-		`DELETE FROM files WHERE id = ANY($1) RETURNING id, owner_id, owner_table, url`,
+		`DELETE FROM files WHERE id = ANY($1) RETURNING id, owner_id, owner_table, url, kind`,
 		ids)
 	if err != nil {
 		return nil, err
@@ -30,7 +32,7 @@ func (r *pgRepo) DeleteByIDs(ctx context.Context, ids []string) ([]Record, error
 	var res []Record
 	for rows.Next() {
 		var rec Record
-		if err := rows.Scan(&rec.ID, &rec.OwnerID, &rec.OwnerTable, &rec.URL); err != nil {
+		if err := rows.Scan(&rec.ID, &rec.OwnerID, &rec.OwnerTable, &rec.URL, &rec.Kind); err != nil {
 			return nil, err
 		}
 		res = append(res, rec)
@@ -40,7 +42,7 @@ func (r *pgRepo) DeleteByIDs(ctx context.Context, ids []string) ([]Record, error
 
 func (r *pgRepo) GetByOwner(ctx context.Context, table, ownerID string) ([]Record, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT id, url FROM files WHERE owner_table=$1 AND owner_id=$2`,
+		`SELECT id, owner_id, owner_table, url, kind FROM files WHERE owner_table=$1 AND owner_id=$2`,
 		table, ownerID)
 	if err != nil {
 		return nil, err
@@ -50,10 +52,34 @@ func (r *pgRepo) GetByOwner(ctx context.Context, table, ownerID string) ([]Recor
 	var list []Record
 	for rows.Next() {
 		var rec Record
-		if err := rows.Scan(&rec.ID, &rec.URL); err != nil {
+		if err := rows.Scan(&rec.ID, &rec.OwnerID, &rec.OwnerTable, &rec.URL, &rec.Kind); err != nil {
 			return nil, err
 		}
 		list = append(list, rec)
 	}
 	return list, nil
 }
+
+func (r *pgRepo) FindVisible(ctx context.Context, url, userID string) (Record, error) {
+	var rec Record
+	err := r.db.QueryRow(ctx,
+		`SELECT f.id, f.owner_id, f.owner_table, f.url, f.kind
+		   FROM files f
+		  WHERE f.url = $1
+		    AND ($2 = ''
+		         OR (f.owner_table = 'cargos' AND EXISTS (
+		               SELECT 1
+		                 FROM cargos c
+		                 JOIN user_trucks ut ON ut.truck_id = c.truckid
+		                WHERE c.id = f.owner_id AND c.deleted_at IS NULL AND ut.user_id::text = $2))
+		         OR (f.owner_table = 'trucks' AND EXISTS (
+		               SELECT 1
+		                 FROM trucks t
+		                 JOIN user_trucks ut ON ut.truck_id = t.id
+		                WHERE t.id = f.owner_id AND t.deleted_at IS NULL AND ut.user_id::text = $2)))`,
+		url, userID).Scan(&rec.ID, &rec.OwnerID, &rec.OwnerTable, &rec.URL, &rec.Kind)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Record{}, ErrNotFound
+	}
+	return rec, err
+}
@@ -2,24 +2,40 @@ package file
 
 import (
 	"context"
+	"errors"
 	"io"
 )
 
+var ErrNotFound = errors.New("файл не найден")
+
 type Meta struct {
 	URL string
 }
 
+const (
+	KindPhoto    = "photo"
+	KindDocument = "document"
+)
+
 type Record struct {
-	ID, OwnerID, OwnerTable, URL string
+	ID, OwnerID, OwnerTable, URL, Kind string
 }
 
 type Repository interface {
 	Create(ctx context.Context, rec Record) error
 	DeleteByIDs(ctx context.Context, ids []string) ([]Record, error)
 	GetByOwner(ctx context.Context, ownerTable, ownerID string) ([]Record, error)
+	// FindVisible ищет файл по URL. Если userID не пуст, находятся только
+	// вложения неудалённых грузов и машин, закреплённых за этим пользователем.
+	FindVisible(ctx context.Context, url, userID string) (Record, error)
 }
 
 type Storage interface {
 	Save(ctx context.Context, name string, r io.Reader) (Meta, error)
 	Delete(ctx context.Context, url string) error
 }
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"файл не найден"`
+	Data    interface{} `json:"data"`
+}
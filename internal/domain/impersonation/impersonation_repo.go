@@ -0,0 +1,113 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepo(db *pgxpool.Pool) Repository {
+	return &PostgresRepo{db: db}
+}
+
+const impersonationColumns = `
+    i.id,
+    i.actor_id,
+    i.user_id,
+    i.reason,
+    i.ip,
+    i.user_agent,
+    i.created_at,
+    i.expires_at`
+
+func scanImpersonation(row pgx.Row) (Impersonation, error) {
+	var imp Impersonation
+	err := row.Scan(
+		&imp.ID,
+		&imp.ActorID,
+		&imp.UserID,
+		&imp.Reason,
+		&imp.IP,
+		&imp.UserAgent,
+		&imp.CreatedAt,
+		&imp.ExpiresAt,
+	)
+	return imp, err
+}
+
+func (r *PostgresRepo) Create(imp Impersonation) (Impersonation, error) {
+	return scanImpersonation(r.db.QueryRow(context.Background(),
+		`INSERT INTO impersonations AS i (actor_id, user_id, reason, ip, user_agent, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING`+impersonationColumns,
+		imp.ActorID, imp.UserID, imp.Reason, imp.IP, imp.UserAgent, imp.ExpiresAt))
+}
+
+func (r *PostgresRepo) FindAll(userID string) ([]Impersonation, error) {
+	rows, err := r.db.Query(context.Background(),
+		"SELECT"+impersonationColumns+`
+		   FROM impersonations i
+		  WHERE $1 = '' OR i.user_id::text = $1
+		  ORDER BY i.created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Impersonation{}
+	for rows.Next() {
+		imp, err := scanImpersonation(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, imp)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresRepo) FindByID(id string) (Impersonation, error) {
+	imp, err := scanImpersonation(r.db.QueryRow(context.Background(),
+		"SELECT"+impersonationColumns+`
+		   FROM impersonations i
+		  WHERE i.id::text = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Impersonation{}, ErrNotFound
+	}
+	if err != nil {
+		return Impersonation{}, err
+	}
+
+	rows, err := r.db.Query(context.Background(),
+		`SELECT method, path, status, created_at
+		   FROM impersonation_requests
+		  WHERE impersonation_id = $1
+		  ORDER BY id`, imp.ID)
+	if err != nil {
+		return Impersonation{}, err
+	}
+	defer rows.Close()
+
+	imp.Requests = []Request{}
+	for rows.Next() {
+		var req Request
+		if err := rows.Scan(&req.Method, &req.Path, &req.Status, &req.CreatedAt); err != nil {
+			return Impersonation{}, err
+		}
+		imp.Requests = append(imp.Requests, req)
+	}
+	return imp, rows.Err()
+}
+
+func (r *PostgresRepo) AddRequest(impersonationID string, req Request) error {
+	_, err := r.db.Exec(context.Background(),
+		`INSERT INTO impersonation_requests (impersonation_id, method, path, status)
+		 VALUES ($1, $2, $3, $4)`,
+		impersonationID, req.Method, req.Path, req.Status)
+	return err
+}
@@ -0,0 +1,80 @@
+package impersonation
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("вход от имени пользователя не найден")
+	ErrUser       = errors.New("пользователь не найден")
+	ErrSelf       = errors.New("нельзя войти от имени самого себя")
+	ErrSuperAdmin = errors.New("нельзя войти от имени суперадминистратора")
+	ErrNoSession  = errors.New("токен выдан до появления сессий, войдите заново")
+	// ErrDenied — действие, недоступное при входе от имени пользователя.
+	ErrDenied = errors.New("Недостаточно прав: действие недоступно при входе от имени пользователя")
+)
+
+// Impersonation — вход суперадминистратора (ActorID) от имени пользователя
+// (UserID). Выданный при этом access-токен действует до ExpiresAt и не
+// продлевается.
+type Impersonation struct {
+	ID        string    `json:"id"`
+	ActorID   string    `json:"actorId"`
+	UserID    string    `json:"userId"`
+	Reason    string    `json:"reason" example:"Заявка 1432: не видит груз"`
+	IP        string    `json:"ip" example:"10.0.0.5"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Requests заполняется только при чтении одного входа.
+	Requests []Request `json:"requests,omitempty"`
+}
+
+// Request — запрос, сделанный с токеном входа от имени пользователя.
+type Request struct {
+	Method    string    `json:"method" example:"GET"`
+	Path      string    `json:"path" example:"/api/v1/cargo/5d7c..."`
+	Status    int       `json:"status" example:"404"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Repository interface {
+	Create(imp Impersonation) (Impersonation, error)
+	// FindAll возвращает входы, новые первыми; с userID — только от имени
+	// этого пользователя.
+	FindAll(userID string) ([]Impersonation, error)
+	// FindByID возвращает вход вместе с его запросами.
+	FindByID(id string) (Impersonation, error)
+	AddRequest(impersonationID string, req Request) error
+}
+
+type StartRequest struct {
+	Reason string `json:"reason" validate:"max=500" example:"Заявка 1432: не видит груз"`
+}
+
+// Started — выданный токен входа от имени пользователя.
+type Started struct {
+	AccessToken   string        `json:"access_token"`
+	Impersonation Impersonation `json:"impersonation"`
+}
+
+type StartResponse struct {
+	Message string  `json:"message" example:"Выполнен вход от имени пользователя"`
+	Data    Started `json:"data"`
+}
+
+type ListResponse struct {
+	Message string          `json:"message" example:"Входы от имени пользователей"`
+	Data    []Impersonation `json:"data"`
+}
+
+type GetResponse struct {
+	Message string        `json:"message" example:"Вход от имени пользователя"`
+	Data    Impersonation `json:"data"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"нельзя войти от имени суперадминистратора"`
+	Data    interface{} `json:"data"`
+}
@@ -0,0 +1,97 @@
+package invitation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"test-project/internal/domain/user"
+)
+
+// MaxBulk — сколько адресов можно пригласить одним запросом.
+const MaxBulk = 500
+
+var ErrBulkInvalid = errors.New("невалидный список приглашений")
+
+// BulkStatus — итог приглашения одного адреса из списка.
+type BulkStatus string
+
+const (
+	BulkSent           BulkStatus = "sent"
+	BulkAlreadyUser    BulkStatus = "already_user"
+	BulkAlreadyInvited BulkStatus = "already_invited"
+	BulkEmailFailed    BulkStatus = "email_failed"
+	BulkInvalid        BulkStatus = "invalid"
+	BulkFailed         BulkStatus = "failed"
+)
+
+type BulkResult struct {
+	Email  string     `json:"email" example:"firulvv@mail.ru"`
+	Status BulkStatus `json:"status" example:"sent"`
+	// Message — причина для статусов, отличных от sent.
+	Message string `json:"message,omitempty"`
+	// ID — приглашение; есть для sent и email_failed (его можно отправить повторно).
+	ID string `json:"id,omitempty"`
+}
+
+type BulkResponse struct {
+	Message string       `json:"message" example:"Приглашения обработаны: отправлено 2 из 3"`
+	Data    []BulkResult `json:"data"`
+}
+
+// ParseCSV читает список приглашений из CSV с колонками email, role,
+// username. Первая строка считается заголовком, если в ней есть колонка
+// email; тогда порядок колонок любой. Без заголовка колонки идут в
+// порядке email, role, username; role и username необязательны.
+func ParseCSV(r io.Reader) ([]CreateRequest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBulkInvalid, err)
+	}
+
+	columns := map[string]int{"email": 0, "role": 1, "username": 2}
+	if len(records) > 0 && hasEmailHeader(records[0]) {
+		columns = map[string]int{}
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		records = records[1:]
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	list := make([]CreateRequest, 0, len(records))
+	for _, record := range records {
+		email := field(record, "email")
+		if email == "" {
+			continue
+		}
+
+		req := CreateRequest{Email: email, Role: user.Role(strings.ToUpper(field(record, "role")))}
+		if username := field(record, "username"); username != "" {
+			req.Username = &username
+		}
+		list = append(list, req)
+	}
+	return list, nil
+}
+
+func hasEmailHeader(record []string) bool {
+	for _, name := range record {
+		if strings.EqualFold(strings.TrimSpace(name), "email") {
+			return true
+		}
+	}
+	return false
+}
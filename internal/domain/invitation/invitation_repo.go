@@ -2,7 +2,12 @@ package invitation
 
 import (
 	"context"
+	"errors"
+	"test-project/internal/domain/user"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,12 +19,204 @@ func NewPostgresCargoRepo(db *pgxpool.Pool) InvitationRepository {
 	return &PostgresInvitationRepo{db: db}
 }
 
+// invitationStatus вычисляет статус приглашения.
+const invitationStatus = `
+    CASE
+      WHEN i.used                THEN 'used'
+      WHEN i.expires_at <= now() THEN 'expired'
+      ELSE 'pending'
+    END`
+
+// invitationColumns — колонки приглашения без токена.
+const invitationColumns = `
+    i.id,
+    i.email,
+    i.used,
+    i.role,
+    i.username,` + invitationStatus + `,
+    i.expires_at,
+    i.used_at,
+    i."createdAt"`
+
+func scanInvitation(row pgx.Row) (Invitation, error) {
+	var i Invitation
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Used,
+		&i.Role,
+		&i.Username,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Invitation{}, ErrNotFound
+	}
+	return i, err
+}
+
 func (r *PostgresInvitationRepo) Create(i Invitation) (Invitation, error) {
-	err := r.db.QueryRow(context.Background(), "INSERT INTO invitations (email, token) VALUES ($1, $2) RETURNING id", i.Email, i.Token).Scan(&i.ID)
+	created, err := scanInvitation(r.db.QueryRow(context.Background(),
+		`INSERT INTO invitations AS i (email, token, role, username, expires_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (email) DO UPDATE
+		    SET token       = EXCLUDED.token,
+		        role        = EXCLUDED.role,
+		        username    = EXCLUDED.username,
+		        expires_at  = EXCLUDED.expires_at,
+		        used        = FALSE,
+		        used_at     = NULL,
+		        "createdAt" = CURRENT_TIMESTAMP
+		  WHERE i.used OR i.expires_at <= now()
+		 RETURNING`+invitationColumns,
+		i.Email, i.Token, i.Role, i.Username, i.ExpiresAt))
+
+	// конфликт с действующим приглашением: строка не обновлена
+	if errors.Is(err, ErrNotFound) {
+		return Invitation{}, ErrAlreadyInvited
+	}
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	created.Token = i.Token
+	return created, nil
+}
+
+func (r *PostgresInvitationRepo) FindAll(status *Status) ([]Invitation, error) {
+	var filter *string
+	if status != nil {
+		v := string(*status)
+		filter = &v
+	}
 
+	rows, err := r.db.Query(context.Background(),
+		"SELECT"+invitationColumns+`
+		   FROM invitations i
+		  WHERE $1::text IS NULL OR`+invitationStatus+` = $1::text
+		  ORDER BY i."createdAt" DESC`, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Invitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, inv)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresInvitationRepo) FindByID(id string) (Invitation, error) {
+	return scanInvitation(r.db.QueryRow(context.Background(),
+		"SELECT"+invitationColumns+" FROM invitations i WHERE i.id::text = $1", id))
+}
+
+func (r *PostgresInvitationRepo) Renew(id, token string, expiresAt time.Time) (Invitation, error) {
+	inv, err := scanInvitation(r.db.QueryRow(context.Background(),
+		`UPDATE invitations AS i
+		    SET token = $2, expires_at = $3
+		  WHERE i.id::text = $1 AND NOT i.used
+		 RETURNING`+invitationColumns, id, token, expiresAt))
+	if errors.Is(err, ErrNotFound) {
+		return Invitation{}, r.missing(id)
+	}
 	if err != nil {
 		return Invitation{}, err
 	}
 
-	return i, nil
+	inv.Token = token
+	return inv, nil
+}
+
+func (r *PostgresInvitationRepo) Delete(id string) error {
+	tag, err := r.db.Exec(context.Background(),
+		`DELETE FROM invitations WHERE id::text = $1 AND NOT used`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.missing(id)
+	}
+	return nil
+}
+
+// missing уточняет, почему приглашение не изменилось: его нет или оно
+// уже использовано.
+func (r *PostgresInvitationRepo) missing(id string) error {
+	inv, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if inv.Used {
+		return ErrAlreadyUsed
+	}
+	return ErrNotFound
+}
+
+func (r *PostgresInvitationRepo) Accept(token, email, passwordHash string) (user.User, error) {
+	return r.accept(`token = $1 AND email = $2`, []interface{}{token, email}, email, passwordHash, "")
+}
+
+func (r *PostgresInvitationRepo) AcceptByEmail(email, username string) (user.User, error) {
+	return r.accept(`email = $1`, []interface{}{email}, email, "", username)
+}
+
+// accept гасит приглашение, найденное по условию where, и создаёт по нему
+// пользователя. username используется, если в приглашении имя не задано.
+func (r *PostgresInvitationRepo) accept(where string, args []interface{}, email, passwordHash, username string) (user.User, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return user.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// строка блокируется UPDATE: второй запрос с тем же токеном дождётся
+	// коммита и уже не найдёт неиспользованное приглашение
+	var (
+		role    user.Role
+		invited *string
+	)
+	err = tx.QueryRow(ctx,
+		`UPDATE invitations
+		    SET used = TRUE, used_at = now()
+		  WHERE `+where+` AND NOT used AND expires_at > now()
+		 RETURNING role, username`, args...).Scan(&role, &invited)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return user.User{}, ErrInvalid
+	}
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if invited == nil && username != "" {
+		invited = &username
+	}
+	u := user.User{Email: email, Password: passwordHash, Role: role}
+	if invited != nil {
+		u.Username = *invited
+	}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO users (username, email, password, role)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, "createdAt"`,
+		invited, email, passwordHash, role,
+	).Scan(&u.ID, &u.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return user.User{}, ErrUserExists
+		}
+		return user.User{}, err
+	}
+
+	return u, tx.Commit(ctx)
 }
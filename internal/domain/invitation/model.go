@@ -1,23 +1,73 @@
 package invitation
 
-import "time"
+import (
+	"errors"
+	"test-project/internal/domain/user"
+	"time"
+)
+
+var (
+	ErrNotFound       = errors.New("приглашение не найдено")
+	ErrAlreadyInvited = errors.New("Приглашение для этого email уже отправлено")
+	ErrAlreadyUsed    = errors.New("приглашение уже использовано")
+	ErrInvalid        = errors.New("приглашение недействительно: оно уже использовано, отозвано или истекло")
+	ErrUserExists     = errors.New("Пользователь с таким email уже существует")
+	ErrInvalidStatus  = errors.New("статус должен быть pending, used или expired")
+	ErrValidation     = errors.New("Ошибки валидации")
+	ErrEmailFailed    = errors.New("не удалось отправить письмо с приглашением")
+)
+
+// Status — состояние приглашения, вычисляется при выборке.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusUsed    Status = "used"
+	StatusExpired Status = "expired"
+)
 
 type Invitation struct {
 	ID string `json:"id"`
 
 	Email string `json:"email" validate:"required,email"`
-	Token string `json:"token" validate:"required"`
+	Token string `json:"token,omitempty" validate:"required"`
 	Used  bool   `json:"used" default:"false"`
 
-	CreatedAt time.Time `json:"createdAt"`
+	// Role и Username применяются к учётной записи при регистрации.
+	Role     user.Role `json:"role" validate:"omitempty,oneof=USER EDITOR SUPERADMIN"`
+	Username *string   `json:"username,omitempty" validate:"omitempty,min=3"`
+
+	Status    Status     `json:"status"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
 }
 
 type InvitationRepository interface {
+	// Create сохраняет приглашение. Просроченное или использованное
+	// приглашение на тот же email заменяется, действующее — нет
+	// (ErrAlreadyInvited).
 	Create(invitation Invitation) (Invitation, error)
+	FindAll(status *Status) ([]Invitation, error)
+	FindByID(id string) (Invitation, error)
+	// Renew выдаёт неиспользованному приглашению новый токен и срок.
+	Renew(id, token string, expiresAt time.Time) (Invitation, error)
+	// Delete отзывает неиспользованное приглашение.
+	Delete(id string) error
+	// Accept в одной транзакции гасит приглашение и создаёт по нему
+	// пользователя с указанными в приглашении ролью и именем. Приглашение
+	// создаёт не больше одной учётной записи.
+	Accept(token, email, passwordHash string) (user.User, error)
+	// AcceptByEmail принимает действующее приглашение на email при входе
+	// через внешнего провайдера: пользователь создаётся без пароля, username
+	// берётся, если в приглашении имя не задано. Нет приглашения — ErrInvalid.
+	AcceptByEmail(email, username string) (user.User, error)
 }
 
 type CreateRequest struct {
-	Email string `json:"email" validate:"required,email" example:"firulvv@mail.ru"`
+	Email    string    `json:"email" validate:"required,email" example:"firulvv@mail.ru"`
+	Role     user.Role `json:"role" example:"EDITOR"`
+	Username *string   `json:"username" example:"Иван"`
 }
 
 type CreateResponse struct {
@@ -25,6 +75,20 @@ type CreateResponse struct {
 	Data    string `json:"data"`
 }
 
+type ListResponse struct {
+	Message string       `json:"message" example:"Список приглашений"`
+	Data    []Invitation `json:"data"`
+}
+
+type GetResponse struct {
+	Message string     `json:"message" example:"Приглашение отправлено повторно"`
+	Data    Invitation `json:"data"`
+}
+
+type DeleteResponse struct {
+	Message string `json:"message" example:"Приглашение отозвано"`
+}
+
 type ErrorResponse struct {
 	Message string      `json:"message" example:"Невалидный формат JSON"`
 	Data    interface{} `json:"data"`
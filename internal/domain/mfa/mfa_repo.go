@@ -0,0 +1,98 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepo(db *pgxpool.Pool) Repository {
+	return &PostgresRepo{db: db}
+}
+
+func (r *PostgresRepo) Get(userID string) (State, error) {
+	var s State
+	err := r.db.QueryRow(context.Background(),
+		`SELECT mfa_secret, mfa_enabled, mfa_last_step FROM users WHERE id = $1`, userID,
+	).Scan(&s.Secret, &s.Enabled, &s.LastStep)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return State{}, ErrNotEnrolled
+	}
+	return s, err
+}
+
+func (r *PostgresRepo) SetSecret(userID, secret string) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE users SET mfa_secret = $2, mfa_last_step = 0 WHERE id = $1 AND NOT mfa_enabled`,
+		userID, secret)
+	return err
+}
+
+func (r *PostgresRepo) Enable(userID string, recoveryHashes []string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET mfa_enabled = TRUE WHERE id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO mfa_recovery_codes (user_id, code_hash)
+		 SELECT $1, unnest($2::text[])`, userID, recoveryHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepo) Disable(userID string) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE users SET mfa_enabled = FALSE, mfa_secret = NULL, mfa_last_step = 0 WHERE id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepo) UseStep(userID string, step int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE users SET mfa_last_step = $2 WHERE id = $1 AND mfa_last_step < $2`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PostgresRepo) UseRecoveryCode(userID, hash string) (bool, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE mfa_recovery_codes SET used_at = now()
+		  WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
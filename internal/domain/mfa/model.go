@@ -0,0 +1,90 @@
+package mfa
+
+import "errors"
+
+// RecoveryCodeCount — сколько резервных кодов выдаётся при подключении 2FA.
+const RecoveryCodeCount = 10
+
+var (
+	ErrNotEnrolled     = errors.New("двухфакторная аутентификация не подключена")
+	ErrAlreadyEnabled  = errors.New("двухфакторная аутентификация уже включена")
+	ErrNotStarted      = errors.New("сначала начните подключение двухфакторной аутентификации")
+	ErrInvalidCode     = errors.New("неверный код подтверждения")
+	ErrRequired        = errors.New("для вашей роли двухфакторная аутентификация обязательна, отключить её нельзя")
+	ErrChallengeFailed = errors.New("сессия входа истекла, войдите заново")
+)
+
+// State — состояние 2FA пользователя. Secret задан с начала подключения,
+// Enabled — после подтверждения первым кодом.
+type State struct {
+	Secret   *string
+	Enabled  bool
+	LastStep int64
+}
+
+type Repository interface {
+	Get(userID string) (State, error)
+	// SetSecret начинает подключение: сохраняет новый, ещё не подтверждённый секрет.
+	SetSecret(userID, secret string) error
+	// Enable включает 2FA и заменяет резервные коды (храним только хэши).
+	Enable(userID string, recoveryHashes []string) error
+	// Disable выключает 2FA, удаляет секрет и резервные коды.
+	Disable(userID string) error
+	// UseStep фиксирует использованный интервал TOTP; false — код этого
+	// или более позднего интервала уже применялся.
+	UseStep(userID string, step int64) (bool, error)
+	// UseRecoveryCode гасит резервный код; false — кода нет или он уже использован.
+	UseRecoveryCode(userID, hash string) (bool, error)
+}
+
+type EnrollResponse struct {
+	Message string     `json:"message" example:"Отсканируйте QR-код в приложении-аутентификаторе и подтвердите кодом"`
+	Data    Enrollment `json:"data"`
+}
+
+// Enrollment — данные для добавления учётной записи в приложение-аутентификатор.
+type Enrollment struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"otpauthUri" example:"otpauth://totp/Myakos:firulvv@mail.ru?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Myakos"`
+}
+
+type CodeRequest struct {
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+type DisableRequest struct {
+	Password string `json:"password" validate:"required" example:"123456"`
+	// Code — код из приложения или резервный код.
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+type ChallengeRequest struct {
+	MFAToken string `json:"mfaToken" validate:"required"`
+	// Code — код из приложения или резервный код. Для начала обязательного
+	// подключения (/auth/login/mfa/setup) не нужен.
+	Code string `json:"code" example:"123456"`
+}
+
+// ChallengeResponse — ответ на вход по паролю, когда нужен второй фактор.
+// Step: "verify" — ввести код (/auth/login/mfa), "setup" — сначала
+// подключить 2FA (/auth/login/mfa/setup, затем /auth/login/mfa/confirm).
+type ChallengeResponse struct {
+	Message string            `json:"message" example:"Введите код двухфакторной аутентификации"`
+	Data    map[string]string `json:"data"`
+}
+
+// LoginConfirmResponse — вход с подключением 2FA: access-токен и резервные коды.
+type LoginConfirmResponse struct {
+	Message string            `json:"message" example:"Двухфакторная аутентификация включена. Сохраните резервные коды"`
+	Data    LoginConfirmation `json:"data"`
+}
+
+type LoginConfirmation struct {
+	AccessToken   string   `json:"access_token"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RecoveryCodesResponse struct {
+	Message string   `json:"message" example:"Двухфакторная аутентификация включена. Сохраните резервные коды"`
+	Data    []string `json:"data"`
+}
@@ -0,0 +1,92 @@
+package permission
+
+import (
+	"errors"
+	"test-project/internal/domain/user"
+)
+
+// Permission — право на действие, например cargo:create. Роли получают
+// права через таблицу role_permissions, которую может менять администратор.
+type Permission string
+
+const (
+	CargoView       Permission = "cargo:view"
+	CargoCreate     Permission = "cargo:create"
+	CargoUpdate     Permission = "cargo:update"
+	CargoDelete     Permission = "cargo:delete"
+	CargoTransition Permission = "cargo:transition"
+	// CargoViewAll — видеть грузы всех машин; без него пользователь видит
+	// только грузы закреплённых за ним машин.
+	CargoViewAll Permission = "cargo:view_all"
+	// FinanceView — видеть выплаты по грузам (сумма, дата, статус, условия).
+	FinanceView Permission = "finance:view"
+
+	TruckView          Permission = "truck:view"
+	TruckManage        Permission = "truck:manage"
+	DriverView         Permission = "driver:view"
+	DriverManage       Permission = "driver:manage"
+	CounterpartyView   Permission = "counterparty:view"
+	CounterpartyManage Permission = "counterparty:manage"
+	ScheduleView       Permission = "schedule:view"
+
+	TrashView    Permission = "trash:view"
+	TrashRestore Permission = "trash:restore"
+	TrashPurge   Permission = "trash:purge"
+
+	UserView         Permission = "user:view"
+	UserManage       Permission = "user:manage"
+	InvitationManage Permission = "invitation:manage"
+	PermissionManage Permission = "permission:manage"
+	APIKeyManage     Permission = "apikey:manage"
+	// UserImpersonate — входить от имени пользователя и смотреть журнал
+	// таких входов.
+	UserImpersonate Permission = "user:impersonate"
+)
+
+// ImpersonationDenied — разрешения, которых нет при входе от имени
+// пользователя, даже если они выданы его роли: через чужую учётную запись
+// нельзя раздавать права, управлять пользователями и ключами.
+var ImpersonationDenied = []Permission{
+	UserManage, InvitationManage, PermissionManage, APIKeyManage, UserImpersonate,
+}
+
+var (
+	ErrUnknown  = errors.New("неизвестное разрешение")
+	ErrRole     = errors.New("неизвестная роль")
+	ErrLockout  = errors.New("у суперадминистратора нельзя отнять право управлять разрешениями")
+	ErrNotFound = errors.New("разрешение не найдено")
+)
+
+// Definition — разрешение из реестра с описанием и ролями, которым оно выдано.
+type Definition struct {
+	Name        Permission  `json:"name" example:"cargo:create"`
+	Description string      `json:"description" example:"Создание грузов"`
+	Roles       []user.Role `json:"roles"`
+}
+
+type Repository interface {
+	// All возвращает реестр разрешений с ролями.
+	All() ([]Definition, error)
+	ForRole(role user.Role) ([]Permission, error)
+	// SetRole заменяет набор разрешений роли. Неизвестные имена — ErrUnknown.
+	SetRole(role user.Role, perms []Permission) error
+}
+
+type SetRoleRequest struct {
+	Permissions []Permission `json:"permissions" validate:"required"`
+}
+
+type ListResponse struct {
+	Message string       `json:"message" example:"Разрешения и роли"`
+	Data    []Definition `json:"data"`
+}
+
+type RoleResponse struct {
+	Message string       `json:"message" example:"Разрешения роли обновлены"`
+	Data    []Permission `json:"data"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"Неизвестное разрешение"`
+	Data    interface{} `json:"data"`
+}
@@ -0,0 +1,97 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test-project/internal/domain/user"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepo(db *pgxpool.Pool) Repository {
+	return &PostgresRepo{db: db}
+}
+
+func (r *PostgresRepo) All() ([]Definition, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT p.name,
+		        p.description,
+		        COALESCE(array_agg(rp.role::text ORDER BY rp.role) FILTER (WHERE rp.role IS NOT NULL), '{}')
+		   FROM permissions p
+		   LEFT JOIN role_permissions rp ON rp.permission = p.name
+		  GROUP BY p.name, p.description
+		  ORDER BY p.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Definition{}
+	for rows.Next() {
+		var (
+			d     Definition
+			roles []string
+		)
+		if err := rows.Scan(&d.Name, &d.Description, &roles); err != nil {
+			return nil, err
+		}
+		d.Roles = make([]user.Role, len(roles))
+		for i, role := range roles {
+			d.Roles[i] = user.Role(role)
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresRepo) ForRole(role user.Role) ([]Permission, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	perms := []Permission{}
+	for rows.Next() {
+		var p Permission
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		perms = append(perms, p)
+	}
+	return perms, rows.Err()
+}
+
+func (r *PostgresRepo) SetRole(role user.Role, perms []Permission) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM role_permissions WHERE role = $1`, role); err != nil {
+		return err
+	}
+	for _, p := range perms {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`, role, p)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return fmt.Errorf("%w: %s", ErrUnknown, p)
+			}
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("сессия не найдена")
+
+// Session — один вход пользователя в систему. ID сессии совпадает с
+// семейством refresh-токенов, выданных при этом входе.
+type Session struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"userId"`
+	UserAgent       string     `json:"userAgent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64)"`
+	IP              string     `json:"ip" example:"203.0.113.7"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	LastRefreshedAt *time.Time `json:"lastRefreshedAt"`
+	ExpiresAt       time.Time  `json:"expiresAt"`
+	// Current — сессия, из которой сделан запрос.
+	Current bool `json:"current" example:"true"`
+}
+
+type SessionRepository interface {
+	Create(s Session) (Session, error)
+	// FindActive возвращает неотозванные и неистёкшие сессии пользователя.
+	FindActive(userID string) ([]Session, error)
+	FindActiveByID(userID, id string) (Session, error)
+	// Touch отмечает обновление токенов и продлевает сессию до expiresAt.
+	Touch(id string, expiresAt time.Time) error
+	Revoke(id string) error
+	// RevokeAll отзывает все активные сессии пользователя, кроме exceptID,
+	// и возвращает их ID.
+	RevokeAll(userID, exceptID string) ([]string, error)
+}
+
+type ListResponse struct {
+	Message string    `json:"message" example:"Активные сессии"`
+	Data    []Session `json:"data"`
+}
+
+type RevokeAllResponse struct {
+	Message string   `json:"message" example:"Сессии завершены"`
+	Data    []string `json:"data"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"сессия не найдена"`
+	Data    interface{} `json:"data"`
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresSessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresSessionRepo(db *pgxpool.Pool) SessionRepository {
+	return &PostgresSessionRepo{db: db}
+}
+
+const sessionColumns = `
+    s.id,
+    s.user_id,
+    s.user_agent,
+    s.ip,
+    s.created_at,
+    s.last_refreshed_at,
+    s.expires_at`
+
+func scanSession(row pgx.Row) (Session, error) {
+	var s Session
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.UserAgent,
+		&s.IP,
+		&s.CreatedAt,
+		&s.LastRefreshedAt,
+		&s.ExpiresAt,
+	)
+	return s, err
+}
+
+func (r *PostgresSessionRepo) Create(s Session) (Session, error) {
+	return scanSession(r.db.QueryRow(context.Background(),
+		`INSERT INTO sessions AS s (user_id, user_agent, ip, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING`+sessionColumns,
+		s.UserID, s.UserAgent, s.IP, s.ExpiresAt))
+}
+
+func (r *PostgresSessionRepo) FindActive(userID string) ([]Session, error) {
+	rows, err := r.db.Query(context.Background(),
+		"SELECT"+sessionColumns+`
+		   FROM sessions s
+		  WHERE s.user_id = $1 AND s.revoked_at IS NULL AND s.expires_at > now()
+		  ORDER BY COALESCE(s.last_refreshed_at, s.created_at) DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+func (r *PostgresSessionRepo) FindActiveByID(userID, id string) (Session, error) {
+	s, err := scanSession(r.db.QueryRow(context.Background(),
+		"SELECT"+sessionColumns+`
+		   FROM sessions s
+		  WHERE s.id::text = $2 AND s.user_id = $1 AND s.revoked_at IS NULL AND s.expires_at > now()`,
+		userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Session{}, ErrNotFound
+	}
+	return s, err
+}
+
+func (r *PostgresSessionRepo) Touch(id string, expiresAt time.Time) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE sessions SET last_refreshed_at = now(), expires_at = $2 WHERE id = $1 AND revoked_at IS NULL`,
+		id, expiresAt)
+	return err
+}
+
+func (r *PostgresSessionRepo) Revoke(id string) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE sessions SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+func (r *PostgresSessionRepo) RevokeAll(userID, exceptID string) ([]string, error) {
+	rows, err := r.db.Query(context.Background(),
+		`UPDATE sessions SET revoked_at = now()
+		  WHERE user_id = $1 AND id::text <> $2 AND revoked_at IS NULL AND expires_at > now()
+		  RETURNING id`, userID, exceptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,61 @@
+package trash
+
+import (
+	"errors"
+	"test-project/internal/domain/cargo"
+	"time"
+)
+
+type ItemType string
+
+const (
+	TypeCargo ItemType = "cargo"
+	TypeTruck ItemType = "truck"
+)
+
+var (
+	ErrUnknownType   = errors.New("неизвестный тип объекта корзины, допустимы cargo и truck")
+	ErrNotFound      = errors.New("объект не найден в корзине")
+	ErrTruckDeleted  = errors.New("машина груза находится в корзине, сначала восстановите машину")
+	ErrTruckHasCargo = errors.New("у машины есть грузы, сначала удалите их окончательно или перенесите на другую машину")
+	ErrTaken         = errors.New("номер груза, название, госномер или VIN уже заняты другим объектом, сначала измените его")
+)
+
+func (t ItemType) Valid() bool {
+	return t == TypeCargo || t == TypeTruck
+}
+
+// Table возвращает таблицу, в которой хранятся объекты этого типа
+// (она же owner_table у файлов).
+func (t ItemType) Table() string {
+	if t == TypeTruck {
+		return "trucks"
+	}
+	return "cargos"
+}
+
+type Item struct {
+	Type      ItemType  `json:"type" example:"cargo"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title" example:"1234"`
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy *string   `json:"deletedBy"`
+}
+
+type Repository interface {
+	List() ([]Item, error)
+	// Restore возвращает объект из корзины. Для груза в той же транзакции
+	// пишется ревизия revise.
+	Restore(t ItemType, id string, revise cargo.Revise) error
+	Purge(t ItemType, id string) error
+}
+
+type ListResponse struct {
+	Message string `json:"message" example:"Корзина"`
+	Data    []Item `json:"data"`
+}
+
+type ErrorResponse struct {
+	Message string      `json:"message" example:"объект не найден в корзине"`
+	Data    interface{} `json:"data"`
+}
@@ -0,0 +1,124 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"test-project/internal/domain/cargo"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresTrashRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTrashRepo(db *pgxpool.Pool) Repository {
+	return &PostgresTrashRepo{db: db}
+}
+
+func (r *PostgresTrashRepo) List() ([]Item, error) {
+	const q = `
+SELECT 'cargo' AS type, id, cargonumber AS title, deleted_at, deleted_by
+  FROM cargos
+ WHERE deleted_at IS NOT NULL
+UNION ALL
+SELECT 'truck' AS type, id, name AS title, deleted_at, deleted_by
+  FROM trucks
+ WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;`
+
+	rows, err := r.db.Query(context.Background(), q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Item{}
+	for rows.Next() {
+		var it Item
+		if err := rows.Scan(&it.Type, &it.ID, &it.Title, &it.DeletedAt, &it.DeletedBy); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+func (r *PostgresTrashRepo) Restore(t ItemType, id string, rv cargo.Revise) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if t == TypeCargo {
+		var truckDeleted bool
+		err := tx.QueryRow(ctx,
+			`SELECT t.deleted_at IS NOT NULL
+			   FROM cargos c
+			   JOIN trucks t ON t.id = c.truckid
+			  WHERE c.id = $1 AND c.deleted_at IS NOT NULL`, id,
+		).Scan(&truckDeleted)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if truckDeleted {
+			return ErrTruckDeleted
+		}
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE `+t.Table()+` SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		// пока объект лежал в корзине, его уникальный ключ занял другой
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrTaken
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if t == TypeCargo {
+		if err := cargo.RecordRevision(ctx, tx, id, rv); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Purge окончательно удаляет объект, который уже находится в корзине.
+func (r *PostgresTrashRepo) Purge(t ItemType, id string) error {
+	ctx := context.Background()
+
+	if t == TypeTruck {
+		var hasCargo bool
+		if err := r.db.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM cargos WHERE truckid = $1)`, id,
+		).Scan(&hasCargo); err != nil {
+			return err
+		}
+		if hasCargo {
+			return ErrTruckHasCargo
+		}
+	}
+
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM `+t.Table()+` WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
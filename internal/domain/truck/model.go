@@ -1,25 +1,75 @@
 package truck
 
 import (
+	"errors"
 	"test-project/internal/domain/cargo"
 	"time"
 )
 
+var (
+	ErrNotFound             = errors.New("машина не найдена")
+	ErrTruckHasActiveCargos = errors.New("у машины есть незавершённые грузы, завершите их или перенесите на другую машину")
+	ErrValidation           = errors.New("Ошибки валидации")
+	ErrDuplicate            = errors.New("машина с таким госномером или VIN уже существует")
+)
+
 type Truck struct {
+	ID   string `json:"id"`
+	Name string `json:"name" validate:"required"`
+
+	LicensePlate *string  `json:"licensePlate,omitempty" validate:"omitempty,ru_plate" example:"А123ВС77"`
+	VIN          *string  `json:"vin,omitempty" validate:"omitempty,vin" example:"1M8GDM9AXKP042788"`
+	Make         *string  `json:"make,omitempty" example:"КАМАЗ"`
+	Model        *string  `json:"model,omitempty" example:"54901"`
+	Year         *int     `json:"year,omitempty" validate:"omitempty,gte=1950,lte=2100" example:"2021"`
+	PayloadKg    *float64 `json:"payloadKg,omitempty" validate:"omitempty,gt=0" example:"20000"`
+	VolumeM3     *float64 `json:"volumeM3,omitempty" validate:"omitempty,gt=0" example:"82"`
+	Active       bool     `json:"active" example:"true"`
+
+	Photos    []Attachment `json:"photos"`
+	Documents []Attachment `json:"documents"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Attachment struct {
 	ID        string    `json:"id"`
-	Name      string    `json:"name"`
+	URL       string    `json:"url"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type UpdateTruckInput struct {
+	Name         *string  `json:"name,omitempty" validate:"omitempty,min=1" example:"Машина"`
+	LicensePlate *string  `json:"licensePlate,omitempty" validate:"omitempty,ru_plate" example:"А123ВС77"`
+	VIN          *string  `json:"vin,omitempty" validate:"omitempty,vin" example:"1M8GDM9AXKP042788"`
+	Make         *string  `json:"make,omitempty" example:"КАМАЗ"`
+	Model        *string  `json:"model,omitempty" example:"54901"`
+	Year         *int     `json:"year,omitempty" validate:"omitempty,gte=1950,lte=2100" example:"2021"`
+	PayloadKg    *float64 `json:"payloadKg,omitempty" validate:"omitempty,gt=0" example:"20000"`
+	VolumeM3     *float64 `json:"volumeM3,omitempty" validate:"omitempty,gt=0" example:"82"`
+	Active       *bool    `json:"active,omitempty" example:"false"`
+}
+
 type TruckRepository interface {
 	Create(truck Truck) (Truck, error)
 	FindAll() ([]Truck, error)
 	FindByID(id string) (Truck, error)
-	GetTruckCargos(id string, limit int, page int) ([]cargo.Cargo, error)
+	Update(id string, input UpdateTruckInput) (Truck, error)
+	Delete(id string, deletedBy string) error
+	HasActiveCargos(id string) (bool, error)
+	GetTruckCargos(id string, filter cargo.ListFilter) (cargo.ListResult, error)
 }
 
 type CreateRequest struct {
-	Name string `json:"name" example:"Машина"`
+	Name         string   `json:"name" example:"Машина"`
+	LicensePlate *string  `json:"licensePlate,omitempty" example:"А123ВС77"`
+	VIN          *string  `json:"vin,omitempty" example:"1M8GDM9AXKP042788"`
+	Make         *string  `json:"make,omitempty" example:"КАМАЗ"`
+	Model        *string  `json:"model,omitempty" example:"54901"`
+	Year         *int     `json:"year,omitempty" example:"2021"`
+	PayloadKg    *float64 `json:"payloadKg,omitempty" example:"20000"`
+	VolumeM3     *float64 `json:"volumeM3,omitempty" example:"82"`
+	Active       *bool    `json:"active,omitempty" example:"true"`
 }
 
 type CreateResponse struct {
@@ -33,8 +83,8 @@ type ListResponse struct {
 }
 
 type GetResponse struct {
-	Message string  `json:"message" example:"Машина"`
-	Data    []Truck `json:"data"`
+	Message string `json:"message" example:"Машина"`
+	Data    Truck  `json:"data"`
 }
 
 type ErrorResponse struct {
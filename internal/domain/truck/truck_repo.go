@@ -5,32 +5,102 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"test-project/internal/domain/cargo"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PostgresTruckRepo struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	cargos cargo.CargoRepository
 }
 
 func NewPostgresTruckRepo(db *pgxpool.Pool) TruckRepository {
-	return &PostgresTruckRepo{db: db}
+	return &PostgresTruckRepo{db: db, cargos: cargo.NewPostgresCargoRepo(db)}
+}
+
+// truckColumns — общий список колонок машины; фото и документы
+// подтягиваются из files с owner_table = 'trucks'.
+const truckColumns = `
+    t.id,
+    t.name,
+    t.license_plate,
+    t.vin,
+    t.make,
+    t.model,
+    t.year,
+    t.payload_kg,
+    t.volume_m3,
+    t.active,
+    t."createdAt",
+    COALESCE((
+      SELECT json_agg(json_build_object('id', f.id, 'url', f.url, 'createdAt', f.created_at) ORDER BY f.created_at)
+        FROM files f
+       WHERE f.owner_table = 'trucks' AND f.owner_id = t.id AND f.kind = 'photo'
+    ), '[]') AS photos_json,
+    COALESCE((
+      SELECT json_agg(json_build_object('id', f.id, 'url', f.url, 'createdAt', f.created_at) ORDER BY f.created_at)
+        FROM files f
+       WHERE f.owner_table = 'trucks' AND f.owner_id = t.id AND f.kind = 'document'
+    ), '[]') AS documents_json`
+
+func scanTruck(row pgx.Row) (Truck, error) {
+	var (
+		t             Truck
+		photosJSON    []byte
+		documentsJSON []byte
+	)
+
+	if err := row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.LicensePlate,
+		&t.VIN,
+		&t.Make,
+		&t.Model,
+		&t.Year,
+		&t.PayloadKg,
+		&t.VolumeM3,
+		&t.Active,
+		&t.CreatedAt,
+		&photosJSON,
+		&documentsJSON,
+	); err != nil {
+		return Truck{}, err
+	}
+
+	if err := json.Unmarshal(photosJSON, &t.Photos); err != nil {
+		return Truck{}, fmt.Errorf("unmarshal photos: %w", err)
+	}
+	if err := json.Unmarshal(documentsJSON, &t.Documents); err != nil {
+		return Truck{}, fmt.Errorf("unmarshal documents: %w", err)
+	}
+
+	return t, nil
 }
 
 func (r *PostgresTruckRepo) Create(u Truck) (Truck, error) {
-	err := r.db.QueryRow(context.Background(), "INSERT INTO trucks (name) VALUES ($1) RETURNING id", u.Name).Scan(&u.ID)
+	var id string
+	err := r.db.QueryRow(context.Background(),
+		`INSERT INTO trucks (name, license_plate, vin, make, model, year, payload_kg, volume_m3, active)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id`,
+		u.Name, u.LicensePlate, u.VIN, u.Make, u.Model, u.Year, u.PayloadKg, u.VolumeM3, u.Active,
+	).Scan(&id)
 
 	if err != nil {
 		return Truck{}, err
 	}
 
-	return u, nil
+	return r.FindByID(id)
 }
 
 func (r *PostgresTruckRepo) FindAll() ([]Truck, error) {
-	rows, err := r.db.Query(context.Background(), "SELECT id, name FROM trucks")
+	rows, err := r.db.Query(context.Background(),
+		"SELECT"+truckColumns+"\nFROM trucks t\nWHERE t.deleted_at IS NULL\nORDER BY t.\"createdAt\"")
 	if err != nil {
 		return nil, err
 	}
@@ -38,125 +108,116 @@ func (r *PostgresTruckRepo) FindAll() ([]Truck, error) {
 
 	var trucks []Truck
 	for rows.Next() {
-		var t Truck
-		if err := rows.Scan(&t.ID, &t.Name); err != nil {
+		t, err := scanTruck(rows)
+		if err != nil {
 			return nil, err
 		}
 		trucks = append(trucks, t)
 	}
 
-	return trucks, nil
+	return trucks, rows.Err()
 }
 
 func (r *PostgresTruckRepo) FindByID(id string) (Truck, error) {
-	var t Truck
-	err := r.db.QueryRow(context.Background(), "SELECT id, name FROM trucks WHERE id = $1", id).Scan(&t.ID, &t.Name)
+	// кривой id не найдёт машину, а не уронит запрос ошибкой приведения к uuid
+	if uuid.Validate(id) != nil {
+		return Truck{}, fmt.Errorf("%w: id=%s", ErrNotFound, id)
+	}
+
+	t, err := scanTruck(r.db.QueryRow(context.Background(),
+		"SELECT"+truckColumns+"\nFROM trucks t\nWHERE t.id = $1 AND t.deleted_at IS NULL", id))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return Truck{}, fmt.Errorf("Машина с id=%d не существует", id)
+			return Truck{}, fmt.Errorf("%w: id=%s", ErrNotFound, id)
 		}
 
 		return Truck{}, err
 	}
 
-	return t, err
+	return t, nil
 }
 
-func (r *PostgresTruckRepo) GetTruckCargos(
-	id string, limit, page int,
-) ([]cargo.Cargo, error) {
-
-	if limit <= 0 {
-		limit = 10
-	}
-	if page <= 0 {
-		page = 1
-	}
-	offset := (page - 1) * limit
-
-	const q = `
-SELECT
-	c.id,
-	c.cargonumber          AS "cargoNumber",
-	c.date,
-	c.loadunloaddate       AS "loadUnloadDate",
-	c.driver,
-	c.transportationinfo   AS "transportationInfo",
-	c.payoutamount         AS "payoutAmount",
-	c.payoutdate           AS "payoutDate",
-	c.paymentstatus        AS "paymentStatus",
-	c.payoutterms          AS "payoutTerms",
-	c."createdAt",
-	c.truckid              AS "truckId",
-
-	/* -- агрегируем все файлы, привязанные к cargo -- */
-	COALESCE(
-	  json_agg(
-	    json_build_object(
-	      'id',         f.id,
-	      'url',        f.url,
-	      'cargoId',    f.owner_id,
-	      'createdAt',  f.created_at
-	    )
-	    ORDER BY f.created_at
-	  ) FILTER (WHERE f.id IS NOT NULL),
-	  '[]'
-	) AS photos_json
-
-FROM cargos c
-LEFT JOIN files f
-  ON     f.owner_table = 'cargos'
-     AND f.owner_id    = c.id
-
-WHERE c.truckid = $1
-GROUP BY c.id
-ORDER BY c."createdAt" DESC
-LIMIT  $2
-OFFSET $3;
-`
-
-	rows, err := r.db.Query(context.Background(), q, id, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+func (r *PostgresTruckRepo) Update(id string, t UpdateTruckInput) (Truck, error) {
+	query := "UPDATE trucks SET "
+	args := []interface{}{}
+	i := 1
 
-	var cargos []cargo.Cargo
-	for rows.Next() {
-		var (
-			c          cargo.Cargo
-			photosJSON []byte
-		)
-
-		if err := rows.Scan(
-			&c.ID,
-			&c.CargoNumber,
-			&c.Date,
-			&c.LoadUnloadDate,
-			&c.Driver,
-			&c.TransportationInfo,
-			&c.PayoutAmount,
-			&c.PayoutDate,
-			&c.PaymentStatus,
-			&c.PayoutTerms,
-			&c.CreatedAt,
-			&c.TruckID,
-			&photosJSON, // JSON-массив из запроса
-		); err != nil {
-			return nil, err
-		}
+	set := func(column string, value interface{}) {
+		query += fmt.Sprintf("%s = $%d, ", column, i)
+		args = append(args, value)
+		i++
+	}
 
-		// распаковываем JSON в срез CargoPhoto
-		if err := json.Unmarshal(photosJSON, &c.CargoPhotos); err != nil {
-			return nil, err
-		}
+	if t.Name != nil {
+		set("name", *t.Name)
+	}
+	if t.LicensePlate != nil {
+		set("license_plate", *t.LicensePlate)
+	}
+	if t.VIN != nil {
+		set("vin", *t.VIN)
+	}
+	if t.Make != nil {
+		set("make", *t.Make)
+	}
+	if t.Model != nil {
+		set("model", *t.Model)
+	}
+	if t.Year != nil {
+		set("year", *t.Year)
+	}
+	if t.PayloadKg != nil {
+		set("payload_kg", *t.PayloadKg)
+	}
+	if t.VolumeM3 != nil {
+		set("volume_m3", *t.VolumeM3)
+	}
+	if t.Active != nil {
+		set("active", *t.Active)
+	}
 
-		cargos = append(cargos, c)
+	if len(args) == 0 {
+		return r.FindByID(id)
 	}
 
-	if rows.Err() != nil {
-		return nil, rows.Err()
+	// убрать последнюю запятую
+	query = strings.TrimSuffix(query, ", ")
+	query += fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL", i)
+	args = append(args, id)
+
+	if _, err := r.db.Exec(context.Background(), query, args...); err != nil {
+		return Truck{}, err
 	}
-	return cargos, nil
+
+	return r.FindByID(id)
+}
+
+// Delete помечает машину удалённой. Грузы машины при этом не затрагиваются.
+func (r *PostgresTruckRepo) Delete(id string, deletedBy string) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE trucks SET deleted_at = now(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		id, deletedBy)
+	return err
+}
+
+// HasActiveCargos сообщает, есть ли у машины неудалённые грузы,
+// которые ещё не закрыты и не отменены.
+func (r *PostgresTruckRepo) HasActiveCargos(id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(context.Background(),
+		`SELECT EXISTS (
+		   SELECT 1 FROM cargos
+		    WHERE truckid = $1
+		      AND deleted_at IS NULL
+		      AND status NOT IN ('closed', 'cancelled')
+		 )`, id).Scan(&exists)
+	return exists, err
+}
+
+// GetTruckCargos использует тот же построитель запросов, что и список грузов,
+// ограничивая выборку грузами машины.
+func (r *PostgresTruckRepo) GetTruckCargos(id string, f cargo.ListFilter) (cargo.ListResult, error) {
+	f.TruckID = &id
+	return r.cargos.FindAll(f)
 }
@@ -8,6 +8,9 @@ const (
 	RoleUser       Role = "USER"
 	RoleEditor     Role = "EDITOR"
 	RoleSuperAdmin Role = "SUPERADMIN"
+	// RoleService — роль сервисного субъекта, вошедшего по ключу API. В БД
+	// не хранится и в AllRoles не входит: права ключа задаются при его создании.
+	RoleService Role = "SERVICE"
 )
 
 var AllRoles = []Role{
@@ -35,6 +38,17 @@ type UserRepository interface {
 	Delete(id string) error
 	FindByEmail(email string) (User, error)
 	Update(id string, user UpdateUser) error
+
+	// TokenVersion — версия токенов пользователя. Токены со старой версией
+	// недействительны; версия растёт при смене роли.
+	TokenVersion(id string) (int, error)
+	SetPassword(id, hash string) error
+
+	// Trucks возвращает ID машин, закреплённых за пользователем.
+	Trucks(id string) ([]string, error)
+	// SetTrucks заменяет закреплённые машины. Неизвестные или удалённые
+	// машины — ErrTruckNotFound.
+	SetTrucks(id string, truckIDs []string) error
 }
 
 type ListResponse struct {
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrResetTokenInvalid = errors.New("ссылка для сброса пароля недействительна или устарела")
+	ErrWrongPassword     = errors.New("Неверный текущий пароль")
+	ErrSamePassword      = errors.New("новый пароль совпадает с текущим")
+)
+
+// PasswordPolicy — требования к паролю, настраиваются через PASSWORD_* в окружении.
+type PasswordPolicy struct {
+	MinLength      int
+	RequireDigit   bool
+	RequireUpper   bool
+	RequireSpecial bool
+}
+
+// PolicyError перечисляет невыполненные требования к паролю.
+type PolicyError struct {
+	Violations []string
+}
+
+func (e *PolicyError) Error() string {
+	return "пароль не соответствует требованиям: " + strings.Join(e.Violations, "; ")
+}
+
+// Check возвращает *PolicyError, если пароль не соответствует политике.
+func (p PasswordPolicy) Check(password string) error {
+	var (
+		digit, upper, special bool
+		violations            []string
+	)
+	for _, r := range password {
+		switch {
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsUpper(r):
+			upper = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			special = true
+		}
+	}
+
+	if n := len([]rune(password)); n < p.MinLength {
+		violations = append(violations, fmt.Sprintf("не короче %d символов", p.MinLength))
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, "хотя бы одна цифра")
+	}
+	if p.RequireUpper && !upper {
+		violations = append(violations, "хотя бы одна заглавная буква")
+	}
+	if p.RequireSpecial && !special {
+		violations = append(violations, "хотя бы один спецсимвол")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// PasswordResetRepository хранит одноразовые токены сброса пароля.
+// В базе лежит только хэш токена.
+type PasswordResetRepository interface {
+	// Create сохраняет новый токен и гасит все прежние неиспользованные токены пользователя.
+	Create(userID, tokenHash string, expiresAt time.Time) error
+	// Consume помечает токен использованным и возвращает его пользователя.
+	// Использованный, просроченный или неизвестный токен — ErrResetTokenInvalid.
+	Consume(tokenHash string) (string, error)
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email" example:"firulvv@mail.ru"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required" example:"8c5b3f..."`
+	Password string `json:"password" validate:"required" example:"N3wPassw0rd"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required" example:"123456"`
+	NewPassword     string `json:"newPassword" validate:"required" example:"N3wPassw0rd"`
+}
+
+type PasswordResponse struct {
+	Message string      `json:"message" example:"Пароль изменён"`
+	Data    interface{} `json:"data"`
+}
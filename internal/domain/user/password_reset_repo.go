@@ -0,0 +1,53 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresPasswordResetRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresPasswordResetRepo(db *pgxpool.Pool) PasswordResetRepository {
+	return &PostgresPasswordResetRepo{db: db}
+}
+
+func (r *PostgresPasswordResetRepo) Create(userID, tokenHash string, expiresAt time.Time) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE password_resets SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO password_resets (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`,
+		tokenHash, userID, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresPasswordResetRepo) Consume(tokenHash string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(context.Background(),
+		`UPDATE password_resets
+		    SET used_at = now()
+		  WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		  RETURNING user_id`, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrResetTokenInvalid
+	}
+	return userID, err
+}
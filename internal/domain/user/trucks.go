@@ -0,0 +1,17 @@
+package user
+
+import "errors"
+
+// ErrTruckNotFound — среди закрепляемых машин есть несуществующая или удалённая.
+var ErrTruckNotFound = errors.New("машина не найдена или удалена")
+
+// SetTrucksRequest заменяет список машин, закреплённых за пользователем.
+// Пустой список снимает все закрепления.
+type SetTrucksRequest struct {
+	TruckIDs []string `json:"truckIds" validate:"required,dive,uuid" example:"c8169351-f6d8-4058-af4a-8ead3363fd92"`
+}
+
+type TrucksResponse struct {
+	Message string   `json:"message" example:"Машины пользователя"`
+	Data    []string `json:"data"`
+}
@@ -61,7 +61,7 @@ func (r *PostgresUserRepo) FindByID(id string) (User, error) {
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return User{}, fmt.Errorf("Пользователь с id=%d не существует", id)
+			return User{}, fmt.Errorf("Пользователь с id=%s не существует", id)
 		}
 
 		return User{}, err
@@ -122,7 +122,11 @@ func (r *PostgresUserRepo) Delete(id string) error {
 
 func (r *PostgresUserRepo) Update(id string, u UpdateUser) error {
 	_, err := r.db.Exec(context.Background(),
-		`UPDATE users SET username = $1, role = $2 WHERE id = $3`,
+		`UPDATE users
+		    SET username = $1,
+		        role = $2,
+		        token_version = token_version + (role <> $2::role)::int
+		  WHERE id = $3`,
 		u.Username, u.Role, id)
 
 	if err != nil {
@@ -131,3 +135,72 @@ func (r *PostgresUserRepo) Update(id string, u UpdateUser) error {
 
 	return nil
 }
+
+func (r *PostgresUserRepo) TokenVersion(id string) (int, error) {
+	var v int
+	err := r.db.QueryRow(context.Background(),
+		`SELECT token_version FROM users WHERE id::text = $1`, id).Scan(&v)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrUserNotFound
+	}
+	return v, err
+}
+
+func (r *PostgresUserRepo) SetPassword(id, hash string) error {
+	_, err := r.db.Exec(context.Background(), `UPDATE users SET password = $1 WHERE id = $2`, hash, id)
+	return err
+}
+
+func (r *PostgresUserRepo) Trucks(id string) ([]string, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT truck_id FROM user_trucks WHERE user_id = $1 ORDER BY created_at, truck_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var truckID string
+		if err := rows.Scan(&truckID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, truckID)
+	}
+	return ids, rows.Err()
+}
+
+func (r *PostgresUserRepo) SetTrucks(id string, truckIDs []string) error {
+	ctx := context.Background()
+
+	unique := make(map[string]struct{}, len(truckIDs))
+	for _, t := range truckIDs {
+		unique[t] = struct{}{}
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_trucks WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO user_trucks (user_id, truck_id)
+		 SELECT $1, t.id
+		   FROM trucks t
+		  WHERE t.id = ANY($2::uuid[]) AND t.deleted_at IS NULL`,
+		id, truckIDs)
+	if err != nil {
+		return err
+	}
+	// часть машин не нашлась — ничего не меняем
+	if tag.RowsAffected() != int64(len(unique)) {
+		return ErrTruckNotFound
+	}
+
+	return tx.Commit(ctx)
+}
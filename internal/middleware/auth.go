@@ -5,9 +5,15 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"test-project/internal/domain/apikey"
 	"test-project/internal/domain/auth"
+	"test-project/internal/domain/impersonation"
+	"test-project/internal/domain/permission"
 	"test-project/internal/domain/user"
+	"test-project/internal/usecase"
 	"test-project/utils"
+
+	"go.uber.org/zap"
 )
 
 type ctxKey string
@@ -15,30 +21,169 @@ type ctxKey string
 const (
 	UserIDKey   ctxKey = "userID"
 	UserRoleKey ctxKey = "userRole"
+	SessionKey  ctxKey = "sessionID"
+	// ImpersonationKey — вход от имени пользователя, которым сделан запрос.
+	ImpersonationKey ctxKey = "impersonation"
 )
 
+// JwtMiddleware пускает к маршруту любого вошедшего пользователя.
 func JwtMiddleware(deps *auth.Deps, next http.HandlerFunc) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		h := r.Header.Get("Authorization")
-		parts := strings.SplitN(h, " ", 2)
-		if len(parts) != 2 {
-			utils.JSON(w, http.StatusUnauthorized, "missing token", nil, deps.Logger)
-			return
-		}
-		uid, role, err := deps.JwtService.ValidateAccess(parts[1])
+	return &Policy{Name: "authenticated", next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated(deps, w, r, func(w http.ResponseWriter, r *http.Request) {
+			if r, ok := withPermissions(deps, w, r); ok {
+				next(w, r)
+			}
+		})
+	})}
+}
+
+// authenticated проверяет вход и вызывает next. Запросы с токеном входа от
+// имени пользователя помечаются в логе и вместе с кодом ответа пишутся в
+// журнал этого входа.
+func authenticated(deps *auth.Deps, w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	r, ok := authenticate(deps, w, r)
+	if !ok {
+		return
+	}
+	imp, ok := GetImpersonation(r.Context())
+	if !ok {
+		next(w, r)
+		return
+	}
+
+	deps.Logger.Info("запрос от имени пользователя",
+		zap.String("impersonation", imp.ID),
+		zap.String("actor", imp.ActorID),
+		zap.String("user", imp.UserID),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	next(rec, r)
+
+	err := deps.Impersonations.Record(imp.ID, impersonation.Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Status: rec.status,
+	})
+	if err != nil {
+		deps.Logger.Error("не удалось записать запрос в журнал входа от имени пользователя",
+			zap.String("impersonation", imp.ID), zap.Error(err))
+	}
+}
+
+// statusRecorder запоминает код ответа обработчика.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// authenticate проверяет access-токен или ключ API и кладёт пользователя в
+// контекст запроса. Если токен не годится, сам отвечает 401.
+func authenticate(deps *auth.Deps, w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return authenticateKey(deps, w, r, key)
+	}
+
+	h := r.Header.Get("Authorization")
+	parts := strings.SplitN(h, " ", 2)
+	if len(parts) != 2 {
+		utils.JSON(w, http.StatusUnauthorized, "missing token", nil, deps.Logger)
+		return nil, false
+	}
+	if strings.HasPrefix(parts[1], usecase.APIKeyPrefix) {
+		return authenticateKey(deps, w, r, parts[1])
+	}
+	claims, err := deps.JwtService.ValidateAccess(parts[1])
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, deps.Logger)
+		return nil, false
+	}
+
+	// токен завершённой сессии, удалённого пользователя или выданный
+	// до смены роли больше не действует
+	valid, err := deps.AuthService.AccessValid(claims)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, deps.Logger)
+		return nil, false
+	}
+	if valid && claims.ActorID != "" {
+		// у роли администратора могли отнять право входить от имени других
+		valid, err = actorMayImpersonate(deps, claims.ActorRole)
 		if err != nil {
-			utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, deps.Logger)
-			return
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, deps.Logger)
+			return nil, false
 		}
+	}
+	if !valid {
+		utils.JSON(w, http.StatusUnauthorized, "token is revoked", nil, deps.Logger)
+		return nil, false
+	}
+
+	uid, role := claims.UserID, claims.Role
 
+	// передаём в ctx
+	ctx := context.WithValue(r.Context(), UserIDKey, uid)
+	ctx = context.WithValue(ctx, UserRoleKey, role)
+	ctx = context.WithValue(ctx, SessionKey, claims.SessionID)
+
+	if claims.ActorID != "" {
+		// суперадминистратор за пользователя: онлайн он от этого не становится
+		ctx = context.WithValue(ctx, ImpersonationKey, impersonation.Impersonation{
+			ID:      claims.ImpersonationID,
+			ActorID: claims.ActorID,
+			UserID:  uid,
+		})
+	} else {
 		// помечаем онлайн
 		deps.AuthService.TouchOnline(uid)
-		// передаём в ctx
-		ctx := context.WithValue(r.Context(), UserIDKey, uid)
-		ctx = context.WithValue(ctx, UserRoleKey, role)
+	}
 
-		next(w, r.WithContext(ctx))
-	})
+	return r.WithContext(ctx), true
+}
+
+func actorMayImpersonate(deps *auth.Deps, role user.Role) (bool, error) {
+	perms, err := deps.Permissions.ForRole(role)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range perms {
+		if p == permission.UserImpersonate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// authenticateKey пускает интеграцию по ключу API. В контекст попадает
+// сервисный субъект: ID ключа, роль SERVICE и права самого ключа вместо
+// прав роли.
+func authenticateKey(deps *auth.Deps, w http.ResponseWriter, r *http.Request, key string) (*http.Request, bool) {
+	k, err := deps.APIKeys.Authenticate(key)
+	if err != nil {
+		if errors.Is(err, apikey.ErrInvalid) {
+			utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, deps.Logger)
+		} else {
+			utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, deps.Logger)
+		}
+		return nil, false
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, k.ID)
+	ctx = context.WithValue(ctx, UserRoleKey, user.RoleService)
+	ctx = context.WithValue(ctx, PermissionsKey, k.Permissions)
+
+	return r.WithContext(ctx), true
 }
 
 func GetUserRole(ctx context.Context) (user.Role, error) {
@@ -52,3 +197,41 @@ func GetUserRole(ctx context.Context) (user.Role, error) {
 	}
 	return role, nil
 }
+
+func GetUserID(ctx context.Context) (string, error) {
+	val := ctx.Value(UserIDKey)
+	if val == nil {
+		return "", errors.New("user id not found in context")
+	}
+	id, ok := val.(string)
+	if !ok || id == "" {
+		return "", errors.New("invalid user id type in context")
+	}
+	return id, nil
+}
+
+// GetImpersonation возвращает вход от имени пользователя, если запрос
+// сделан с его токеном. Заполнены только ID, ActorID и UserID.
+func GetImpersonation(ctx context.Context) (impersonation.Impersonation, bool) {
+	imp, ok := ctx.Value(ImpersonationKey).(impersonation.Impersonation)
+	return imp, ok
+}
+
+// DenyImpersonation закрывает действие для входа от имени пользователя:
+// смену пароля, настройку 2FA, завершение сессий.
+func DenyImpersonation(deps *auth.Deps, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetImpersonation(r.Context()); ok {
+			utils.JSON(w, http.StatusUnauthorized, impersonation.ErrDenied.Error(), nil, deps.Logger)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// GetSessionID возвращает сессию, при входе в которую выдан access-токен.
+// Для токенов, выпущенных до появления сессий, — пустая строка.
+func GetSessionID(ctx context.Context) string {
+	id, _ := ctx.Value(SessionKey).(string)
+	return id
+}
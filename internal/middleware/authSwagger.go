@@ -3,7 +3,7 @@ package middleware
 import "net/http"
 
 func AuthSwagger(next http.Handler, username, password string) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return &Policy{Name: "basic auth", next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		u, p, ok := r.BasicAuth()
 		if !ok || u != username || p != password {
 			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
@@ -11,5 +11,5 @@ func AuthSwagger(next http.Handler, username, password string) http.Handler {
 			return
 		}
 		next.ServeHTTP(w, r)
-	})
+	})}
 }
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"test-project/internal/domain/auth"
+	"test-project/internal/domain/cargo"
+	"test-project/internal/domain/permission"
+	"test-project/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const PermissionsKey ctxKey = "permissions"
+
+// Policy — обработчик маршрута с явно заданной политикой доступа. Маршруты
+// регистрируются через Public, JwtMiddleware или Require; CheckPolicies при
+// старте не даст забыть про это.
+type Policy struct {
+	Name string
+	next http.Handler
+}
+
+func (p *Policy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.next.ServeHTTP(w, r)
+}
+
+// Public открывает маршрут без входа в систему.
+func Public(next http.Handler) http.Handler {
+	return &Policy{Name: "public", next: next}
+}
+
+// Require пускает к маршруту пользователя, роли которого выдано разрешение
+// perm. Разрешения роли кладутся в контекст (см. HasPermission).
+func Require(deps *auth.Deps, perm permission.Permission, next http.HandlerFunc) http.Handler {
+	return &Policy{Name: "permission " + string(perm), next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated(deps, w, r, func(w http.ResponseWriter, r *http.Request) {
+			r, ok := withPermissions(deps, w, r)
+			if !ok {
+				return
+			}
+
+			if !HasPermission(r.Context(), perm) {
+				utils.JSON(w, http.StatusUnauthorized, "Недостаточно прав: нужно разрешение "+string(perm), nil, deps.Logger)
+				return
+			}
+
+			next(w, r)
+		})
+	})}
+}
+
+// withPermissions кладёт в контекст разрешения роли пользователя запроса.
+// Права ключа API уже положены при входе и не заменяются правами роли. При
+// входе от имени пользователя права из ImpersonationDenied отбрасываются.
+func withPermissions(deps *auth.Deps, w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if _, ok := r.Context().Value(PermissionsKey).([]permission.Permission); ok {
+		return r, true
+	}
+
+	role, err := GetUserRole(r.Context())
+	if err != nil {
+		utils.JSON(w, http.StatusUnauthorized, err.Error(), nil, deps.Logger)
+		return nil, false
+	}
+	perms, err := deps.Permissions.ForRole(role)
+	if err != nil {
+		utils.JSON(w, http.StatusInternalServerError, err.Error(), nil, deps.Logger)
+		return nil, false
+	}
+	if _, ok := GetImpersonation(r.Context()); ok {
+		perms = withoutImpersonationDenied(perms)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), PermissionsKey, perms)), true
+}
+
+func withoutImpersonationDenied(perms []permission.Permission) []permission.Permission {
+	allowed := make([]permission.Permission, 0, len(perms))
+	for _, p := range perms {
+		denied := false
+		for _, d := range permission.ImpersonationDenied {
+			if p == d {
+				denied = true
+				break
+			}
+		}
+		if !denied {
+			allowed = append(allowed, p)
+		}
+	}
+	return allowed
+}
+
+// HasPermission сообщает, что у пользователя запроса есть разрешение perm.
+// Работает на маршрутах, закрытых Require или JwtMiddleware.
+func HasPermission(ctx context.Context, perm permission.Permission) bool {
+	for _, p := range Permissions(ctx) {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions возвращает разрешения пользователя запроса: права роли или,
+// для ключа API, права ключа.
+func Permissions(ctx context.Context) []permission.Permission {
+	perms, _ := ctx.Value(PermissionsKey).([]permission.Permission)
+	return perms
+}
+
+// CargoScope возвращает, какие грузы видит пользователь запроса: все — с
+// разрешением cargo:view_all, иначе только грузы закреплённых за ним машин.
+func CargoScope(ctx context.Context) cargo.Scope {
+	if HasPermission(ctx, permission.CargoViewAll) {
+		return cargo.AllCargos
+	}
+	userID, _ := GetUserID(ctx)
+	return cargo.Scope{UserID: userID}
+}
+
+// CheckPolicies проверяет, что у каждого маршрута задана политика доступа.
+func CheckPolicies(r *mux.Router) error {
+	var missing []string
+
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		h := route.GetHandler()
+		if h == nil {
+			// подроутер: его маршруты обходятся отдельно
+			return nil
+		}
+		if _, ok := h.(*Policy); ok {
+			return nil
+		}
+
+		path, _ := route.GetPathTemplate()
+		methods, _ := route.GetMethods()
+		missing = append(missing, strings.TrimSpace(strings.Join(methods, ",")+" "+path))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("маршруты без политики доступа (Public, JwtMiddleware или Require): %s", strings.Join(missing, "; "))
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 )
 
 type Client struct {
@@ -27,3 +29,106 @@ func (c *Client) SetEX(key, val string, ttl time.Duration) error {
 func (c *Client) Keys(pattern string) ([]string, error) {
 	return c.Client.Keys(c.ctx, pattern).Result()
 }
+
+// Get возвращает значение ключа; для отсутствующего ключа — пустую строку.
+func (c *Client) Get(key string) (string, error) {
+	val, err := c.Client.Get(c.ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+// GetDel возвращает значение ключа и удаляет его; для отсутствующего
+// ключа — пустую строку. Подходит для одноразовых значений.
+func (c *Client) GetDel(key string) (string, error) {
+	val, err := c.Client.GetDel(c.ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+func (c *Client) Del(keys ...string) error {
+	return c.Client.Del(c.ctx, keys...).Err()
+}
+
+var compareAndSwap = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if cur == ARGV[1] then
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+  return {1, cur}
+end
+if not cur then
+  return {0, ''}
+end
+return {0, cur}
+`)
+
+// CompareAndSwap атомарно заменяет значение ключа на next с новым TTL, если
+// текущее значение равно expected. Если замены не было, возвращается текущее
+// значение (пустая строка — ключа нет).
+func (c *Client) CompareAndSwap(key, expected, next string, ttl time.Duration) (string, bool, error) {
+	res, err := compareAndSwap.Run(c.ctx, c.Client, []string{key}, expected, next, ttl.Milliseconds()).Slice()
+	if err != nil {
+		return "", false, err
+	}
+
+	swapped, _ := res[0].(int64)
+	current, _ := res[1].(string)
+	return current, swapped == 1, nil
+}
+
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+if redis.call('ZCARD', KEYS[1]) < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[4])
+  redis.call('PEXPIRE', KEYS[1], window)
+  return {1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// SlidingWindow учитывает запрос в скользящем окне: не больше limit
+// запросов за window. Если лимит исчерпан, запрос не учитывается и
+// возвращается время до освобождения места в окне.
+func (c *Client) SlidingWindow(key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	member := strconv.FormatInt(now, 10) + "-" + uuid.NewString()
+
+	res, err := slidingWindow.Run(c.ctx, c.Client, []string{key}, now, window.Milliseconds(), limit, member).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	allowed, _ := res[0].(int64)
+	wait, _ := res[1].(int64)
+	return allowed == 1, time.Duration(wait) * time.Millisecond, nil
+}
+
+var incrWithTTL = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// IncrWithTTL увеличивает счётчик; TTL ставится при его создании и
+// последующими вызовами не продлевается.
+func (c *Client) IncrWithTTL(key string, ttl time.Duration) (int64, error) {
+	return incrWithTTL.Run(c.ctx, c.Client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+// TTL возвращает оставшееся время жизни ключа; 0 — ключа нет или он бессрочный.
+func (c *Client) TTL(key string) (time.Duration, error) {
+	ttl, err := c.Client.PTTL(c.ctx, key).Result()
+	if err != nil || ttl < 0 {
+		return 0, err
+	}
+	return ttl, nil
+}
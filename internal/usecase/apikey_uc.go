@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	apikeyDomain "test-project/internal/domain/apikey"
+	permissionDomain "test-project/internal/domain/permission"
+	"time"
+)
+
+type APIKeyUsecase interface {
+	// Create выпускает ключ. Сам ключ возвращается только здесь.
+	Create(input apikeyDomain.CreateRequest, createdBy string) (apikeyDomain.Created, error)
+	List() ([]apikeyDomain.APIKey, error)
+	Revoke(id string) error
+	// Authenticate находит действующий ключ и отмечает его использование.
+	Authenticate(key string) (apikeyDomain.APIKey, error)
+}
+
+// APIKeyPrefix отличает ключ API от access-токена в заголовке Authorization.
+const APIKeyPrefix = "tpk_"
+
+// apiKeyShownPrefix — сколько первых символов ключа хранится открыто,
+// чтобы ключ можно было узнать в списке.
+const apiKeyShownPrefix = len(APIKeyPrefix) + 8
+
+type apiKeyUsecase struct {
+	repo        apikeyDomain.Repository
+	permissions permissionDomain.Repository
+}
+
+func NewAPIKeyUsecase(repo apikeyDomain.Repository, permissions permissionDomain.Repository) APIKeyUsecase {
+	return &apiKeyUsecase{repo: repo, permissions: permissions}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *apiKeyUsecase) Create(input apikeyDomain.CreateRequest, createdBy string) (apikeyDomain.Created, error) {
+	if !input.ExpiresAt.After(time.Now()) {
+		return apikeyDomain.Created{}, apikeyDomain.ErrExpiry
+	}
+
+	registry, err := u.permissions.All()
+	if err != nil {
+		return apikeyDomain.Created{}, err
+	}
+	known := make(map[permissionDomain.Permission]bool, len(registry))
+	for _, d := range registry {
+		known[d.Name] = true
+	}
+	for _, p := range input.Permissions {
+		if p == permissionDomain.APIKeyManage {
+			return apikeyDomain.Created{}, apikeyDomain.ErrSelfManage
+		}
+		if !known[p] {
+			return apikeyDomain.Created{}, fmt.Errorf("%w: %s", permissionDomain.ErrUnknown, p)
+		}
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return apikeyDomain.Created{}, err
+	}
+	key := APIKeyPrefix + hex.EncodeToString(buf)
+
+	k, err := u.repo.Create(apikeyDomain.APIKey{
+		Name:        strings.TrimSpace(input.Name),
+		Prefix:      key[:apiKeyShownPrefix],
+		Permissions: input.Permissions,
+		ExpiresAt:   input.ExpiresAt,
+		CreatedBy:   &createdBy,
+	}, hashAPIKey(key))
+	if err != nil {
+		return apikeyDomain.Created{}, err
+	}
+
+	return apikeyDomain.Created{APIKey: k, Key: key}, nil
+}
+
+func (u *apiKeyUsecase) List() ([]apikeyDomain.APIKey, error) {
+	return u.repo.FindAll()
+}
+
+func (u *apiKeyUsecase) Revoke(id string) error {
+	return u.repo.Revoke(id)
+}
+
+func (u *apiKeyUsecase) Authenticate(key string) (apikeyDomain.APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return apikeyDomain.APIKey{}, apikeyDomain.ErrInvalid
+	}
+
+	k, err := u.repo.FindActiveByHash(hashAPIKey(key))
+	if err != nil {
+		return apikeyDomain.APIKey{}, err
+	}
+	if err := u.repo.Touch(k.ID); err != nil {
+		return apikeyDomain.APIKey{}, err
+	}
+	return k, nil
+}
@@ -1,9 +1,15 @@
 package usecase
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"time"
 
+	invitationDomain "test-project/internal/domain/invitation"
+	mfaDomain "test-project/internal/domain/mfa"
+	sessionDomain "test-project/internal/domain/session"
 	userDomain "test-project/internal/domain/user"
 	"test-project/internal/redis"
 
@@ -11,8 +17,31 @@ import (
 )
 
 type AuthUsecase interface {
-	Register(email, password string) (userDomain.User, error)
-	Login(email, password string) (string, string, error)
+	// Register создаёт пользователя по приглашению с ролью и именем из него.
+	// Приглашение гасится в той же транзакции.
+	Register(inviteToken, email, password string) (userDomain.User, error)
+	// Login проверяет пароль. Если нужен второй фактор, вместо пары токенов
+	// возвращается MFA-токен, с которым вход завершается через LoginMFA
+	// или, при обязательной 2FA, через LoginMFASetup и LoginMFAConfirm.
+	Login(email, password, userAgent, ip string) (LoginResult, error)
+	LoginMFA(mfaToken, code, userAgent, ip string) (LoginResult, error)
+	LoginMFASetup(mfaToken string) (mfaDomain.Enrollment, error)
+	LoginMFAConfirm(mfaToken, code, userAgent, ip string) (LoginResult, error)
+	// MagicLink выпускает одноразовый токен ссылки для входа, привязанный к
+	// nonce из куки браузера. Для неизвестного email возвращается пустой
+	// токен без ошибки, чтобы не раскрывать адреса.
+	MagicLink(email, nonce string) (string, error)
+	// LoginMagicLink меняет токен из ссылки на пару токенов или, как Login,
+	// на MFA-токен, если нужен второй фактор.
+	LoginMagicLink(token, nonce, userAgent, ip string) (LoginResult, error)
+	// LoginExternal завершает вход, подтверждённый внешним провайдером
+	// (OIDC), или, как Login, выдаёт MFA-токен. См. ExternalIdentity.
+	LoginExternal(identity ExternalIdentity, userAgent, ip string) (LoginResult, error)
+	// UnlockAccount снимает блокировку входа после неудачных попыток.
+	UnlockAccount(userID string) error
+	Refresh(refreshToken string) (string, string, error)
+	Logout(refreshToken string) error
+	AccessValid(claims AccessClaims) (bool, error)
 	TouchOnline(userID string) error
 	OnlineUsers(since time.Duration) ([]string, error)
 
@@ -20,49 +49,414 @@ type AuthUsecase interface {
 	FindByEmail(email string) (userDomain.User, error)
 }
 
+var (
+	ErrRefreshInvalid = errors.New("Невалидный refresh токен")
+	ErrRefreshReused  = errors.New("Refresh токен уже был использован, сессия завершена. Войдите заново")
+	ErrNoAccount      = errors.New("Нет учётной записи или действующего приглашения для этого email")
+	ErrMagicLink      = errors.New("Ссылка для входа недействительна, уже использована или открыта не в том браузере, где её запросили")
+)
+
+// ExternalIdentity — пользователь, которого подтвердил внешний провайдер.
+// Role, если задана, заменяет роль учётной записи: ролями управляет
+// провайдер через группы.
+type ExternalIdentity struct {
+	Email    string
+	Username string
+	Role     *userDomain.Role
+}
+
+// LoginResult — итог шага входа: либо пара токенов, либо MFA-токен и шаг,
+// который осталось пройти (MFAPurposeVerify или MFAPurposeSetup).
+type LoginResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+	MFAStep      string
+	// RecoveryCodes — резервные коды, выданные при подключении 2FA во время входа.
+	RecoveryCodes []string
+}
+
 type usecase struct {
-	repo  userDomain.UserRepository
-	jwt   *JwtUsecase
-	redis *redis.Client
+	repo        userDomain.UserRepository
+	sessions    sessionDomain.SessionRepository
+	invitations invitationDomain.InvitationRepository
+	mfa         MfaUsecase
+	versions    tokenVersions
+	lockout     lockout
+	jwt         *JwtUsecase
+	redis       *redis.Client
+	magicTTL    time.Duration
 }
 
-func NewService(r userDomain.UserRepository, j *JwtUsecase, rc *redis.Client) AuthUsecase {
-	return &usecase{repo: r, jwt: j, redis: rc}
+func NewService(r userDomain.UserRepository, sessions sessionDomain.SessionRepository, invitations invitationDomain.InvitationRepository, mfa MfaUsecase, j *JwtUsecase, rc *redis.Client, lockoutPolicy LockoutPolicy, magicTTL time.Duration) AuthUsecase {
+	return &usecase{
+		repo:        r,
+		sessions:    sessions,
+		invitations: invitations,
+		mfa:         mfa,
+		versions:    tokenVersions{repo: r, redis: rc},
+		lockout:     lockout{redis: rc, policy: lockoutPolicy},
+		jwt:         j,
+		redis:       rc,
+		magicTTL:    magicTTL,
+	}
 }
 
-func (u *usecase) Register(email, password string) (userDomain.User, error) {
+func (u *usecase) Register(inviteToken, email, password string) (userDomain.User, error) {
 	if _, err := u.repo.FindByEmail(email); err == nil {
-		return userDomain.User{}, errors.New("Пользователь с таким email уже существует")
+		return userDomain.User{}, invitationDomain.ErrUserExists
 	}
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return u.repo.Create(
-		userDomain.User{
-			Email:    email,
-			Password: string(hash),
-		},
-	)
+	return u.invitations.Accept(inviteToken, email, string(hash))
 }
 
-func (u *usecase) Login(email, password string) (string, string, error) {
+func (u *usecase) Login(email, password, userAgent, ip string) (LoginResult, error) {
 	user, err := u.repo.FindByEmail(email)
 	if err != nil {
-		return "", "", err
+		return LoginResult{}, err
+	}
+	if err := u.lockout.Check(user.ID); err != nil {
+		return LoginResult{}, err
 	}
 	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
-		return "", "", errors.New("Неверный пароль")
+		if err := u.lockout.Fail(user.ID); err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, errors.New("Неверный пароль")
+	}
+
+	return u.firstFactorPassed(user, userAgent, ip)
+}
+
+// firstFactorPassed продолжает вход после пароля или ссылки из письма:
+// выдаёт MFA-токен, если нужен второй фактор, иначе пару токенов.
+func (u *usecase) firstFactorPassed(user userDomain.User, userAgent, ip string) (LoginResult, error) {
+	enabled, err := u.mfa.Enabled(user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	step := ""
+	switch {
+	case enabled:
+		step = MFAPurposeVerify
+	case u.mfa.Required(user.Role):
+		step = MFAPurposeSetup
+	}
+	if step != "" {
+		token, err := u.jwt.GenerateMFA(user.ID, step)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{MFAToken: token, MFAStep: step}, nil
 	}
 
-	accessToken, err := u.jwt.GenerateAccess(user.ID, user.Role)
+	if err := u.lockout.Reset(user.ID); err != nil {
+		return LoginResult{}, err
+	}
+	return u.issueTokens(user, userAgent, ip)
+}
+
+// LoginMFA завершает вход кодом из приложения или резервным кодом.
+func (u *usecase) LoginMFA(mfaToken, code, userAgent, ip string) (LoginResult, error) {
+	userID, err := u.jwt.ValidateMFA(mfaToken, MFAPurposeVerify)
+	if err != nil {
+		return LoginResult{}, mfaDomain.ErrChallengeFailed
+	}
+	if err := u.lockout.Check(userID); err != nil {
+		return LoginResult{}, err
+	}
+	if err := u.mfa.Verify(userID, code); err != nil {
+		return LoginResult{}, u.mfaFailed(userID, err)
+	}
+	if err := u.lockout.Reset(userID); err != nil {
+		return LoginResult{}, err
+	}
+
+	user, err := u.repo.FindByID(userID)
+	if err != nil {
+		return LoginResult{}, mfaDomain.ErrChallengeFailed
+	}
+	return u.issueTokens(user, userAgent, ip)
+}
+
+// LoginMFASetup начинает обязательное подключение 2FA прямо во время входа.
+func (u *usecase) LoginMFASetup(mfaToken string) (mfaDomain.Enrollment, error) {
+	userID, err := u.jwt.ValidateMFA(mfaToken, MFAPurposeSetup)
+	if err != nil {
+		return mfaDomain.Enrollment{}, mfaDomain.ErrChallengeFailed
+	}
+	return u.mfa.Enroll(userID)
+}
+
+// LoginMFAConfirm подтверждает подключение 2FA первым кодом и завершает вход.
+func (u *usecase) LoginMFAConfirm(mfaToken, code, userAgent, ip string) (LoginResult, error) {
+	userID, err := u.jwt.ValidateMFA(mfaToken, MFAPurposeSetup)
+	if err != nil {
+		return LoginResult{}, mfaDomain.ErrChallengeFailed
+	}
+	if err := u.lockout.Check(userID); err != nil {
+		return LoginResult{}, err
+	}
+	codes, err := u.mfa.Confirm(userID, code)
+	if err != nil {
+		return LoginResult{}, u.mfaFailed(userID, err)
+	}
+	if err := u.lockout.Reset(userID); err != nil {
+		return LoginResult{}, err
+	}
+
+	user, err := u.repo.FindByID(userID)
+	if err != nil {
+		return LoginResult{}, mfaDomain.ErrChallengeFailed
+	}
+	res, err := u.issueTokens(user, userAgent, ip)
+	res.RecoveryCodes = codes
+	return res, err
+}
+
+// magicLinkKey — ключ Redis неиспользованной ссылки для входа.
+func magicLinkKey(jti string) string {
+	return "magic:" + jti
+}
+
+func hashNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *usecase) MagicLink(email, nonce string) (string, error) {
+	user, err := u.repo.FindByEmail(email)
+	if err != nil {
+		return "", nil
+	}
+
+	token, jti, err := u.jwt.GenerateMagic(user.ID, hashNonce(nonce), u.magicTTL)
+	if err != nil {
+		return "", err
+	}
+	if err := u.redis.SetEX(magicLinkKey(jti), user.ID, u.magicTTL); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// LoginMagicLink гасит ссылку, только если она открыта в том же браузере:
+// ссылка, открытая в чужом, остаётся действующей.
+func (u *usecase) LoginMagicLink(token, nonce, userAgent, ip string) (LoginResult, error) {
+	claims, err := u.jwt.ValidateMagic(token)
+	if err != nil || nonce == "" {
+		return LoginResult{}, ErrMagicLink
+	}
+	if subtle.ConstantTimeCompare([]byte(hashNonce(nonce)), []byte(claims.NonceHash)) != 1 {
+		return LoginResult{}, ErrMagicLink
+	}
+
+	userID, err := u.redis.GetDel(magicLinkKey(claims.ID))
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if userID != claims.UserID {
+		return LoginResult{}, ErrMagicLink
+	}
+
+	user, err := u.repo.FindByID(userID)
+	if err != nil {
+		return LoginResult{}, ErrMagicLink
+	}
+	return u.firstFactorPassed(user, userAgent, ip)
+}
+
+// LoginExternal находит пользователя по email, а если его нет — принимает
+// приглашение на этот email и создаёт учётную запись без пароля. Вход у
+// провайдера заменяет только пароль: второй фактор, как и при входе по
+// паролю, проверяется у нас.
+func (u *usecase) LoginExternal(identity ExternalIdentity, userAgent, ip string) (LoginResult, error) {
+	user, err := u.repo.FindByEmail(identity.Email)
+	if err != nil {
+		user, err = u.invitations.AcceptByEmail(identity.Email, identity.Username)
+		if errors.Is(err, invitationDomain.ErrInvalid) {
+			return LoginResult{}, ErrNoAccount
+		}
+		if err != nil {
+			return LoginResult{}, err
+		}
+	}
+
+	if identity.Role != nil && *identity.Role != user.Role {
+		// смена роли повышает версию токенов, старые токены перестают действовать
+		if err := u.repo.Update(user.ID, userDomain.UpdateUser{Username: user.Username, Role: string(*identity.Role)}); err != nil {
+			return LoginResult{}, err
+		}
+		if err := u.versions.Invalidate(user.ID); err != nil {
+			return LoginResult{}, err
+		}
+		user.Role = *identity.Role
+	}
+
+	return u.firstFactorPassed(user, userAgent, ip)
+}
+
+// mfaFailed засчитывает неверный код второго фактора как неудачный вход.
+func (u *usecase) mfaFailed(userID string, err error) error {
+	if !errors.Is(err, mfaDomain.ErrInvalidCode) {
+		return err
+	}
+	if lockErr := u.lockout.Fail(userID); lockErr != nil {
+		return lockErr
+	}
+	return err
+}
+
+func (u *usecase) UnlockAccount(userID string) error {
+	return u.lockout.Reset(userID)
+}
+
+// issueTokens открывает новую сессию и выдаёт для неё пару токенов.
+func (u *usecase) issueTokens(user userDomain.User, userAgent, ip string) (LoginResult, error) {
+	version, err := u.versions.Get(user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	// каждый вход — новая сессия и новое семейство refresh-токенов
+	sess, err := u.sessions.Create(sessionDomain.Session{
+		UserID:    user.ID,
+		UserAgent: userAgent,
+		IP:        ip,
+		ExpiresAt: time.Now().Add(u.jwt.RefreshTTL()),
+	})
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	accessToken, err := u.jwt.GenerateAccess(user.ID, user.Role, sess.ID, version)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	refreshToken, jti, err := u.jwt.GenerateRefresh(user.ID, user.Role, sess.ID, version)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if err := u.redis.SetEX(refreshFamilyKey(sess.ID), jti, u.jwt.RefreshTTL()); err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// refreshFamilyKey — ключ Redis, где хранится jti последнего выданного
+// refresh-токена семейства. Нет ключа — семейство отозвано или истекло.
+func refreshFamilyKey(familyID string) string {
+	return "refresh:family:" + familyID
+}
+
+// Refresh выдаёт новую пару токенов и ротирует refresh-токен. Повторное
+// предъявление уже заменённого токена означает утечку: всё семейство
+// отзывается, и пользователю придётся войти заново. Токены, выданные до
+// смены роли или удаления пользователя, не принимаются; роль для новых
+// токенов читается из базы.
+func (u *usecase) Refresh(refreshToken string) (string, string, error) {
+	claims, err := u.jwt.ValidateRefresh(refreshToken)
+	if err != nil {
+		return "", "", ErrRefreshInvalid
+	}
+
+	current, err := u.versions.Current(claims.UserID, claims.Version)
 	if err != nil {
 		return "", "", err
 	}
+	if !current {
+		return "", "", ErrRefreshInvalid
+	}
 
-	refreshToken, err := u.jwt.GenerateRefresh(user.ID, user.Role)
+	user, err := u.repo.FindByID(claims.UserID)
+	if err != nil {
+		return "", "", ErrRefreshInvalid
+	}
+
+	newRefresh, jti, err := u.jwt.GenerateRefresh(user.ID, user.Role, claims.FamilyID, claims.Version)
 	if err != nil {
 		return "", "", err
 	}
 
-	return accessToken, refreshToken, nil
+	key := refreshFamilyKey(claims.FamilyID)
+	latest, swapped, err := u.redis.CompareAndSwap(key, claims.ID, jti, u.jwt.RefreshTTL())
+	if err != nil {
+		return "", "", err
+	}
+	if !swapped {
+		if latest == "" {
+			return "", "", ErrRefreshInvalid
+		}
+		// повторное использование — вся семья отзывается, как при выходе,
+		// чтобы сессия не висела активной в списке
+		if err := revokeSession(u.redis, u.sessions, claims.FamilyID); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshReused
+	}
+
+	if err := u.sessions.Touch(claims.FamilyID, time.Now().Add(u.jwt.RefreshTTL())); err != nil {
+		return "", "", err
+	}
+
+	access, err := u.jwt.GenerateAccess(user.ID, user.Role, claims.FamilyID, claims.Version)
+	if err != nil {
+		return "", "", err
+	}
+
+	return access, newRefresh, nil
+}
+
+// Logout отзывает семейство refresh-токена. Невалидный токен отзывать
+// нечего, это не ошибка.
+func (u *usecase) Logout(refreshToken string) error {
+	claims, err := u.jwt.ValidateRefresh(refreshToken)
+	if err != nil {
+		return nil
+	}
+	return revokeSession(u.redis, u.sessions, claims.FamilyID)
+}
+
+// revokeSession завершает сессию: удаляет её семейство refresh-токенов
+// из Redis и помечает отозванной в базе.
+func revokeSession(rc *redis.Client, sessions sessionDomain.SessionRepository, sessionID string) error {
+	if err := rc.Del(refreshFamilyKey(sessionID)); err != nil {
+		return err
+	}
+	return sessions.Revoke(sessionID)
+}
+
+// AccessValid проверяет, что пользователь не удалён, его роль не менялась
+// после выдачи access-токена, а сессия токена не завершена. Токены без
+// сессии (выпущенные до её появления) доживают свой короткий срок. У токена
+// входа от имени пользователя то же проверяется и для администратора, а без
+// сессии он недействителен.
+func (u *usecase) AccessValid(claims AccessClaims) (bool, error) {
+	current, err := u.versions.Current(claims.UserID, claims.Version)
+	if err != nil || !current {
+		return false, err
+	}
+	if claims.ActorID != "" {
+		if claims.SessionID == "" {
+			return false, nil
+		}
+		current, err := u.versions.Current(claims.ActorID, claims.ActorVersion)
+		if err != nil || !current {
+			return false, err
+		}
+	}
+
+	if claims.SessionID == "" {
+		return true, nil
+	}
+	jti, err := u.redis.Get(refreshFamilyKey(claims.SessionID))
+	if err != nil {
+		return false, err
+	}
+	return jti != "", nil
 }
 
 func (u *usecase) FindByEmail(email string) (userDomain.User, error) {
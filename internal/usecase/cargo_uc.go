@@ -1,44 +1,207 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"mime/multipart"
 	"strings"
 	cargoDomain "test-project/internal/domain/cargo"
+	counterpartyDomain "test-project/internal/domain/counterparty"
+	driverDomain "test-project/internal/domain/driver"
+	"test-project/internal/domain/file"
+	"test-project/internal/domain/user"
 	"test-project/internal/validator"
+	"time"
 )
 
 type CargoUsecase interface {
-	CreateCargo(input cargoDomain.Cargo) (cargoDomain.Cargo, error)
-	PatchCargo(input cargoDomain.UpdateCargoInput, id string) (cargoDomain.Cargo, error)
-	ListGargos() ([]cargoDomain.Cargo, error)
-	DeleteCargo(id string) error
-	GetCargo(id string) (cargoDomain.Cargo, error)
+	CreateCargo(input cargoDomain.Cargo, actorID string) (cargoDomain.Cargo, error)
+	PatchCargo(input cargoDomain.UpdateCargoInput, id string, actorID string, scope cargoDomain.Scope) (cargoDomain.Cargo, error)
+	ListGargos(filter cargoDomain.ListFilter) (cargoDomain.ListResult, error)
+	DeleteCargo(id string, actorID string) error
+	// GetCargo возвращает груз, видимый в scope.
+	GetCargo(id string, scope cargoDomain.Scope) (cargoDomain.Cargo, error)
+
+	AttachPhotos(ctx context.Context, id string, actorID string, files []*multipart.FileHeader) error
+	DeletePhotos(ctx context.Context, id string, actorID string, fileIDs []string) error
+
+	TransitionCargo(id string, input cargoDomain.TransitionRequest, userID string, role user.Role, scope cargoDomain.Scope) (cargoDomain.Cargo, error)
+	ListTransitions(id string, scope cargoDomain.Scope) ([]cargoDomain.StatusTransition, error)
+
+	History(id string, scope cargoDomain.Scope) ([]cargoDomain.Revision, error)
+	Revert(id, revisionID string, actorID string, scope cargoDomain.Scope) (cargoDomain.Cargo, error)
+
+	ListStops(id string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error)
+	AddStop(id string, input cargoDomain.AddStopRequest, actorID string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error)
+	ReorderStops(id string, input cargoDomain.ReorderStopsRequest, actorID string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error)
+	CompleteStop(id, stopID string, input cargoDomain.CompleteStopRequest, actorID string, scope cargoDomain.Scope) (cargoDomain.Stop, error)
+	DeleteStop(id, stopID string, actorID string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error)
 }
 
 type cargoUsecase struct {
-	repo      cargoDomain.CargoRepository
-	validator *validator.Validator
+	repo           cargoDomain.CargoRepository
+	drivers        driverDomain.DriverRepository
+	counterparties counterpartyDomain.CounterpartyRepository
+	files          *FileService
+	validator      *validator.Validator
+	conflictMode   cargoDomain.ConflictMode
+}
+
+func NewCargoUsecase(
+	r cargoDomain.CargoRepository,
+	drivers driverDomain.DriverRepository,
+	counterparties counterpartyDomain.CounterpartyRepository,
+	files *FileService,
+	v *validator.Validator,
+	conflictMode cargoDomain.ConflictMode,
+) CargoUsecase {
+	return &cargoUsecase{
+		repo:           r,
+		drivers:        drivers,
+		counterparties: counterparties,
+		files:          files,
+		validator:      v,
+		conflictMode:   conflictMode,
+	}
+}
+
+// checkSchedule проверяет плановое окно и возвращает проверку пересечений
+// с грузами той же машины, которую репозиторий выполнит в транзакции записи
+// вместе с проверкой вместимости.
+// В режиме reject пересечение — ошибка, в режиме warn пересечения попадают
+// в conflicts, чтобы показать их в ответе.
+func (u *cargoUsecase) checkSchedule(start, end *time.Time, conflicts *[]cargoDomain.ScheduleSlot) (cargoDomain.ScheduleCheck, error) {
+	if !cargoDomain.ValidWindow(start, end) {
+		return nil, cargoDomain.ErrScheduleWindow
+	}
+	if start == nil {
+		return nil, nil
+	}
+
+	return func(found []cargoDomain.ScheduleSlot) error {
+		if len(found) > 0 && u.conflictMode == cargoDomain.ConflictReject {
+			return &cargoDomain.ScheduleConflictError{Conflicts: found}
+		}
+		*conflicts = found
+		return nil
+	}, nil
 }
 
-func NewCargoUsecase(r cargoDomain.CargoRepository, v *validator.Validator) CargoUsecase {
-	return &cargoUsecase{repo: r, validator: v}
+// checkDriver проверяет, что водитель существует и не уволен.
+func (u *cargoUsecase) checkDriver(id *string) error {
+	if id == nil {
+		return nil
+	}
+
+	d, err := u.drivers.FindByID(*id)
+	if err != nil {
+		return err
+	}
+	if d.Status == driverDomain.StatusDismissed {
+		return driverDomain.ErrDismissed
+	}
+	return nil
 }
 
-func (u *cargoUsecase) CreateCargo(input cargoDomain.Cargo) (cargoDomain.Cargo, error) {
+// checkCounterparties проверяет, что указанные заказчик, грузоотправитель
+// и грузополучатель есть в справочнике.
+func (u *cargoUsecase) checkCounterparties(ids ...*string) error {
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+		if _, err := u.counterparties.FindByID(*id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revision собирает ревизию, если состояние груза действительно изменилось.
+// Снимком служит состояние после изменения, для удаления — до него.
+func revision(action cargoDomain.RevisionAction, cargoID, actorID string, before, after map[string]interface{}) *cargoDomain.Revision {
+	diff := cargoDomain.Diff(before, after)
+	if len(diff) == 0 {
+		return nil
+	}
+
+	snapshot := after
+	if action == cargoDomain.RevisionDelete {
+		snapshot = before
+	}
+
+	rev := cargoDomain.NewRevision(action, cargoID, actorID, diff, snapshot)
+	return &rev
+}
+
+// revise возвращает построитель ревизии, который репозиторий вызовет
+// в транзакции изменения груза.
+func revise(action cargoDomain.RevisionAction, actorID string, before map[string]interface{}) cargoDomain.Revise {
+	return func(after cargoDomain.Cargo) *cargoDomain.Revision {
+		return revision(action, after.ID, actorID, before, cargoDomain.Snapshot(after))
+	}
+}
+
+// reviseDelete строит ревизию удаления: снимок — состояние груза до удаления.
+func reviseDelete(actorID string) cargoDomain.Revise {
+	return func(deleted cargoDomain.Cargo) *cargoDomain.Revision {
+		return revision(cargoDomain.RevisionDelete, deleted.ID, actorID, cargoDomain.Snapshot(deleted), nil)
+	}
+}
+
+// revisePhotos строит ревизию добавления или удаления фотографий.
+// Снимок — состояние груза после изменения, чтобы к ревизии можно было откатиться.
+func revisePhotos(action cargoDomain.RevisionAction, actorID string, urls []string) cargoDomain.Revise {
+	return func(after cargoDomain.Cargo) *cargoDomain.Revision {
+		list := make([]interface{}, len(urls))
+		for i, url := range urls {
+			list[i] = url
+		}
+
+		change := cargoDomain.FieldChange{New: list}
+		if action == cargoDomain.RevisionPhotoDelete {
+			change = cargoDomain.FieldChange{Old: list}
+		}
+
+		diff := map[string]cargoDomain.FieldChange{"cargoPhotos": change}
+		rev := cargoDomain.NewRevision(action, after.ID, actorID, diff, cargoDomain.Snapshot(after))
+		return &rev
+	}
+}
+
+func (u *cargoUsecase) CreateCargo(input cargoDomain.Cargo, actorID string) (cargoDomain.Cargo, error) {
 	if errs := u.validator.Validate(input); len(errs) > 0 {
 		return cargoDomain.Cargo{}, errors.New(strings.Join(errs, "; "))
 	}
+	if err := u.checkDriver(input.DriverID); err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	if err := u.checkCounterparties(input.CustomerID, input.ShipperID, input.ConsigneeID); err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	var conflicts []cargoDomain.ScheduleSlot
+	schedule, err := u.checkSchedule(input.PlannedStart, input.PlannedEnd, &conflicts)
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	check := cargoDomain.TruckCheck{Capacity: true, Schedule: schedule}
+
+	created, err := u.repo.Create(input, check, revise(cargoDomain.RevisionCreate, actorID, nil))
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	created.ScheduleConflicts = conflicts
 
-	return u.repo.Create(input)
+	return created, nil
 }
 
-func (u *cargoUsecase) ListGargos() ([]cargoDomain.Cargo, error) {
-	return u.repo.FindAll()
+func (u *cargoUsecase) ListGargos(filter cargoDomain.ListFilter) (cargoDomain.ListResult, error) {
+	return u.repo.FindAll(filter)
 }
 
-func (u *cargoUsecase) GetCargo(id string) (cargoDomain.Cargo, error) {
-	cargo, err := u.repo.FindByID(id)
+func (u *cargoUsecase) GetCargo(id string, scope cargoDomain.Scope) (cargoDomain.Cargo, error) {
+	cargo, err := u.repo.FindVisible(id, scope)
 
 	if err != nil {
 		fmt.Println(err.Error())
@@ -47,16 +210,281 @@ func (u *cargoUsecase) GetCargo(id string) (cargoDomain.Cargo, error) {
 	return cargo, err
 }
 
-func (u *cargoUsecase) PatchCargo(input cargoDomain.UpdateCargoInput, id string) (cargoDomain.Cargo, error) {
-	return u.repo.Update(input, id)
+func (u *cargoUsecase) PatchCargo(input cargoDomain.UpdateCargoInput, id string, actorID string, scope cargoDomain.Scope) (cargoDomain.Cargo, error) {
+	if input.Status != nil {
+		return cargoDomain.Cargo{}, cargoDomain.ErrStatusNotPatchable
+	}
+	if err := u.checkDriver(input.DriverID); err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	if err := u.checkCounterparties(input.CustomerID, input.ShipperID, input.ConsigneeID); err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	before, err := u.repo.FindVisible(id, scope)
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	// вместимость проверяем, только если меняется машина, вес или объём,
+	// расписание — если меняется машина или плановое время
+	var (
+		conflicts []cargoDomain.ScheduleSlot
+		check     cargoDomain.TruckCheck
+	)
+	check.Capacity = input.TruckID != nil || input.WeightKg != nil || input.VolumeM3 != nil
+	if input.TruckID != nil || input.PlannedStart != nil || input.PlannedEnd != nil {
+		start, end := before.PlannedStart, before.PlannedEnd
+		if input.PlannedStart != nil {
+			start = input.PlannedStart
+		}
+		if input.PlannedEnd != nil {
+			end = input.PlannedEnd
+		}
+
+		if check.Schedule, err = u.checkSchedule(start, end, &conflicts); err != nil {
+			return cargoDomain.Cargo{}, err
+		}
+	}
+
+	after, err := u.repo.Update(input, id, check, revise(cargoDomain.RevisionPatch, actorID, cargoDomain.Snapshot(before)))
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	after.ScheduleConflicts = conflicts
+	return after, nil
+}
+
+func (u *cargoUsecase) DeleteCargo(id string, actorID string) error {
+	return u.repo.Delete(id, actorID, reviseDelete(actorID))
+}
+
+func (u *cargoUsecase) AttachPhotos(ctx context.Context, id string, actorID string, files []*multipart.FileHeader) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	stored, err := u.files.Store(ctx, "cargos", id, file.KindPhoto, files)
+
+	// фиксируем то, что успело загрузиться, даже если дальше произошла ошибка
+	if len(stored) > 0 {
+		urls := make([]string, len(stored))
+		for i, rec := range stored {
+			urls[i] = rec.URL
+		}
+		if addErr := u.repo.AddPhotos(id, stored, revisePhotos(cargoDomain.RevisionPhotoAdd, actorID, urls)); addErr != nil {
+			u.files.Discard(ctx, stored)
+			return addErr
+		}
+	}
+
+	return err
 }
 
-func (u *cargoUsecase) DeleteCargo(id string) error {
-	_, err := u.repo.FindByID(id)
+func (u *cargoUsecase) DeletePhotos(ctx context.Context, id string, actorID string, fileIDs []string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+
+	// удаляем только файлы, которые действительно принадлежат грузу
+	owned, err := u.files.ListByOwner(ctx, "cargos", id)
+	if err != nil {
+		return err
+	}
+
+	requested := make(map[string]bool, len(fileIDs))
+	for _, fid := range fileIDs {
+		requested[fid] = true
+	}
+
+	var (
+		ids  []string
+		urls []string
+	)
+	for _, rec := range owned {
+		if requested[rec.ID] {
+			ids = append(ids, rec.ID)
+			urls = append(urls, rec.URL)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
 
+	deleted, err := u.repo.DeletePhotos(id, ids, revisePhotos(cargoDomain.RevisionPhotoDelete, actorID, urls))
 	if err != nil {
 		return err
 	}
 
-	return u.repo.Delete(id)
+	u.files.Discard(ctx, deleted)
+	return nil
+}
+
+func (u *cargoUsecase) TransitionCargo(id string, input cargoDomain.TransitionRequest, userID string, role user.Role, scope cargoDomain.Scope) (cargoDomain.Cargo, error) {
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return cargoDomain.Cargo{}, errors.New(strings.Join(errs, "; "))
+	}
+
+	cargo, err := u.repo.FindVisible(id, scope)
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	if err := cargoDomain.CanTransition(cargo.Status, input.To, role); err != nil {
+		return cargoDomain.Cargo{}, fmt.Errorf("%w: %s → %s", err, cargo.Status, input.To)
+	}
+
+	rv := revise(cargoDomain.RevisionTransition, userID, cargoDomain.Snapshot(cargo))
+	if _, err := u.repo.ChangeStatus(id, cargo.Status, input.To, userID, input.Comment, rv); err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	return u.repo.FindByID(id)
+}
+
+func (u *cargoUsecase) ListTransitions(id string, scope cargoDomain.Scope) ([]cargoDomain.StatusTransition, error) {
+	if _, err := u.repo.FindVisible(id, scope); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindTransitions(id)
+}
+
+func (u *cargoUsecase) History(id string, scope cargoDomain.Scope) ([]cargoDomain.Revision, error) {
+	return u.repo.FindRevisions(id, scope)
+}
+
+// Revert восстанавливает поля груза из снимка ревизии. Статус не
+// откатывается — он меняется только через переходы жизненного цикла.
+func (u *cargoUsecase) Revert(id, revisionID string, actorID string, scope cargoDomain.Scope) (cargoDomain.Cargo, error) {
+	before, err := u.repo.FindVisible(id, scope)
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	rev, err := u.repo.FindRevision(id, revisionID)
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+
+	target, err := rev.Restore()
+	if err != nil {
+		return cargoDomain.Cargo{}, fmt.Errorf("decode revision snapshot: %w", err)
+	}
+	if errs := u.validator.Validate(target); len(errs) > 0 {
+		return cargoDomain.Cargo{}, cargoDomain.ErrRevisionNotRevertable
+	}
+	var conflicts []cargoDomain.ScheduleSlot
+	schedule, err := u.checkSchedule(target.PlannedStart, target.PlannedEnd, &conflicts)
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	check := cargoDomain.TruckCheck{Capacity: true, Schedule: schedule}
+
+	after, err := u.repo.Replace(id, target, check, revise(cargoDomain.RevisionRevert, actorID, cargoDomain.Snapshot(before)))
+	if err != nil {
+		return cargoDomain.Cargo{}, err
+	}
+	after.ScheduleConflicts = conflicts
+
+	return after, nil
+}
+
+func (u *cargoUsecase) ListStops(id string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error) {
+	if _, err := u.repo.FindVisible(id, scope); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindStops(id)
+}
+
+// changeStops выполняет изменение маршрута груза. Ревизия — список остановок
+// до и после, снимок — состояние груза после изменения — пишется
+// репозиторием в той же транзакции.
+func (u *cargoUsecase) changeStops(id, actorID string, scope cargoDomain.Scope, change func(rv cargoDomain.Revise) error) ([]cargoDomain.Stop, error) {
+	cargo, err := u.repo.FindVisible(id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := func(after cargoDomain.Cargo) *cargoDomain.Revision {
+		diff := map[string]cargoDomain.FieldChange{"stops": {Old: cargo.Stops, New: after.Stops}}
+		rev := cargoDomain.NewRevision(cargoDomain.RevisionStops, id, actorID, diff, cargoDomain.Snapshot(after))
+		return &rev
+	}
+	if err := change(rv); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindStops(id)
+}
+
+func (u *cargoUsecase) AddStop(id string, input cargoDomain.AddStopRequest, actorID string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error) {
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+	if input.PlannedFrom != nil && input.PlannedTo != nil && input.PlannedTo.Before(*input.PlannedFrom) {
+		return nil, cargoDomain.ErrStopWindow
+	}
+
+	stop := cargoDomain.Stop{
+		Type:        input.Type,
+		Address:     strings.TrimSpace(input.Address),
+		Lat:         input.Lat,
+		Lng:         input.Lng,
+		PlannedFrom: input.PlannedFrom,
+		PlannedTo:   input.PlannedTo,
+	}
+	if input.Position != nil {
+		stop.Position = *input.Position
+	}
+
+	return u.changeStops(id, actorID, scope, func(rv cargoDomain.Revise) error {
+		_, err := u.repo.AddStop(id, stop, rv)
+		return err
+	})
+}
+
+func (u *cargoUsecase) ReorderStops(id string, input cargoDomain.ReorderStopsRequest, actorID string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error) {
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+
+	return u.changeStops(id, actorID, scope, func(rv cargoDomain.Revise) error {
+		_, err := u.repo.ReorderStops(id, input.StopIDs, rv)
+		return err
+	})
+}
+
+func (u *cargoUsecase) CompleteStop(id, stopID string, input cargoDomain.CompleteStopRequest, actorID string, scope cargoDomain.Scope) (cargoDomain.Stop, error) {
+	now := time.Now()
+
+	arrivedAt, departedAt := now, now
+	if input.ArrivedAt != nil {
+		arrivedAt = *input.ArrivedAt
+	}
+	if input.DepartedAt != nil {
+		departedAt = *input.DepartedAt
+	}
+	if departedAt.Before(arrivedAt) {
+		return cargoDomain.Stop{}, cargoDomain.ErrStopTimes
+	}
+
+	var completed cargoDomain.Stop
+	_, err := u.changeStops(id, actorID, scope, func(rv cargoDomain.Revise) error {
+		var err error
+		completed, err = u.repo.CompleteStop(id, stopID, arrivedAt, departedAt, rv)
+		return err
+	})
+	if err != nil {
+		return cargoDomain.Stop{}, err
+	}
+	return completed, nil
+}
+
+func (u *cargoUsecase) DeleteStop(id, stopID string, actorID string, scope cargoDomain.Scope) ([]cargoDomain.Stop, error) {
+	return u.changeStops(id, actorID, scope, func(rv cargoDomain.Revise) error {
+		return u.repo.DeleteStop(id, stopID, rv)
+	})
 }
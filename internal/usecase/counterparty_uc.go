@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	counterpartyDomain "test-project/internal/domain/counterparty"
+	"test-project/internal/validator"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	defaultCounterpartyLimit = 50
+	maxCounterpartyLimit     = 200
+)
+
+type CounterpartyUsecase interface {
+	CreateCounterparty(input counterpartyDomain.Counterparty) (counterpartyDomain.Counterparty, error)
+	SearchCounterparties(query string, limit int) ([]counterpartyDomain.Counterparty, error)
+	GetCounterparty(id string) (counterpartyDomain.Counterparty, error)
+	UpdateCounterparty(id string, input counterpartyDomain.UpdateCounterpartyInput) (counterpartyDomain.Counterparty, error)
+	DeleteCounterparty(id string) error
+}
+
+type counterpartyUsecase struct {
+	repo      counterpartyDomain.CounterpartyRepository
+	validator *validator.Validator
+}
+
+func NewCounterpartyUsecase(r counterpartyDomain.CounterpartyRepository, v *validator.Validator) CounterpartyUsecase {
+	return &counterpartyUsecase{repo: r, validator: v}
+}
+
+func normalizeRequisites(inn, kpp, ogrn *string) {
+	for _, s := range []*string{inn, kpp, ogrn} {
+		if s != nil {
+			*s = strings.ToUpper(strings.TrimSpace(*s))
+		}
+	}
+}
+
+// duplicateErr заменяет нарушение уникальности ИНН/КПП на ErrDuplicate.
+func duplicateErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return counterpartyDomain.ErrDuplicate
+	}
+	return err
+}
+
+func (u *counterpartyUsecase) CreateCounterparty(input counterpartyDomain.Counterparty) (counterpartyDomain.Counterparty, error) {
+	normalizeRequisites(&input.INN, input.KPP, input.OGRN)
+
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return counterpartyDomain.Counterparty{}, errors.New(strings.Join(errs, "; "))
+	}
+
+	created, err := u.repo.Create(input)
+	if err != nil {
+		return counterpartyDomain.Counterparty{}, duplicateErr(err)
+	}
+	return created, nil
+}
+
+func (u *counterpartyUsecase) SearchCounterparties(query string, limit int) ([]counterpartyDomain.Counterparty, error) {
+	if limit <= 0 {
+		limit = defaultCounterpartyLimit
+	}
+	if limit > maxCounterpartyLimit {
+		limit = maxCounterpartyLimit
+	}
+
+	return u.repo.Search(strings.TrimSpace(query), limit)
+}
+
+func (u *counterpartyUsecase) GetCounterparty(id string) (counterpartyDomain.Counterparty, error) {
+	return u.repo.FindByID(id)
+}
+
+func (u *counterpartyUsecase) UpdateCounterparty(id string, input counterpartyDomain.UpdateCounterpartyInput) (counterpartyDomain.Counterparty, error) {
+	normalizeRequisites(input.INN, input.KPP, input.OGRN)
+
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return counterpartyDomain.Counterparty{}, errors.New(strings.Join(errs, "; "))
+	}
+
+	updated, err := u.repo.Update(id, input)
+	if err != nil {
+		return counterpartyDomain.Counterparty{}, duplicateErr(err)
+	}
+	return updated, nil
+}
+
+// DeleteCounterparty удаляет контрагента, который не указан ни в одном грузе.
+func (u *counterpartyUsecase) DeleteCounterparty(id string) error {
+	if _, err := u.repo.FindByID(id); err != nil {
+		return err
+	}
+
+	inUse, err := u.repo.InUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return counterpartyDomain.ErrInUse
+	}
+
+	return u.repo.Delete(id)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	driverDomain "test-project/internal/domain/driver"
+	"test-project/internal/validator"
+)
+
+type DriverUsecase interface {
+	CreateDriver(input driverDomain.Driver) (driverDomain.Driver, error)
+	ListDrivers(filter driverDomain.ListFilter) ([]driverDomain.Driver, error)
+	GetDriver(id string) (driverDomain.Driver, error)
+	UpdateDriver(id string, input driverDomain.UpdateDriverInput) (driverDomain.Driver, error)
+	DeleteDriver(id string) error
+}
+
+type driverUsecase struct {
+	repo      driverDomain.DriverRepository
+	validator *validator.Validator
+}
+
+func NewDriverUsecase(r driverDomain.DriverRepository, v *validator.Validator) DriverUsecase {
+	return &driverUsecase{repo: r, validator: v}
+}
+
+func normalizeDriverContacts(fullName, phone, license *string) {
+	if fullName != nil {
+		*fullName = strings.Join(strings.Fields(*fullName), " ")
+	}
+	if phone != nil {
+		*phone = validator.NormalizePhone(*phone)
+	}
+	if license != nil {
+		*license = validator.NormalizeLicense(*license)
+	}
+}
+
+func (u *driverUsecase) CreateDriver(input driverDomain.Driver) (driverDomain.Driver, error) {
+	if input.Status == "" {
+		input.Status = driverDomain.StatusActive
+	}
+	normalizeDriverContacts(&input.FullName, input.Phone, input.LicenseNumber)
+
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return driverDomain.Driver{}, errors.New(strings.Join(errs, "; "))
+	}
+
+	return u.repo.Create(input)
+}
+
+func (u *driverUsecase) ListDrivers(filter driverDomain.ListFilter) ([]driverDomain.Driver, error) {
+	return u.repo.FindAll(filter)
+}
+
+func (u *driverUsecase) GetDriver(id string) (driverDomain.Driver, error) {
+	return u.repo.FindByID(id)
+}
+
+func (u *driverUsecase) UpdateDriver(id string, input driverDomain.UpdateDriverInput) (driverDomain.Driver, error) {
+	normalizeDriverContacts(input.FullName, input.Phone, input.LicenseNumber)
+
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return driverDomain.Driver{}, errors.New(strings.Join(errs, "; "))
+	}
+
+	return u.repo.Update(id, input)
+}
+
+// DeleteDriver удаляет водителя без грузов. Водителя с историей перевозок
+// удалить нельзя — его переводят в статус dismissed.
+func (u *driverUsecase) DeleteDriver(id string) error {
+	if _, err := u.repo.FindByID(id); err != nil {
+		return err
+	}
+
+	hasCargos, err := u.repo.HasCargos(id)
+	if err != nil {
+		return err
+	}
+	if hasCargos {
+		return driverDomain.ErrHasCargos
+	}
+
+	return u.repo.Delete(id)
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"mime/multipart"
+	"test-project/internal/domain/cargo"
 	"test-project/internal/domain/file"
 
 	"github.com/google/uuid"
@@ -21,28 +22,82 @@ func (s *FileService) UploadMany(
 	ctx context.Context,
 	ownerTable, ownerID string,
 	fhs []*multipart.FileHeader,
-) error {
+) ([]file.Record, error) {
+	return s.UploadKind(ctx, ownerTable, ownerID, file.KindPhoto, fhs)
+}
+
+// UploadKind сохраняет файлы с указанным видом вложения (фото, документ).
+func (s *FileService) UploadKind(
+	ctx context.Context,
+	ownerTable, ownerID, kind string,
+	fhs []*multipart.FileHeader,
+) ([]file.Record, error) {
+	stored, err := s.Store(ctx, ownerTable, ownerID, kind, fhs)
+
+	var saved []file.Record
+	for i, rec := range stored {
+		if cerr := s.repo.Create(ctx, rec); cerr != nil {
+			s.Discard(ctx, stored[i:])
+			return saved, cerr
+		}
+		saved = append(saved, rec)
+	}
+	return saved, err
+}
+
+// Store кладёт файлы в хранилище, но не пишет записи о них: это делает
+// вызывающий, например в одной транзакции с ревизией груза. При ошибке
+// возвращается то, что успело сохраниться.
+func (s *FileService) Store(
+	ctx context.Context,
+	ownerTable, ownerID, kind string,
+	fhs []*multipart.FileHeader,
+) ([]file.Record, error) {
+	var stored []file.Record
+
 	for _, fh := range fhs {
-		src, _ := fh.Open()
+		src, err := fh.Open()
+		if err != nil {
+			return stored, err
+		}
 		defer src.Close()
 
 		meta, err := s.st.Save(ctx, fh.Filename, src)
 		if err != nil {
-			return err
+			return stored, err
 		}
 
-		rec := file.Record{
+		stored = append(stored, file.Record{
 			ID:         uuid.NewString(),
 			OwnerID:    ownerID,
 			OwnerTable: ownerTable,
 			URL:        meta.URL,
-		}
-		if err := s.repo.Create(ctx, rec); err != nil {
-			_ = s.st.Delete(ctx, meta.URL)
-			return err
-		}
+			Kind:       kind,
+		})
 	}
-	return nil
+	return stored, nil
+}
+
+// Discard удаляет файлы из хранилища, записи о них не трогает.
+func (s *FileService) Discard(ctx context.Context, recs []file.Record) {
+	for _, r := range recs {
+		_ = s.st.Delete(ctx, r.URL)
+	}
+}
+
+func (s *FileService) ListByOwner(ctx context.Context, ownerTable, ownerID string) ([]file.Record, error) {
+	return s.repo.GetByOwner(ctx, ownerTable, ownerID)
+}
+
+// Find ищет файл по URL. Фото груза, который не виден в scope, не находится.
+func (s *FileService) Find(ctx context.Context, url string, scope cargo.Scope) (file.Record, error) {
+	if scope.All {
+		return s.repo.FindVisible(ctx, url, "")
+	}
+	if scope.UserID == "" {
+		return file.Record{}, file.ErrNotFound
+	}
+	return s.repo.FindVisible(ctx, url, scope.UserID)
 }
 
 func (s *FileService) DeleteMany(ctx context.Context, ids []string) error {
@@ -51,8 +106,6 @@ func (s *FileService) DeleteMany(ctx context.Context, ids []string) error {
 		return err
 	}
 
-	for _, r := range recs {
-		_ = s.st.Delete(ctx, r.URL)
-	}
+	s.Discard(ctx, recs)
 	return nil
 }
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"strings"
+	impersonationDomain "test-project/internal/domain/impersonation"
+	userDomain "test-project/internal/domain/user"
+	"test-project/internal/redis"
+	"time"
+)
+
+type ImpersonationUsecase interface {
+	// Start записывает вход actorID от имени userID в журнал и выдаёт
+	// access-токен пользователя с пометкой act. Refresh-токена нет: по
+	// истечении ttl вход нужно начать заново.
+	Start(actorID, sessionID, userID string, input impersonationDomain.StartRequest, userAgent, ip string) (impersonationDomain.Started, error)
+	List(userID string) ([]impersonationDomain.Impersonation, error)
+	Get(id string) (impersonationDomain.Impersonation, error)
+	// Record добавляет запрос в журнал входа.
+	Record(impersonationID string, req impersonationDomain.Request) error
+}
+
+type impersonationUsecase struct {
+	repo     impersonationDomain.Repository
+	users    userDomain.UserRepository
+	versions tokenVersions
+	jwt      *JwtUsecase
+	ttl      time.Duration
+}
+
+func NewImpersonationUsecase(repo impersonationDomain.Repository, users userDomain.UserRepository, jwt *JwtUsecase, rc *redis.Client, ttl time.Duration) ImpersonationUsecase {
+	return &impersonationUsecase{
+		repo:     repo,
+		users:    users,
+		versions: tokenVersions{repo: users, redis: rc},
+		jwt:      jwt,
+		ttl:      ttl,
+	}
+}
+
+func (u *impersonationUsecase) Start(actorID, sessionID, userID string, input impersonationDomain.StartRequest, userAgent, ip string) (impersonationDomain.Started, error) {
+	if actorID == userID {
+		return impersonationDomain.Started{}, impersonationDomain.ErrSelf
+	}
+	target, err := u.users.FindByID(userID)
+	if err != nil {
+		return impersonationDomain.Started{}, impersonationDomain.ErrUser
+	}
+	// от имени другого суперадминистратора ничего не проверить, зато его
+	// действия в журналах перепутались бы с чужими
+	if target.Role == userDomain.RoleSuperAdmin {
+		return impersonationDomain.Started{}, impersonationDomain.ErrSuperAdmin
+	}
+	// без сессии токен нельзя было бы отозвать, завершив сессию администратора
+	if sessionID == "" {
+		return impersonationDomain.Started{}, impersonationDomain.ErrNoSession
+	}
+	actor, err := u.users.FindByID(actorID)
+	if err != nil {
+		return impersonationDomain.Started{}, err
+	}
+	version, err := u.versions.Get(target.ID)
+	if err != nil {
+		return impersonationDomain.Started{}, err
+	}
+	actorVersion, err := u.versions.Get(actor.ID)
+	if err != nil {
+		return impersonationDomain.Started{}, err
+	}
+
+	imp, err := u.repo.Create(impersonationDomain.Impersonation{
+		ActorID:   actorID,
+		UserID:    target.ID,
+		Reason:    strings.TrimSpace(input.Reason),
+		IP:        ip,
+		UserAgent: userAgent,
+		ExpiresAt: time.Now().Add(u.ttl),
+	})
+	if err != nil {
+		return impersonationDomain.Started{}, err
+	}
+
+	token, err := u.jwt.GenerateImpersonation(AccessClaims{
+		UserID:          target.ID,
+		Role:            target.Role,
+		SessionID:       sessionID,
+		Version:         version,
+		ActorID:         actor.ID,
+		ActorRole:       actor.Role,
+		ActorVersion:    actorVersion,
+		ImpersonationID: imp.ID,
+	}, u.ttl)
+	if err != nil {
+		return impersonationDomain.Started{}, err
+	}
+	return impersonationDomain.Started{AccessToken: token, Impersonation: imp}, nil
+}
+
+func (u *impersonationUsecase) List(userID string) ([]impersonationDomain.Impersonation, error) {
+	return u.repo.FindAll(userID)
+}
+
+func (u *impersonationUsecase) Get(id string) (impersonationDomain.Impersonation, error) {
+	return u.repo.FindByID(id)
+}
+
+func (u *impersonationUsecase) Record(impersonationID string, req impersonationDomain.Request) error {
+	return u.repo.AddRequest(impersonationID, req)
+}
@@ -1,41 +1,66 @@
 package usecase
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 	invitationDomain "test-project/internal/domain/invitation"
+	userDomain "test-project/internal/domain/user"
 	"test-project/internal/validator"
-
-	"github.com/jackc/pgx/v5/pgconn"
+	"time"
 )
 
 type InvitationUsecase interface {
 	CreateInvitation(invitation invitationDomain.Invitation) (invitationDomain.Invitation, error)
+	ListInvitations(status *invitationDomain.Status) ([]invitationDomain.Invitation, error)
+	GetInvitation(id string) (invitationDomain.Invitation, error)
+	// ResendInvitation продлевает неиспользованное приглашение с новым токеном.
+	ResendInvitation(id, token string) (invitationDomain.Invitation, error)
+	RevokeInvitation(id string) error
 }
 
 type invitationUsecase struct {
 	repo      invitationDomain.InvitationRepository
 	validator *validator.Validator
+	ttl       time.Duration
 }
 
-func NewInvitationService(r invitationDomain.InvitationRepository, validator *validator.Validator) InvitationUsecase {
-	return &invitationUsecase{repo: r, validator: validator}
+// ttl — время жизни приглашения, то же, что у токена в ссылке.
+func NewInvitationService(r invitationDomain.InvitationRepository, validator *validator.Validator, ttl time.Duration) InvitationUsecase {
+	return &invitationUsecase{repo: r, validator: validator, ttl: ttl}
 }
 
 func (u *invitationUsecase) CreateInvitation(invitation invitationDomain.Invitation) (invitationDomain.Invitation, error) {
+	if invitation.Role == "" {
+		invitation.Role = userDomain.RoleUser
+	}
+
 	if errs := u.validator.Validate(invitation); len(errs) > 0 {
-		return invitationDomain.Invitation{}, errors.New(strings.Join(errs, "; "))
+		return invitationDomain.Invitation{}, fmt.Errorf("%w: %s", invitationDomain.ErrValidation, strings.Join(errs, "; "))
 	}
 
-	invitation, err := u.repo.Create(invitation)
-	if err != nil {
-		// обработка ошибки дубликата
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return invitationDomain.Invitation{}, errors.New("Приглашение для этого email уже отправлено")
+	invitation.ExpiresAt = time.Now().Add(u.ttl)
+	return u.repo.Create(invitation)
+}
+
+func (u *invitationUsecase) ListInvitations(status *invitationDomain.Status) ([]invitationDomain.Invitation, error) {
+	if status != nil {
+		switch *status {
+		case invitationDomain.StatusPending, invitationDomain.StatusUsed, invitationDomain.StatusExpired:
+		default:
+			return nil, invitationDomain.ErrInvalidStatus
 		}
-		return invitationDomain.Invitation{}, err
 	}
+	return u.repo.FindAll(status)
+}
+
+func (u *invitationUsecase) GetInvitation(id string) (invitationDomain.Invitation, error) {
+	return u.repo.FindByID(id)
+}
+
+func (u *invitationUsecase) ResendInvitation(id, token string) (invitationDomain.Invitation, error) {
+	return u.repo.Renew(id, token, time.Now().Add(u.ttl))
+}
 
-	return invitation, nil
+func (u *invitationUsecase) RevokeInvitation(id string) error {
+	return u.repo.Delete(id)
 }
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Типы токенов. У каждого типа свои ключи, поэтому токен одного типа
+// нельзя выдать за другой, даже если совпадут claims.
+const (
+	TokenAccess  = "access"
+	TokenRefresh = "refresh"
+	TokenInvite  = "invite"
+	TokenMFA     = "mfa"
+	TokenMagic   = "magic"
+)
+
+var tokenTypes = []string{TokenAccess, TokenRefresh, TokenInvite, TokenMFA, TokenMagic}
+
+// Keyring — ключи Ed25519 одного типа токенов. Подписывает один ключ, а
+// проверяются токены всеми: так ключ можно сменить, не разлогинив
+// пользователей.
+type Keyring struct {
+	kid     string
+	signer  ed25519.PrivateKey
+	publics map[string]ed25519.PublicKey
+	// legacy — прежний общий секрет HS256. Пока он задан, принимаются
+	// токены, выпущенные до перехода на ключи.
+	legacy []byte
+}
+
+// JWTKeys — ключи всех типов токенов.
+type JWTKeys map[string]*Keyring
+
+// LoadJWTKeys читает ключи из dir/<тип>/<kid>.pem. Файл с закрытым ключом
+// (PKCS#8) подписывает и проверяет, с открытым (PKIX) — только проверяет.
+// Подписывает закрытый ключ с наибольшим kid, поэтому ключи удобно называть
+// датой выпуска: 2026-10-18.pem. Для ротации новый ключ кладут рядом со
+// старым, а старый после истечения его токенов заменяют открытым ключом
+// или удаляют.
+//
+// Если у типа нет закрытого ключа и generate разрешён, ключ создаётся и
+// сохраняется в каталог — для локальной разработки.
+func LoadJWTKeys(dir string, generate bool, legacy map[string]string) (JWTKeys, error) {
+	keys := JWTKeys{}
+	for _, typ := range tokenTypes {
+		ring, err := loadKeyring(filepath.Join(dir, typ), generate)
+		if err != nil {
+			return nil, fmt.Errorf("ключи токенов %s: %w", typ, err)
+		}
+		if secret := legacy[typ]; secret != "" {
+			ring.legacy = []byte(secret)
+		}
+		keys[typ] = ring
+	}
+	return keys, nil
+}
+
+func loadKeyring(dir string, generate bool) (*Keyring, error) {
+	ring := &Keyring{publics: map[string]ed25519.PublicKey{}}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		kid := strings.TrimSuffix(filepath.Base(name), ".pem")
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%s: не PEM", name)
+		}
+
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			priv, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("%s: нужен ключ Ed25519", name)
+			}
+			ring.publics[kid] = priv.Public().(ed25519.PublicKey)
+			// файлы отсортированы, последний закрытый ключ — с наибольшим kid
+			ring.kid, ring.signer = kid, priv
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			pub, ok := key.(ed25519.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("%s: нужен ключ Ed25519", name)
+			}
+			ring.publics[kid] = pub
+		default:
+			return nil, fmt.Errorf("%s: неизвестный тип PEM %q", name, block.Type)
+		}
+	}
+
+	if ring.signer != nil {
+		return ring, nil
+	}
+	if !generate {
+		return nil, fmt.Errorf("в %s нет закрытого ключа", dir)
+	}
+	if err := ring.generate(dir); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// generate создаёт ключ подписи и сохраняет его в dir.
+func (k *Keyring) generate(dir string) error {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+
+	kid := time.Now().UTC().Format("2006-01-02T150405")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		return err
+	}
+
+	k.kid, k.signer = kid, priv
+	k.publics[kid] = pub
+	return nil
+}
+
+func (k *Keyring) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.kid
+	return token.SignedString(k.signer)
+}
+
+func (k *Keyring) parse(tokenStr string) (*jwt.Token, error) {
+	methods := []string{jwt.SigningMethodEdDSA.Alg()}
+	if k.legacy != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	return jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		if t.Method == jwt.SigningMethodHS256 {
+			return k.legacy, nil
+		}
+		kid, _ := t.Header["kid"].(string)
+		pub, ok := k.publics[kid]
+		if !ok {
+			return nil, errors.New("unknown key id")
+		}
+		return pub, nil
+	}, jwt.WithValidMethods(methods))
+}
+
+// JWK — открытый ключ в формате RFC 8037.
+type JWK struct {
+	Kty string `json:"kty" example:"OKP"`
+	Crv string `json:"crv" example:"Ed25519"`
+	X   string `json:"x"`
+	Kid string `json:"kid" example:"2026-10-18"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" example:"EdDSA"`
+}
+
+// JWKS возвращает открытые ключи типа в порядке kid.
+func (k *Keyring) JWKS() []JWK {
+	kids := make([]string, 0, len(k.publics))
+	for kid := range k.publics {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	list := make([]JWK, len(kids))
+	for i, kid := range kids {
+		list[i] = JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k.publics[kid]),
+			Kid: kid,
+			Use: "sig",
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+		}
+	}
+	return list
+}
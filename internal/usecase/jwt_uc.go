@@ -6,128 +6,288 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type JwtUsecase struct {
-	secretAccess  []byte
-	secretRefresh []byte
-	accessExp     time.Duration
-	refreshExp    time.Duration
+	keys       JWTKeys
+	accessExp  time.Duration
+	refreshExp time.Duration
 }
 
-func NewJWTService(secretAccess, secretRefresh string, accessExp, refreshExp time.Duration) *JwtUsecase {
+func NewJWTService(keys JWTKeys, accessExp, refreshExp time.Duration) *JwtUsecase {
 	return &JwtUsecase{
-		secretAccess:  []byte(secretAccess),
-		secretRefresh: []byte(secretRefresh),
-		accessExp:     accessExp,
-		refreshExp:    refreshExp,
+		keys:       keys,
+		accessExp:  accessExp,
+		refreshExp: refreshExp,
 	}
 }
 
+// AccessJWKS возвращает открытые ключи access-токенов, чтобы другие
+// сервисы могли проверять их без обращения к нам.
+func (j *JwtUsecase) AccessJWKS() []JWK {
+	return j.keys[TokenAccess].JWKS()
+}
+
+// AccessClaims — содержимое access-токена. SessionID (sid) — сессия,
+// при входе в которую выдан токен, Version (ver) — версия токенов пользователя.
+// У токена входа от имени пользователя ActorID (act.sub) — суперадминистратор,
+// ActorRole и ActorVersion (act.role, act.ver) — его роль и версия токенов
+// при выдаче, а ImpersonationID (jti) — запись журнала этого входа.
+type AccessClaims struct {
+	UserID          string
+	Role            user.Role
+	SessionID       string
+	Version         int
+	ActorID         string
+	ActorRole       user.Role
+	ActorVersion    int
+	ImpersonationID string
+}
+
 // Генерация Access Token
-func (j *JwtUsecase) GenerateAccess(userID string, role user.Role) (string, error) {
+func (j *JwtUsecase) GenerateAccess(userID string, role user.Role, sessionID string, version int) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":  userID,
 		"role": role,
+		"sid":  sessionID,
+		"ver":  version,
 		"exp":  time.Now().Add(j.accessExp).Unix(),
 		"type": "access",
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretAccess)
+	return j.keys[TokenAccess].sign(claims)
 }
 
-// Генерация Refresh Token
-func (j *JwtUsecase) GenerateRefresh(userID string, role user.Role) (string, error) {
+// Генерация Access Token для входа от имени пользователя (RFC 8693, claim
+// act). SessionID — сессия суперадминистратора: с её завершением токен
+// перестаёт действовать.
+func (j *JwtUsecase) GenerateImpersonation(c AccessClaims, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":  c.UserID,
+		"role": c.Role,
+		"sid":  c.SessionID,
+		"ver":  c.Version,
+		"jti":  c.ImpersonationID,
+		"act": map[string]interface{}{
+			"sub":  c.ActorID,
+			"role": c.ActorRole,
+			"ver":  c.ActorVersion,
+		},
+		"exp":  time.Now().Add(ttl).Unix(),
+		"type": "access",
+	}
+	return j.keys[TokenAccess].sign(claims)
+}
+
+// RefreshClaims — содержимое refresh-токена. ID (jti) меняется при каждом
+// обновлении, FamilyID остаётся общим для всей цепочки токенов одного входа.
+type RefreshClaims struct {
+	UserID   string
+	Role     user.Role
+	ID       string
+	FamilyID string
+	Version  int
+}
+
+func (j *JwtUsecase) RefreshTTL() time.Duration {
+	return j.refreshExp
+}
+
+// Генерация Refresh Token. Возвращает токен и его jti.
+func (j *JwtUsecase) GenerateRefresh(userID string, role user.Role, familyID string, version int) (string, string, error) {
+	jti := uuid.NewString()
 	claims := jwt.MapClaims{
 		"sub":  userID,
 		"role": role,
+		"jti":  jti,
+		"fam":  familyID,
+		"ver":  version,
 		"exp":  time.Now().Add(j.refreshExp).Unix(),
 		"type": "refresh",
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretRefresh)
+	signed, err := j.keys[TokenRefresh].sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
 }
 
 // Генерация Invite Token
-func (j *JwtUsecase) GenerateInvite(email string) (string, error) {
+func (j *JwtUsecase) GenerateInvite(email string, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"email": email,
-		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"exp":   time.Now().Add(ttl).Unix(),
 		"type":  "invite",
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretAccess) // Можно использовать тот же secretAccess
+	return j.keys[TokenInvite].sign(claims)
+}
+
+// Назначение MFA-токена: подтвердить вход кодом или сначала подключить 2FA.
+const (
+	MFAPurposeVerify = "verify"
+	MFAPurposeSetup  = "setup"
+)
+
+// mfaTokenTTL — сколько есть времени ввести код после пароля.
+const mfaTokenTTL = 5 * time.Minute
+
+// Генерация MFA Token — промежуточный токен между паролем и вторым фактором.
+func (j *JwtUsecase) GenerateMFA(userID, purpose string) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":     userID,
+		"purpose": purpose,
+		"exp":     time.Now().Add(mfaTokenTTL).Unix(),
+		"type":    "mfa",
+	}
+	return j.keys[TokenMFA].sign(claims)
+}
+
+// Валидация MFA Token. Возвращает пользователя, если назначение совпадает.
+func (j *JwtUsecase) ValidateMFA(tokenStr, purpose string) (string, error) {
+	t, err := j.keys[TokenMFA].parse(tokenStr)
+	if err != nil || !t.Valid {
+		return "", errors.New("invalid token")
+	}
+
+	claims := t.Claims.(jwt.MapClaims)
+	if claims["type"] != "mfa" || claims["purpose"] != purpose {
+		return "", errors.New("invalid token type")
+	}
+
+	sub, _ := claims["sub"].(string)
+	return sub, nil
+}
+
+// MagicClaims — содержимое токена из ссылки для входа. NonceHash — sha256
+// nonce из куки браузера, запросившего ссылку.
+type MagicClaims struct {
+	UserID    string
+	ID        string
+	NonceHash string
+}
+
+// Генерация Magic Token — одноразовой ссылки для входа без пароля.
+// Возвращает токен и его jti.
+func (j *JwtUsecase) GenerateMagic(userID, nonceHash string, ttl time.Duration) (string, string, error) {
+	jti := uuid.NewString()
+	claims := jwt.MapClaims{
+		"sub":  userID,
+		"jti":  jti,
+		"nbh":  nonceHash,
+		"exp":  time.Now().Add(ttl).Unix(),
+		"type": "magic",
+	}
+	signed, err := j.keys[TokenMagic].sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
+}
+
+// Валидация Magic Token
+func (j *JwtUsecase) ValidateMagic(tokenStr string) (MagicClaims, error) {
+	t, err := j.keys[TokenMagic].parse(tokenStr)
+	if err != nil || !t.Valid {
+		return MagicClaims{}, errors.New("invalid token")
+	}
+
+	claims := t.Claims.(jwt.MapClaims)
+	if claims["type"] != "magic" {
+		return MagicClaims{}, errors.New("invalid token type")
+	}
+
+	sub, _ := claims["sub"].(string)
+	jti, _ := claims["jti"].(string)
+	nbh, _ := claims["nbh"].(string)
+	if sub == "" || jti == "" || nbh == "" {
+		return MagicClaims{}, errors.New("invalid token")
+	}
+	return MagicClaims{UserID: sub, ID: jti, NonceHash: nbh}, nil
 }
 
 // Валидация Access Token
-func (j *JwtUsecase) ValidateAccess(tokenStr string) (string, user.Role, error) {
-	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-		return j.secretAccess, nil
-	})
+func (j *JwtUsecase) ValidateAccess(tokenStr string) (AccessClaims, error) {
+	token, err := j.keys[TokenAccess].parse(tokenStr)
 	if err != nil {
 		// if errors.Is(err, jwt.ErrTokenExpired) {
 		// 	return "", errors.New("token is expired")
 		// }
-		return "", "", errors.New("token is expired")
+		return AccessClaims{}, errors.New("token is expired")
 	}
 
 	if !token.Valid {
-		return "", "", errors.New("invalid token")
+		return AccessClaims{}, errors.New("invalid token")
 	}
 	claims := token.Claims.(jwt.MapClaims)
 	if claims["type"] != "access" {
-		return "", "", errors.New("invalid token type")
+		return AccessClaims{}, errors.New("invalid token type")
 	}
 
 	sub, _ := claims["sub"].(string)
 	roleStr, ok := claims["role"].(string)
 	if !ok {
-		return "", "", errors.New("invalid role claim")
+		return AccessClaims{}, errors.New("invalid role claim")
 	}
+	sid, _ := claims["sid"].(string)
+	// числа в MapClaims приходят как float64; нет claim — версия 0
+	ver, _ := claims["ver"].(float64)
 
-	role := user.Role(roleStr)
-
-	return sub, role, nil
+	access := AccessClaims{UserID: sub, Role: user.Role(roleStr), SessionID: sid, Version: int(ver)}
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		access.ActorID, _ = act["sub"].(string)
+		actorRole, _ := act["role"].(string)
+		actorVer, _ := act["ver"].(float64)
+		access.ActorRole, access.ActorVersion = user.Role(actorRole), int(actorVer)
+		access.ImpersonationID, _ = claims["jti"].(string)
+		if access.ActorID == "" || actorRole == "" || access.ImpersonationID == "" {
+			return AccessClaims{}, errors.New("invalid act claim")
+		}
+	}
+	return access, nil
 }
 
 // Валидация Refresh Token
-func (j *JwtUsecase) ValidateRefresh(tokenStr string) (string, user.Role, error) {
-	t, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-		return j.secretRefresh, nil
-	})
+func (j *JwtUsecase) ValidateRefresh(tokenStr string) (RefreshClaims, error) {
+	t, err := j.keys[TokenRefresh].parse(tokenStr)
 
 	if err != nil {
 		// if errors.Is(err, jwt.ErrTokenExpired) {
 		// 	return "", errors.New("token is expired")
 		// }
-		return "", "", errors.New("token is expired")
+		return RefreshClaims{}, errors.New("token is expired")
 	}
 
 	if !t.Valid {
-		return "", "", err
+		return RefreshClaims{}, errors.New("invalid token")
 	}
 
 	claims := t.Claims.(jwt.MapClaims)
 
 	if claims["type"] != "refresh" {
-		return "", "", errors.New("invalid token type")
+		return RefreshClaims{}, errors.New("invalid token type")
 	}
 
 	sub, _ := claims["sub"].(string)
 	roleStr, ok := claims["role"].(string)
 	if !ok {
-		return "", "", errors.New("invalid role claim")
+		return RefreshClaims{}, errors.New("invalid role claim")
 	}
 
-	role := user.Role(roleStr)
-	return sub, role, nil
+	// токены, выпущенные до ротации, не привязаны к семейству
+	jti, _ := claims["jti"].(string)
+	fam, _ := claims["fam"].(string)
+	if jti == "" || fam == "" {
+		return RefreshClaims{}, errors.New("invalid token")
+	}
+
+	ver, _ := claims["ver"].(float64)
+
+	return RefreshClaims{UserID: sub, Role: user.Role(roleStr), ID: jti, FamilyID: fam, Version: int(ver)}, nil
 }
 
 // Валидация Invite Token
 func (j *JwtUsecase) ValidateInvite(tokenStr string) (string, error) {
-	t, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-		return j.secretAccess, nil
-	})
+	t, err := j.keys[TokenInvite].parse(tokenStr)
 	if err != nil || !t.Valid {
 		return "", err
 	}
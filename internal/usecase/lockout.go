@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"test-project/internal/redis"
+)
+
+// LockedError — учётная запись временно заблокирована после серии
+// неудачных попыток входа.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	minutes := int(math.Ceil(e.RetryAfter.Minutes()))
+	return fmt.Sprintf("Слишком много неудачных попыток входа. Учётная запись заблокирована, попробуйте через %d мин.", minutes)
+}
+
+// LockoutPolicy — MaxFailures неудачных попыток подряд (пароль или код
+// 2FA) блокируют вход на Duration. MaxFailures = 0 отключает блокировку.
+type LockoutPolicy struct {
+	MaxFailures int
+	Duration    time.Duration
+}
+
+// lockout считает неудачные входы в Redis: "login:failures:<id>" живёт
+// Duration с первой ошибки, "login:lock:<id>" — пока действует блокировка.
+type lockout struct {
+	redis  *redis.Client
+	policy LockoutPolicy
+}
+
+func failuresKey(userID string) string { return "login:failures:" + userID }
+func lockKey(userID string) string     { return "login:lock:" + userID }
+
+// Check возвращает *LockedError, если вход сейчас заблокирован.
+func (l lockout) Check(userID string) error {
+	if l.policy.MaxFailures <= 0 {
+		return nil
+	}
+
+	ttl, err := l.redis.TTL(lockKey(userID))
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		return &LockedError{RetryAfter: ttl}
+	}
+	return nil
+}
+
+// Fail учитывает неудачную попытку и блокирует вход, если их набралось
+// MaxFailures.
+func (l lockout) Fail(userID string) error {
+	if l.policy.MaxFailures <= 0 {
+		return nil
+	}
+
+	n, err := l.redis.IncrWithTTL(failuresKey(userID), l.policy.Duration)
+	if err != nil {
+		return err
+	}
+	if n < int64(l.policy.MaxFailures) {
+		return nil
+	}
+
+	if err := l.redis.SetEX(lockKey(userID), "1", l.policy.Duration); err != nil {
+		return err
+	}
+	return l.redis.Del(failuresKey(userID))
+}
+
+// Reset сбрасывает счётчик после успешного входа и снимает блокировку.
+func (l lockout) Reset(userID string) error {
+	return l.redis.Del(failuresKey(userID), lockKey(userID))
+}
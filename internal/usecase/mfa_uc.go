@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	mfaDomain "test-project/internal/domain/mfa"
+	userDomain "test-project/internal/domain/user"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MfaUsecase interface {
+	// Enroll начинает подключение 2FA: новый секрет и otpauth-ссылка.
+	Enroll(userID string) (mfaDomain.Enrollment, error)
+	// Confirm включает 2FA по первому коду и возвращает резервные коды.
+	Confirm(userID, code string) ([]string, error)
+	Disable(userID, password, code string) error
+	// Verify проверяет код из приложения или резервный код при входе.
+	Verify(userID, code string) error
+	Enabled(userID string) (bool, error)
+	// Required сообщает, что для роли 2FA обязательна.
+	Required(role userDomain.Role) bool
+}
+
+type mfaUsecase struct {
+	repo              mfaDomain.Repository
+	users             userDomain.UserRepository
+	issuer            string
+	requireSuperAdmin bool
+}
+
+func NewMfaUsecase(repo mfaDomain.Repository, users userDomain.UserRepository, issuer string, requireSuperAdmin bool) MfaUsecase {
+	return &mfaUsecase{repo: repo, users: users, issuer: issuer, requireSuperAdmin: requireSuperAdmin}
+}
+
+func (u *mfaUsecase) Required(role userDomain.Role) bool {
+	return u.requireSuperAdmin && role == userDomain.RoleSuperAdmin
+}
+
+func (u *mfaUsecase) Enabled(userID string) (bool, error) {
+	state, err := u.repo.Get(userID)
+	if err != nil {
+		return false, err
+	}
+	return state.Enabled, nil
+}
+
+func (u *mfaUsecase) Enroll(userID string) (mfaDomain.Enrollment, error) {
+	user, err := u.users.FindByID(userID)
+	if err != nil {
+		return mfaDomain.Enrollment{}, err
+	}
+	state, err := u.repo.Get(userID)
+	if err != nil {
+		return mfaDomain.Enrollment{}, err
+	}
+	if state.Enabled {
+		return mfaDomain.Enrollment{}, mfaDomain.ErrAlreadyEnabled
+	}
+
+	secret, err := mfaDomain.GenerateSecret()
+	if err != nil {
+		return mfaDomain.Enrollment{}, err
+	}
+	if err := u.repo.SetSecret(userID, secret); err != nil {
+		return mfaDomain.Enrollment{}, err
+	}
+
+	return mfaDomain.Enrollment{Secret: secret, URI: mfaDomain.URI(u.issuer, user.Email, secret)}, nil
+}
+
+func (u *mfaUsecase) Confirm(userID, code string) ([]string, error) {
+	state, err := u.repo.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	if state.Enabled {
+		return nil, mfaDomain.ErrAlreadyEnabled
+	}
+	if state.Secret == nil {
+		return nil, mfaDomain.ErrNotStarted
+	}
+	if err := u.verifyTOTP(userID, *state.Secret, code); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, mfaDomain.RecoveryCodeCount)
+	hashes := make([]string, mfaDomain.RecoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := hex.EncodeToString(buf)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	if err := u.repo.Enable(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (u *mfaUsecase) Disable(userID, password, code string) error {
+	user, err := u.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if u.Required(user.Role) {
+		return mfaDomain.ErrRequired
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return userDomain.ErrWrongPassword
+	}
+	if err := u.Verify(userID, code); err != nil {
+		return err
+	}
+
+	return u.repo.Disable(userID)
+}
+
+func (u *mfaUsecase) Verify(userID, code string) error {
+	state, err := u.repo.Get(userID)
+	if err != nil {
+		return err
+	}
+	if !state.Enabled || state.Secret == nil {
+		return mfaDomain.ErrNotEnrolled
+	}
+
+	if _, ok := mfaDomain.Verify(*state.Secret, code, time.Now()); ok {
+		return u.verifyTOTP(userID, *state.Secret, code)
+	}
+
+	used, err := u.repo.UseRecoveryCode(userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return mfaDomain.ErrInvalidCode
+	}
+	return nil
+}
+
+// verifyTOTP проверяет код и запоминает его интервал, чтобы перехваченный
+// код нельзя было использовать повторно.
+func (u *mfaUsecase) verifyTOTP(userID, secret, code string) error {
+	step, ok := mfaDomain.Verify(secret, code, time.Now())
+	if !ok {
+		return mfaDomain.ErrInvalidCode
+	}
+
+	fresh, err := u.repo.UseStep(userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return mfaDomain.ErrInvalidCode
+	}
+	return nil
+}
+
+// hashRecoveryCode нормализует резервный код (регистр, пробелы, дефисы) и хэширует его.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
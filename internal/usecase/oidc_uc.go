@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	userDomain "test-project/internal/domain/user"
+	"test-project/internal/redis"
+	"test-project/pkg/oidc"
+	"time"
+)
+
+type OIDCUsecase interface {
+	// Start начинает вход: запоминает state, nonce и code_verifier и
+	// возвращает адрес страницы входа провайдера и state. State нужно
+	// сохранить в браузере и передать в Finish как browserState.
+	Start(ctx context.Context) (string, string, error)
+	// Finish проверяет ответ провайдера и выдаёт пару токенов. Ответ
+	// принимается только в браузере, начавшем вход (browserState == state):
+	// иначе злоумышленник мог бы подсунуть ссылку с кодом для своей
+	// учётной записи.
+	Finish(ctx context.Context, code, state, browserState, userAgent, ip string) (LoginResult, error)
+}
+
+var (
+	ErrOIDCState  = errors.New("Вход через провайдера устарел или уже завершён, начните заново")
+	ErrOIDCEmail  = errors.New("Провайдер не сообщил подтверждённый email")
+	ErrOIDCNoRole = errors.New("Ни одна из ваших групп не даёт доступа к системе")
+)
+
+// OIDCStateTTL — сколько есть времени на вход у провайдера.
+const OIDCStateTTL = 10 * time.Minute
+
+type oidcState struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type oidcUsecase struct {
+	provider    *oidc.Provider
+	auth        AuthUsecase
+	redis       *redis.Client
+	groupsClaim string
+	groupRoles  map[string]userDomain.Role
+}
+
+// NewOIDCUsecase создаёт вход через провайдера. groupRoles сопоставляет
+// группы ролям (см. ParseGroupRoles); пустое соответствие роль не меняет.
+func NewOIDCUsecase(provider *oidc.Provider, auth AuthUsecase, rc *redis.Client, groupsClaim string, groupRoles map[string]userDomain.Role) OIDCUsecase {
+	return &oidcUsecase{
+		provider:    provider,
+		auth:        auth,
+		redis:       rc,
+		groupsClaim: groupsClaim,
+		groupRoles:  groupRoles,
+	}
+}
+
+// ParseGroupRoles разбирает соответствие вида "admins=SUPERADMIN,drivers=USER".
+func ParseGroupRoles(s string) (map[string]userDomain.Role, error) {
+	roles := map[string]userDomain.Role{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(group) == "" {
+			return nil, fmt.Errorf("ожидалось группа=РОЛЬ, получено %q", pair)
+		}
+		r := userDomain.Role(strings.ToUpper(strings.TrimSpace(role)))
+		if roleRank(r) < 0 {
+			return nil, fmt.Errorf("неизвестная роль %q", role)
+		}
+		roles[strings.TrimSpace(group)] = r
+	}
+	return roles, nil
+}
+
+// roleRank — старшинство роли: порядок в AllRoles, -1 для неизвестной.
+func roleRank(role userDomain.Role) int {
+	for i, r := range userDomain.AllRoles {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+func oidcStateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+func (u *oidcUsecase) Start(ctx context.Context) (string, string, error) {
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	st := oidcState{}
+	if st.Nonce, err = oidc.RandomString(); err != nil {
+		return "", "", err
+	}
+	if st.Verifier, err = oidc.RandomString(); err != nil {
+		return "", "", err
+	}
+
+	url, err := u.provider.AuthCodeURL(ctx, state, st.Nonce, st.Verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, _ := json.Marshal(st)
+	if err := u.redis.SetEX(oidcStateKey(state), string(data), OIDCStateTTL); err != nil {
+		return "", "", err
+	}
+	return url, state, nil
+}
+
+func (u *oidcUsecase) Finish(ctx context.Context, code, state, browserState, userAgent, ip string) (LoginResult, error) {
+	if state == "" || code == "" {
+		return LoginResult{}, ErrOIDCState
+	}
+	if subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return LoginResult{}, ErrOIDCState
+	}
+	// state одноразовый: повтор того же ответа провайдера не пройдёт
+	raw, err := u.redis.GetDel(oidcStateKey(state))
+	if err != nil {
+		return LoginResult{}, err
+	}
+	var st oidcState
+	if raw == "" || json.Unmarshal([]byte(raw), &st) != nil {
+		return LoginResult{}, ErrOIDCState
+	}
+
+	idToken, err := u.provider.Exchange(ctx, code, st.Verifier)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	claims, err := u.provider.Verify(ctx, idToken, st.Nonce)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	// email_verified есть не у всех провайдеров; явное false не принимаем
+	email := strings.TrimSpace(claims.String("email"))
+	if email == "" || (claims["email_verified"] != nil && !claims.Bool("email_verified")) {
+		return LoginResult{}, ErrOIDCEmail
+	}
+
+	identity := ExternalIdentity{Email: email, Username: claims.String("name")}
+	if identity.Username == "" {
+		identity.Username = claims.String("preferred_username")
+	}
+
+	if len(u.groupRoles) > 0 {
+		role, ok := u.roleFor(claims.Strings(u.groupsClaim))
+		if !ok {
+			return LoginResult{}, ErrOIDCNoRole
+		}
+		identity.Role = &role
+	}
+
+	return u.auth.LoginExternal(identity, userAgent, ip)
+}
+
+// roleFor выбирает старшую роль из групп пользователя.
+func (u *oidcUsecase) roleFor(groups []string) (userDomain.Role, bool) {
+	best, found := userDomain.Role(""), false
+	for _, g := range groups {
+		r, ok := u.groupRoles[g]
+		if ok && (!found || roleRank(r) > roleRank(best)) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
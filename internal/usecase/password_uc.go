@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	sessionDomain "test-project/internal/domain/session"
+	userDomain "test-project/internal/domain/user"
+	"test-project/internal/redis"
+	"test-project/internal/validator"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type PasswordUsecase interface {
+	// Forgot выпускает токен сброса пароля. Для неизвестного email
+	// возвращается пустой токен без ошибки, чтобы не раскрывать адреса.
+	Forgot(input userDomain.ForgotPasswordRequest) (string, error)
+	// Reset задаёт новый пароль по токену и завершает все сессии пользователя.
+	Reset(input userDomain.ResetPasswordRequest) error
+	// Change меняет пароль по текущему и завершает все сессии, кроме currentSessionID.
+	Change(userID, currentSessionID string, input userDomain.ChangePasswordRequest) error
+	// CheckPolicy проверяет пароль новой учётной записи, например при
+	// регистрации по приглашению. Нарушения — *PolicyError.
+	CheckPolicy(password string) error
+}
+
+type passwordUsecase struct {
+	users     userDomain.UserRepository
+	resets    userDomain.PasswordResetRepository
+	sessions  sessionDomain.SessionRepository
+	redis     *redis.Client
+	policy    userDomain.PasswordPolicy
+	resetTTL  time.Duration
+	validator *validator.Validator
+}
+
+func NewPasswordUsecase(
+	users userDomain.UserRepository,
+	resets userDomain.PasswordResetRepository,
+	sessions sessionDomain.SessionRepository,
+	rc *redis.Client,
+	policy userDomain.PasswordPolicy,
+	resetTTL time.Duration,
+	v *validator.Validator,
+) PasswordUsecase {
+	return &passwordUsecase{
+		users:     users,
+		resets:    resets,
+		sessions:  sessions,
+		redis:     rc,
+		policy:    policy,
+		resetTTL:  resetTTL,
+		validator: v,
+	}
+}
+
+func (u *passwordUsecase) CheckPolicy(password string) error {
+	return u.policy.Check(password)
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *passwordUsecase) Forgot(input userDomain.ForgotPasswordRequest) (string, error) {
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return "", errors.New(strings.Join(errs, "; "))
+	}
+
+	user, err := u.users.FindByEmail(input.Email)
+	if err != nil {
+		return "", nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := u.resets.Create(user.ID, hashResetToken(token), time.Now().Add(u.resetTTL)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (u *passwordUsecase) Reset(input userDomain.ResetPasswordRequest) error {
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	// политику проверяем до погашения токена, чтобы ссылкой можно было воспользоваться ещё раз
+	if err := u.policy.Check(input.Password); err != nil {
+		return err
+	}
+
+	userID, err := u.resets.Consume(hashResetToken(input.Token))
+	if err != nil {
+		return err
+	}
+
+	return u.setPassword(userID, input.Password, "")
+}
+
+func (u *passwordUsecase) Change(userID, currentSessionID string, input userDomain.ChangePasswordRequest) error {
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	user, err := u.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.CurrentPassword)) != nil {
+		return userDomain.ErrWrongPassword
+	}
+	if input.NewPassword == input.CurrentPassword {
+		return userDomain.ErrSamePassword
+	}
+	if err := u.policy.Check(input.NewPassword); err != nil {
+		return err
+	}
+
+	return u.setPassword(userID, input.NewPassword, currentSessionID)
+}
+
+// setPassword сохраняет хэш нового пароля и завершает сессии пользователя,
+// кроме keepSessionID.
+func (u *passwordUsecase) setPassword(userID, password, keepSessionID string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := u.users.SetPassword(userID, string(hash)); err != nil {
+		return err
+	}
+
+	_, err = revokeUserSessions(u.redis, u.sessions, userID, keepSessionID)
+	return err
+}
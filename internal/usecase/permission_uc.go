@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	permissionDomain "test-project/internal/domain/permission"
+	userDomain "test-project/internal/domain/user"
+	"test-project/internal/redis"
+	"time"
+)
+
+type PermissionUsecase interface {
+	// ForRole возвращает разрешения роли; читается на каждый запрос, поэтому кэшируется.
+	ForRole(role userDomain.Role) ([]permissionDomain.Permission, error)
+	List() ([]permissionDomain.Definition, error)
+	// SetRole заменяет разрешения роли.
+	SetRole(role userDomain.Role, perms []permissionDomain.Permission) error
+}
+
+// rolePermissionsTTL — сколько набор разрешений роли живёт в кэше.
+// SetRole сбрасывает кэш сразу.
+const rolePermissionsTTL = 10 * time.Minute
+
+// noPermissions кэшируется для роли без разрешений: пустая строка в Redis
+// означает отсутствие ключа.
+const noPermissions = "-"
+
+type permissionUsecase struct {
+	repo  permissionDomain.Repository
+	redis *redis.Client
+}
+
+func NewPermissionUsecase(repo permissionDomain.Repository, rc *redis.Client) PermissionUsecase {
+	return &permissionUsecase{repo: repo, redis: rc}
+}
+
+func rolePermissionsKey(role userDomain.Role) string {
+	return "permissions:" + string(role)
+}
+
+func (u *permissionUsecase) ForRole(role userDomain.Role) ([]permissionDomain.Permission, error) {
+	cached, err := u.redis.Get(rolePermissionsKey(role))
+	if err != nil {
+		return nil, err
+	}
+	if cached == noPermissions {
+		return []permissionDomain.Permission{}, nil
+	}
+	if cached != "" {
+		names := strings.Split(cached, ",")
+		perms := make([]permissionDomain.Permission, len(names))
+		for i, name := range names {
+			perms[i] = permissionDomain.Permission(name)
+		}
+		return perms, nil
+	}
+
+	perms, err := u.repo.ForRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	value := noPermissions
+	if len(perms) > 0 {
+		names := make([]string, len(perms))
+		for i, p := range perms {
+			names[i] = string(p)
+		}
+		value = strings.Join(names, ",")
+	}
+	if err := u.redis.SetEX(rolePermissionsKey(role), value, rolePermissionsTTL); err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+func (u *permissionUsecase) List() ([]permissionDomain.Definition, error) {
+	return u.repo.All()
+}
+
+func (u *permissionUsecase) SetRole(role userDomain.Role, perms []permissionDomain.Permission) error {
+	known := false
+	for _, r := range userDomain.AllRoles {
+		known = known || r == role
+	}
+	if !known {
+		return fmt.Errorf("%w: %s", permissionDomain.ErrRole, role)
+	}
+
+	// иначе никто больше не сможет вернуть права
+	if role == userDomain.RoleSuperAdmin && !hasPermission(perms, permissionDomain.PermissionManage) {
+		return permissionDomain.ErrLockout
+	}
+
+	if err := u.repo.SetRole(role, perms); err != nil {
+		return err
+	}
+	return u.redis.Del(rolePermissionsKey(role))
+}
+
+func hasPermission(perms []permissionDomain.Permission, perm permissionDomain.Permission) bool {
+	for _, p := range perms {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	cargoDomain "test-project/internal/domain/cargo"
+	"time"
+)
+
+type ScheduleUsecase interface {
+	Board(from, to time.Time, scope cargoDomain.Scope) ([]cargoDomain.TruckTimeline, error)
+}
+
+type scheduleUsecase struct {
+	cargos cargoDomain.CargoRepository
+}
+
+func NewScheduleUsecase(cargos cargoDomain.CargoRepository) ScheduleUsecase {
+	return &scheduleUsecase{cargos: cargos}
+}
+
+// Board возвращает расписание видимых машин за период [from, to)
+// со свободными промежутками между грузами.
+func (u *scheduleUsecase) Board(from, to time.Time, scope cargoDomain.Scope) ([]cargoDomain.TruckTimeline, error) {
+	if !to.After(from) || to.Sub(from) > cargoDomain.MaxScheduleRange {
+		return nil, cargoDomain.ErrScheduleRange
+	}
+
+	timelines, err := u.cargos.FindSchedule(from, to, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range timelines {
+		timelines[i].FillGaps(from, to)
+	}
+	return timelines, nil
+}
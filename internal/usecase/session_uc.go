@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	sessionDomain "test-project/internal/domain/session"
+	"test-project/internal/redis"
+)
+
+type SessionUsecase interface {
+	// List возвращает активные сессии пользователя; currentID помечается текущей.
+	List(userID, currentID string) ([]sessionDomain.Session, error)
+	Revoke(userID, sessionID string) error
+	// RevokeOthers завершает все сессии пользователя, кроме currentID.
+	// Пустой currentID завершает все сессии.
+	RevokeOthers(userID, currentID string) ([]string, error)
+}
+
+type sessionUsecase struct {
+	repo  sessionDomain.SessionRepository
+	redis *redis.Client
+}
+
+func NewSessionUsecase(repo sessionDomain.SessionRepository, rc *redis.Client) SessionUsecase {
+	return &sessionUsecase{repo: repo, redis: rc}
+}
+
+func (u *sessionUsecase) List(userID, currentID string) ([]sessionDomain.Session, error) {
+	list, err := u.repo.FindActive(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		list[i].Current = list[i].ID == currentID
+	}
+	return list, nil
+}
+
+func (u *sessionUsecase) Revoke(userID, sessionID string) error {
+	if _, err := u.repo.FindActiveByID(userID, sessionID); err != nil {
+		return err
+	}
+	return revokeSession(u.redis, u.repo, sessionID)
+}
+
+func (u *sessionUsecase) RevokeOthers(userID, currentID string) ([]string, error) {
+	return revokeUserSessions(u.redis, u.repo, userID, currentID)
+}
+
+// revokeUserSessions завершает все сессии пользователя, кроме exceptID.
+func revokeUserSessions(rc *redis.Client, repo sessionDomain.SessionRepository, userID, exceptID string) ([]string, error) {
+	list, err := repo.FindActive(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// сначала гасим токены в Redis, затем отмечаем сессии в базе
+	var keys []string
+	for _, s := range list {
+		if s.ID != exceptID {
+			keys = append(keys, refreshFamilyKey(s.ID))
+		}
+	}
+	if len(keys) > 0 {
+		if err := rc.Del(keys...); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo.RevokeAll(userID, exceptID)
+}
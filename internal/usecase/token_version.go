@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	userDomain "test-project/internal/domain/user"
+	"test-project/internal/redis"
+	"time"
+)
+
+// tokenVersionTTL — сколько версия токенов живёт в кэше. Изменения
+// через UserUsecase сбрасывают кэш сразу.
+const tokenVersionTTL = 10 * time.Minute
+
+// deletedTokenVersion кэшируется для удалённых пользователей: ни один
+// токен с такой версией не выдаётся.
+const deletedTokenVersion = -1
+
+// tokenVersions читает users.token_version через кэш в Redis.
+type tokenVersions struct {
+	repo  userDomain.UserRepository
+	redis *redis.Client
+}
+
+func tokenVersionKey(userID string) string {
+	return "token_version:" + userID
+}
+
+func (t tokenVersions) Get(userID string) (int, error) {
+	cached, err := t.redis.Get(tokenVersionKey(userID))
+	if err != nil {
+		return 0, err
+	}
+	if cached != "" {
+		return strconv.Atoi(cached)
+	}
+
+	v, err := t.repo.TokenVersion(userID)
+	if errors.Is(err, userDomain.ErrUserNotFound) {
+		v, err = deletedTokenVersion, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := t.redis.SetEX(tokenVersionKey(userID), strconv.Itoa(v), tokenVersionTTL); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// Current сообщает, что токен с версией version ещё действителен.
+func (t tokenVersions) Current(userID string, version int) (bool, error) {
+	v, err := t.Get(userID)
+	if err != nil {
+		return false, err
+	}
+	return v != deletedTokenVersion && v == version, nil
+}
+
+func (t tokenVersions) Invalidate(userID string) error {
+	return t.redis.Del(tokenVersionKey(userID))
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	cargoDomain "test-project/internal/domain/cargo"
+	trashDomain "test-project/internal/domain/trash"
+)
+
+type TrashUsecase interface {
+	List() ([]trashDomain.Item, error)
+	Restore(t trashDomain.ItemType, id string, actorID string) error
+	Purge(ctx context.Context, t trashDomain.ItemType, id string) error
+}
+
+type trashUsecase struct {
+	repo  trashDomain.Repository
+	files *FileService
+}
+
+func NewTrashUsecase(r trashDomain.Repository, files *FileService) TrashUsecase {
+	return &trashUsecase{repo: r, files: files}
+}
+
+func (u *trashUsecase) List() ([]trashDomain.Item, error) {
+	return u.repo.List()
+}
+
+func (u *trashUsecase) Restore(t trashDomain.ItemType, id string, actorID string) error {
+	if !t.Valid() {
+		return trashDomain.ErrUnknownType
+	}
+
+	// восстановление груза тоже попадает в его историю
+	return u.repo.Restore(t, id, func(after cargoDomain.Cargo) *cargoDomain.Revision {
+		snapshot := cargoDomain.Snapshot(after)
+		rev := cargoDomain.NewRevision(cargoDomain.RevisionRestore, after.ID, actorID, cargoDomain.Diff(nil, snapshot), snapshot)
+		return &rev
+	})
+}
+
+// Purge окончательно удаляет объект из корзины вместе с его файлами.
+func (u *trashUsecase) Purge(ctx context.Context, t trashDomain.ItemType, id string) error {
+	if !t.Valid() {
+		return trashDomain.ErrUnknownType
+	}
+
+	files, err := u.files.ListByOwner(ctx, t.Table(), id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.Purge(t, id); err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(files))
+	for i, f := range files {
+		ids[i] = f.ID
+	}
+	return u.files.DeleteMany(ctx, ids)
+}
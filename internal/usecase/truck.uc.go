@@ -1,35 +1,70 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"strings"
 	"test-project/internal/domain/cargo"
 	truckDomain "test-project/internal/domain/truck"
 	"test-project/internal/validator"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type TruckUsecase interface {
 	CreateTruck(input truckDomain.Truck) (truckDomain.Truck, error)
 	ListTrucks() ([]truckDomain.Truck, error)
 	GetTruck(id string) (truckDomain.Truck, error)
-	GetTruckCargos(id string, limit int, page int) ([]cargo.Cargo, error)
+	UpdateTruck(id string, input truckDomain.UpdateTruckInput) (truckDomain.Truck, error)
+	DeleteTruck(id string, actorID string) error
+	GetTruckCargos(id string, filter cargo.ListFilter) (cargo.ListResult, error)
+
+	AttachFiles(ctx context.Context, id, kind string, files []*multipart.FileHeader) error
+	DeleteFiles(ctx context.Context, id string, fileIDs []string) error
 }
 
 type truckUsecase struct {
 	repo      truckDomain.TruckRepository
+	files     *FileService
 	validator *validator.Validator
 }
 
-func NewTruckUsecase(r truckDomain.TruckRepository, v *validator.Validator) TruckUsecase {
-	return &truckUsecase{repo: r, validator: v}
+func NewTruckUsecase(r truckDomain.TruckRepository, files *FileService, v *validator.Validator) TruckUsecase {
+	return &truckUsecase{repo: r, files: files, validator: v}
+}
+
+func normalizeTruckIDs(plate, vin *string) {
+	if plate != nil {
+		*plate = validator.NormalizePlate(*plate)
+	}
+	if vin != nil {
+		*vin = strings.ToUpper(strings.TrimSpace(*vin))
+	}
+}
+
+// truckDuplicateErr заменяет нарушение уникальности госномера/VIN на ErrDuplicate.
+func truckDuplicateErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return truckDomain.ErrDuplicate
+	}
+	return err
 }
 
 func (u *truckUsecase) CreateTruck(input truckDomain.Truck) (truckDomain.Truck, error) {
+	normalizeTruckIDs(input.LicensePlate, input.VIN)
+
 	if errs := u.validator.Validate(input); len(errs) > 0 {
-		return truckDomain.Truck{}, errors.New(strings.Join(errs, "; "))
+		return truckDomain.Truck{}, fmt.Errorf("%w: %s", truckDomain.ErrValidation, strings.Join(errs, "; "))
 	}
 
-	return u.repo.Create(input)
+	created, err := u.repo.Create(input)
+	if err != nil {
+		return truckDomain.Truck{}, truckDuplicateErr(err)
+	}
+	return created, nil
 }
 
 func (u *truckUsecase) ListTrucks() ([]truckDomain.Truck, error) {
@@ -40,6 +75,78 @@ func (u *truckUsecase) GetTruck(id string) (truckDomain.Truck, error) {
 	return u.repo.FindByID(id)
 }
 
-func (u *truckUsecase) GetTruckCargos(id string, limit int, page int) ([]cargo.Cargo, error) {
-	return u.repo.GetTruckCargos(id, limit, page)
+func (u *truckUsecase) UpdateTruck(id string, input truckDomain.UpdateTruckInput) (truckDomain.Truck, error) {
+	normalizeTruckIDs(input.LicensePlate, input.VIN)
+
+	if errs := u.validator.Validate(input); len(errs) > 0 {
+		return truckDomain.Truck{}, fmt.Errorf("%w: %s", truckDomain.ErrValidation, strings.Join(errs, "; "))
+	}
+
+	if _, err := u.repo.FindByID(id); err != nil {
+		return truckDomain.Truck{}, err
+	}
+
+	updated, err := u.repo.Update(id, input)
+	if err != nil {
+		return truckDomain.Truck{}, truckDuplicateErr(err)
+	}
+	return updated, nil
+}
+
+func (u *truckUsecase) DeleteTruck(id string, actorID string) error {
+	if _, err := u.repo.FindByID(id); err != nil {
+		return err
+	}
+
+	active, err := u.repo.HasActiveCargos(id)
+	if err != nil {
+		return err
+	}
+	if active {
+		return truckDomain.ErrTruckHasActiveCargos
+	}
+
+	return u.repo.Delete(id, actorID)
+}
+
+func (u *truckUsecase) GetTruckCargos(id string, filter cargo.ListFilter) (cargo.ListResult, error) {
+	return u.repo.GetTruckCargos(id, filter)
+}
+
+func (u *truckUsecase) AttachFiles(ctx context.Context, id, kind string, files []*multipart.FileHeader) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	if _, err := u.repo.FindByID(id); err != nil {
+		return err
+	}
+
+	_, err := u.files.UploadKind(ctx, "trucks", id, kind, files)
+	return err
+}
+
+// DeleteFiles удаляет только те файлы, которые принадлежат машине.
+func (u *truckUsecase) DeleteFiles(ctx context.Context, id string, fileIDs []string) error {
+	owned, err := u.files.ListByOwner(ctx, "trucks", id)
+	if err != nil {
+		return err
+	}
+
+	requested := make(map[string]bool, len(fileIDs))
+	for _, fid := range fileIDs {
+		requested[fid] = true
+	}
+
+	var ids []string
+	for _, rec := range owned {
+		if requested[rec.ID] {
+			ids = append(ids, rec.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return u.files.DeleteMany(ctx, ids)
 }
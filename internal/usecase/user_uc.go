@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	userDomain "test-project/internal/domain/user"
+	"test-project/internal/redis"
 	"test-project/internal/validator"
 )
 
@@ -13,15 +14,19 @@ type UserUsecase interface {
 	DeleteUser(id string) error
 	CreateUser(input userDomain.User) (userDomain.User, error)
 	UpdateUser(id string, input userDomain.UpdateUser) error
+
+	Trucks(id string) ([]string, error)
+	SetTrucks(id string, truckIDs []string) ([]string, error)
 }
 
 type userUsecase struct {
 	repo      userDomain.UserRepository
 	validator *validator.Validator
+	versions  tokenVersions
 }
 
-func NewUserUsecase(r userDomain.UserRepository, v *validator.Validator) UserUsecase {
-	return &userUsecase{repo: r, validator: v}
+func NewUserUsecase(r userDomain.UserRepository, v *validator.Validator, rc *redis.Client) UserUsecase {
+	return &userUsecase{repo: r, validator: v, versions: tokenVersions{repo: r, redis: rc}}
 }
 
 func (u *userUsecase) ListUsers() ([]userDomain.User, error) {
@@ -47,7 +52,12 @@ func (u *userUsecase) DeleteUser(id string) error {
 		return err
 	}
 
-	return u.repo.Delete(id)
+	if err := u.repo.Delete(id); err != nil {
+		return err
+	}
+
+	// выданные токены удалённого пользователя перестают действовать сразу
+	return u.versions.Invalidate(id)
 }
 
 func (u *userUsecase) UpdateUser(id string, input userDomain.UpdateUser) error {
@@ -57,5 +67,23 @@ func (u *userUsecase) UpdateUser(id string, input userDomain.UpdateUser) error {
 		return err
 	}
 
-	return u.repo.Update(id, input)
+	if err := u.repo.Update(id, input); err != nil {
+		return err
+	}
+
+	// при смене роли версия токенов выросла — сбрасываем кэш
+	return u.versions.Invalidate(id)
+}
+
+func (u *userUsecase) Trucks(id string) ([]string, error) {
+	return u.repo.Trucks(id)
+}
+
+// SetTrucks заменяет машины пользователя и возвращает новый список.
+func (u *userUsecase) SetTrucks(id string, truckIDs []string) ([]string, error) {
+	if err := u.repo.SetTrucks(id, truckIDs); err != nil {
+		return nil, err
+	}
+
+	return u.repo.Trucks(id)
 }
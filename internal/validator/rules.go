@@ -0,0 +1,239 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// rule — собственное правило валидации вместе с русским текстом ошибки.
+type rule struct {
+	tag         string
+	fn          validator.Func
+	translation string
+}
+
+var rules = []rule{
+	{"ru_plate", isRuPlate, "{0} должен быть российским госномером, например А123ВС77"},
+	{"vin", isVIN, "{0} должен быть корректным VIN из 17 символов с верной контрольной цифрой"},
+	{"ru_phone", isRuPhone, "{0} должен быть российским номером телефона, например +79991234567"},
+	{"ru_license", isRuLicense, "{0} должен быть номером водительского удостоверения, например 99 01 123456"},
+	{"inn", isINN, "{0} должен быть корректным ИНН из 10 или 12 цифр с верными контрольными цифрами"},
+	{"kpp", isKPP, "{0} должен быть корректным КПП из 9 символов"},
+	{"ogrn", isOGRN, "{0} должен быть корректным ОГРН (13 цифр) или ОГРНИП (15 цифр) с верной контрольной цифрой"},
+	{"bik", isBIK, "{0} должен быть корректным БИК из 9 цифр"},
+	{"hazard_class", isHazardClass, "{0} должен быть классом опасности ДОПОГ: 1, 2, 3, 4.1, 4.2, 4.3, 5.1, 5.2, 6.1, 6.2, 7, 8 или 9"},
+}
+
+func registerRules(validate *validator.Validate, trans ut.Translator) error {
+	for _, r := range rules {
+		if err := validate.RegisterValidation(r.tag, r.fn); err != nil {
+			return err
+		}
+
+		tag, text := r.tag, r.translation
+		err := validate.RegisterTranslation(tag, trans,
+			func(ut ut.Translator) error {
+				return ut.Add(tag, text, true)
+			},
+			func(ut ut.Translator, fe validator.FieldError) string {
+				t, _ := ut.T(tag, fe.Field())
+				return t
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// латинские буквы, совпадающие по написанию с допустимыми в госномере
+var plateLatinToCyrillic = strings.NewReplacer(
+	"A", "А", "B", "В", "E", "Е", "K", "К", "M", "М", "H", "Н",
+	"O", "О", "P", "Р", "C", "С", "T", "Т", "Y", "У", "X", "Х",
+)
+
+// NormalizePlate приводит госномер к виду «А123ВС77»: верхний регистр,
+// без пробелов, латинские двойники заменены кириллицей.
+func NormalizePlate(s string) string {
+	s = strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	return plateLatinToCyrillic.Replace(s)
+}
+
+var ruPlateRe = regexp.MustCompile(`^[АВЕКМНОРСТУХ]\d{3}[АВЕКМНОРСТУХ]{2}\d{2,3}$`)
+
+func isRuPlate(fl validator.FieldLevel) bool {
+	s := NormalizePlate(fl.Field().String())
+	if !ruPlateRe.MatchString(s) {
+		return false
+	}
+	// номер 000 не выдаётся
+	return string([]rune(s)[1:4]) != "000"
+}
+
+var (
+	vinRe = regexp.MustCompile(`^[A-HJ-NPR-Z0-9]{17}$`)
+
+	vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+func isVIN(fl validator.FieldLevel) bool {
+	s := strings.ToUpper(strings.TrimSpace(fl.Field().String()))
+	if !vinRe.MatchString(s) {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		sum += vinTransliteration[s[i]] * vinWeights[i]
+	}
+
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	return s[8] == check
+}
+
+// vinTransliteration — числовые значения символов VIN (ISO 3779).
+var vinTransliteration = map[byte]int{
+	'0': 0, '1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+	'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+	'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+	'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+}
+
+// NormalizePhone приводит российский номер к виду «+79991234567».
+// Номер, который не удалось распознать, возвращается без изменений.
+func NormalizePhone(s string) string {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	d := digits.String()
+	switch {
+	case len(d) == 11 && (d[0] == '7' || d[0] == '8'):
+		return "+7" + d[1:]
+	case len(d) == 10 && d[0] == '9':
+		return "+7" + d
+	}
+	return s
+}
+
+var ruPhoneRe = regexp.MustCompile(`^\+7\d{10}$`)
+
+func isRuPhone(fl validator.FieldLevel) bool {
+	return ruPhoneRe.MatchString(NormalizePhone(fl.Field().String()))
+}
+
+// NormalizeLicense приводит номер водительского удостоверения к виду
+// «9901123456»: без пробелов, буквы серии старого образца — заглавные кириллические.
+func NormalizeLicense(s string) string {
+	return NormalizePlate(s)
+}
+
+// серия — 4 символа (цифры, у старых удостоверений — буквы), номер — 6 цифр
+var ruLicenseRe = regexp.MustCompile(`^[0-9А-ЯЁ]{4}\d{6}$`)
+
+func isRuLicense(fl validator.FieldLevel) bool {
+	return ruLicenseRe.MatchString(NormalizeLicense(fl.Field().String()))
+}
+
+var digitsRe = regexp.MustCompile(`^\d+$`)
+
+// digitsOf возвращает цифры строки, если она состоит только из них.
+func digitsOf(s string) ([]int, bool) {
+	s = strings.TrimSpace(s)
+	if !digitsRe.MatchString(s) {
+		return nil, false
+	}
+
+	d := make([]int, len(s))
+	for i := range s {
+		d[i] = int(s[i] - '0')
+	}
+	return d, true
+}
+
+// innCheck вычисляет контрольную цифру ИНН по весам.
+func innCheck(d []int, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += d[i] * w
+	}
+	return sum % 11 % 10
+}
+
+func isINN(fl validator.FieldLevel) bool {
+	d, ok := digitsOf(fl.Field().String())
+	if !ok {
+		return false
+	}
+
+	switch len(d) {
+	case 10:
+		return innCheck(d, []int{2, 4, 10, 3, 5, 9, 4, 6, 8}) == d[9]
+	case 12:
+		return innCheck(d, []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) == d[10] &&
+			innCheck(d, []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) == d[11]
+	}
+	return false
+}
+
+// КПП: код налогового органа (4 цифры), причина постановки (2 цифры или
+// латинские буквы) и порядковый номер (3 цифры)
+var kppRe = regexp.MustCompile(`^\d{4}[\dA-Z]{2}\d{3}$`)
+
+func isKPP(fl validator.FieldLevel) bool {
+	return kppRe.MatchString(strings.ToUpper(strings.TrimSpace(fl.Field().String())))
+}
+
+func isOGRN(fl validator.FieldLevel) bool {
+	d, ok := digitsOf(fl.Field().String())
+	if !ok {
+		return false
+	}
+
+	// контрольная цифра — остаток от деления числа без последней цифры
+	// на 11 (ОГРН) или 13 (ОГРНИП), взятый по модулю 10
+	var mod int
+	switch len(d) {
+	case 13:
+		mod = 11
+	case 15:
+		mod = 13
+	default:
+		return false
+	}
+
+	rem := 0
+	for _, digit := range d[:len(d)-1] {
+		rem = (rem*10 + digit) % mod
+	}
+	return rem%10 == d[len(d)-1]
+}
+
+var bikRe = regexp.MustCompile(`^\d{9}$`)
+
+func isBIK(fl validator.FieldLevel) bool {
+	return bikRe.MatchString(strings.TrimSpace(fl.Field().String()))
+}
+
+// классы и подклассы опасных грузов по ДОПОГ
+var hazardClasses = map[string]bool{
+	"1": true, "2": true, "3": true,
+	"4.1": true, "4.2": true, "4.3": true,
+	"5.1": true, "5.2": true,
+	"6.1": true, "6.2": true,
+	"7": true, "8": true, "9": true,
+}
+
+func isHazardClass(fl validator.FieldLevel) bool {
+	return hazardClasses[fl.Field().String()]
+}
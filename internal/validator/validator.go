@@ -21,6 +21,9 @@ func New() (*Validator, error) {
 	if err := translations.RegisterDefaultTranslations(validate, trans); err != nil {
 		return nil, err
 	}
+	if err := registerRules(validate, trans); err != nil {
+		return nil, err
+	}
 
 	return &Validator{
 		validate: validate,
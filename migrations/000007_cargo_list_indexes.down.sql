@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_cargos_paymentstatus;
+DROP INDEX IF EXISTS idx_cargos_payoutdate;
+DROP INDEX IF EXISTS idx_cargos_loadunloaddate;
+DROP INDEX IF EXISTS idx_cargos_date;
+DROP INDEX IF EXISTS idx_cargos_created_at_id;
+DROP INDEX IF EXISTS idx_cargos_truckid;
@@ -0,0 +1,6 @@
+CREATE INDEX idx_cargos_truckid ON cargos (truckId);
+CREATE INDEX idx_cargos_created_at_id ON cargos ("createdAt" DESC, id DESC);
+CREATE INDEX idx_cargos_date ON cargos (date);
+CREATE INDEX idx_cargos_loadunloaddate ON cargos (loadUnloadDate);
+CREATE INDEX idx_cargos_payoutdate ON cargos (payoutDate);
+CREATE INDEX idx_cargos_paymentstatus ON cargos (paymentStatus);
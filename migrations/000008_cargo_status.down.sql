@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cargo_status_transitions;
+ALTER TABLE cargos DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS cargo_status;
@@ -0,0 +1,31 @@
+CREATE TYPE cargo_status AS ENUM (
+  'draft',
+  'planned',
+  'loading',
+  'in_transit',
+  'delivered',
+  'invoiced',
+  'paid',
+  'closed',
+  'cancelled'
+);
+
+ALTER TABLE cargos ADD COLUMN status cargo_status NOT NULL DEFAULT 'draft';
+
+-- уже оплаченные грузы переводим сразу в paid
+UPDATE cargos SET status = 'paid' WHERE lower(paymentStatus) = 'paid';
+
+CREATE INDEX idx_cargos_status ON cargos (status);
+
+CREATE TABLE cargo_status_transitions (
+  id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  cargo_id    UUID NOT NULL,
+  from_status cargo_status NOT NULL,
+  to_status   cargo_status NOT NULL,
+  user_id     UUID NOT NULL,
+  comment     TEXT,
+  created_at  TIMESTAMPTZ DEFAULT now(),
+
+  CONSTRAINT fk_cargo FOREIGN KEY (cargo_id) REFERENCES cargos(id) ON DELETE CASCADE
+);
+CREATE INDEX ON cargo_status_transitions (cargo_id, created_at);
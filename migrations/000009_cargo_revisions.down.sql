@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS cargo_revisions_append_only ON cargo_revisions;
+DROP FUNCTION IF EXISTS cargo_revisions_append_only();
+DROP TABLE IF EXISTS cargo_revisions;
@@ -0,0 +1,22 @@
+CREATE TABLE cargo_revisions (
+  id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  -- без внешнего ключа: история должна переживать удаление груза
+  cargo_id   UUID NOT NULL,
+  action     TEXT NOT NULL,
+  diff       JSONB NOT NULL DEFAULT '{}',
+  snapshot   JSONB NOT NULL DEFAULT '{}',
+  user_id    UUID NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT now()
+);
+CREATE INDEX ON cargo_revisions (cargo_id, created_at DESC);
+
+-- журнал только дополняется
+CREATE FUNCTION cargo_revisions_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'cargo_revisions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cargo_revisions_append_only
+  BEFORE UPDATE OR DELETE ON cargo_revisions
+  FOR EACH ROW EXECUTE FUNCTION cargo_revisions_append_only();
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_trucks_deleted_at;
+DROP INDEX IF EXISTS idx_cargos_deleted_at;
+
+DELETE FROM cargos WHERE deleted_at IS NOT NULL;
+DELETE FROM trucks WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS trucks_name_key;
+ALTER TABLE trucks ADD CONSTRAINT trucks_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS cargos_cargonumber_key;
+ALTER TABLE cargos ADD CONSTRAINT cargos_cargonumber_key UNIQUE (cargoNumber);
+
+ALTER TABLE cargos DROP CONSTRAINT fk_truck;
+ALTER TABLE cargos ADD CONSTRAINT fk_truck FOREIGN KEY (truckId) REFERENCES trucks(id) ON DELETE CASCADE;
+
+ALTER TABLE trucks DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE trucks DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE cargos DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE cargos DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,19 @@
+ALTER TABLE cargos ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE cargos ADD COLUMN deleted_by UUID;
+
+ALTER TABLE trucks ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE trucks ADD COLUMN deleted_by UUID;
+
+-- удаление машины больше не должно молча удалять её грузы
+ALTER TABLE cargos DROP CONSTRAINT fk_truck;
+ALTER TABLE cargos ADD CONSTRAINT fk_truck FOREIGN KEY (truckId) REFERENCES trucks(id) ON DELETE RESTRICT;
+
+-- уникальность только среди неудалённых записей
+ALTER TABLE cargos DROP CONSTRAINT cargos_cargonumber_key;
+CREATE UNIQUE INDEX cargos_cargonumber_key ON cargos (cargoNumber) WHERE deleted_at IS NULL;
+
+ALTER TABLE trucks DROP CONSTRAINT trucks_name_key;
+CREATE UNIQUE INDEX trucks_name_key ON trucks (name) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_cargos_deleted_at ON cargos (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_trucks_deleted_at ON trucks (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE files DROP COLUMN IF EXISTS kind;
+
+DROP INDEX IF EXISTS trucks_vin_key;
+DROP INDEX IF EXISTS trucks_license_plate_key;
+
+ALTER TABLE trucks DROP COLUMN IF EXISTS active;
+ALTER TABLE trucks DROP COLUMN IF EXISTS volume_m3;
+ALTER TABLE trucks DROP COLUMN IF EXISTS payload_kg;
+ALTER TABLE trucks DROP COLUMN IF EXISTS year;
+ALTER TABLE trucks DROP COLUMN IF EXISTS model;
+ALTER TABLE trucks DROP COLUMN IF EXISTS make;
+ALTER TABLE trucks DROP COLUMN IF EXISTS vin;
+ALTER TABLE trucks DROP COLUMN IF EXISTS license_plate;
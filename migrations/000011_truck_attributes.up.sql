@@ -0,0 +1,14 @@
+ALTER TABLE trucks ADD COLUMN license_plate TEXT;
+ALTER TABLE trucks ADD COLUMN vin           TEXT;
+ALTER TABLE trucks ADD COLUMN make          TEXT;
+ALTER TABLE trucks ADD COLUMN model         TEXT;
+ALTER TABLE trucks ADD COLUMN year          INT;
+ALTER TABLE trucks ADD COLUMN payload_kg    NUMERIC CHECK (payload_kg > 0);
+ALTER TABLE trucks ADD COLUMN volume_m3     NUMERIC CHECK (volume_m3 > 0);
+ALTER TABLE trucks ADD COLUMN active        BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE UNIQUE INDEX trucks_license_plate_key ON trucks (license_plate) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX trucks_vin_key ON trucks (vin) WHERE deleted_at IS NULL;
+
+-- вид вложения: фото или документ
+ALTER TABLE files ADD COLUMN kind TEXT NOT NULL DEFAULT 'photo';
@@ -0,0 +1,13 @@
+ALTER TABLE cargos ADD COLUMN driver TEXT NOT NULL DEFAULT '';
+
+UPDATE cargos c
+   SET driver = d.full_name
+  FROM drivers d
+ WHERE d.id = c.driver_id
+   AND d.id <> '00000000-0000-0000-0000-000000000000';
+
+ALTER TABLE cargos ALTER COLUMN driver DROP DEFAULT;
+ALTER TABLE cargos DROP COLUMN driver_id;
+
+DROP TABLE IF EXISTS drivers;
+DROP TYPE IF EXISTS driver_status;
@@ -0,0 +1,52 @@
+CREATE TYPE driver_status AS ENUM ('active', 'on_leave', 'dismissed');
+
+CREATE TABLE drivers (
+  id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  full_name          TEXT NOT NULL,
+  phone              TEXT,
+  license_number     TEXT,
+  license_categories TEXT[] NOT NULL DEFAULT '{}',
+  license_expiry     DATE,
+  status             driver_status NOT NULL DEFAULT 'active',
+  created_at         TIMESTAMPTZ DEFAULT now()
+);
+CREATE UNIQUE INDEX drivers_license_number_key ON drivers (license_number);
+CREATE INDEX idx_drivers_full_name ON drivers (lower(full_name));
+
+ALTER TABLE cargos ADD COLUMN driver_id UUID;
+ALTER TABLE cargos ADD CONSTRAINT fk_driver FOREIGN KEY (driver_id) REFERENCES drivers(id) ON DELETE RESTRICT;
+
+-- Переносим водителей из текстового поля. Написания, отличающиеся только
+-- регистром, лишними пробелами или «ё/е», считаются одним водителем;
+-- в качестве ФИО берётся самое частое написание.
+WITH spellings AS (
+  SELECT regexp_replace(btrim(driver), '\s+', ' ', 'g') AS name, count(*) AS n
+    FROM cargos
+   WHERE btrim(driver) <> ''
+   GROUP BY 1
+)
+INSERT INTO drivers (full_name)
+SELECT DISTINCT ON (replace(lower(name), 'ё', 'е')) name
+  FROM spellings
+ ORDER BY replace(lower(name), 'ё', 'е'), n DESC, name;
+
+UPDATE cargos c
+   SET driver_id = d.id
+  FROM drivers d
+ WHERE replace(lower(regexp_replace(btrim(c.driver), '\s+', ' ', 'g')), 'ё', 'е')
+     = replace(lower(d.full_name), 'ё', 'е');
+
+-- Грузы без водителя получают служебного уволенного водителя: поле
+-- обязательное, а назначить его на новые грузы нельзя.
+INSERT INTO drivers (id, full_name, status)
+SELECT '00000000-0000-0000-0000-000000000000', 'Водитель не указан', 'dismissed'
+ WHERE EXISTS (SELECT 1 FROM cargos WHERE driver_id IS NULL);
+
+UPDATE cargos
+   SET driver_id = '00000000-0000-0000-0000-000000000000'
+ WHERE driver_id IS NULL;
+
+ALTER TABLE cargos ALTER COLUMN driver_id SET NOT NULL;
+ALTER TABLE cargos DROP COLUMN driver;
+
+CREATE INDEX idx_cargos_driver_id ON cargos (driver_id);
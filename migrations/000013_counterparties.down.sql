@@ -0,0 +1,5 @@
+ALTER TABLE cargos DROP COLUMN IF EXISTS consignee_id;
+ALTER TABLE cargos DROP COLUMN IF EXISTS shipper_id;
+ALTER TABLE cargos DROP COLUMN IF EXISTS customer_id;
+
+DROP TABLE IF EXISTS counterparties;
@@ -0,0 +1,30 @@
+CREATE TABLE counterparties (
+  id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  legal_name     TEXT NOT NULL,
+  short_name     TEXT,
+  inn            TEXT NOT NULL CHECK (inn ~ '^\d{10}(\d{2})?$'),
+  kpp            TEXT CHECK (kpp ~ '^\d{4}[\dA-Z]{2}\d{3}$'),
+  ogrn           TEXT CHECK (ogrn ~ '^\d{13}(\d{2})?$'),
+  legal_address  TEXT,
+  postal_address TEXT,
+  contacts       JSONB NOT NULL DEFAULT '[]',
+  bank           JSONB,
+  created_at     TIMESTAMPTZ DEFAULT now()
+);
+
+-- у обособленных подразделений один ИНН, но разные КПП
+CREATE UNIQUE INDEX counterparties_inn_kpp_key ON counterparties (inn, COALESCE(kpp, ''));
+CREATE INDEX idx_counterparties_inn ON counterparties (inn text_pattern_ops);
+CREATE INDEX idx_counterparties_legal_name ON counterparties (lower(legal_name));
+
+ALTER TABLE cargos ADD COLUMN customer_id  UUID;
+ALTER TABLE cargos ADD COLUMN shipper_id   UUID;
+ALTER TABLE cargos ADD COLUMN consignee_id UUID;
+
+ALTER TABLE cargos ADD CONSTRAINT fk_customer  FOREIGN KEY (customer_id)  REFERENCES counterparties(id) ON DELETE RESTRICT;
+ALTER TABLE cargos ADD CONSTRAINT fk_shipper   FOREIGN KEY (shipper_id)   REFERENCES counterparties(id) ON DELETE RESTRICT;
+ALTER TABLE cargos ADD CONSTRAINT fk_consignee FOREIGN KEY (consignee_id) REFERENCES counterparties(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_cargos_customer_id  ON cargos (customer_id);
+CREATE INDEX idx_cargos_shipper_id   ON cargos (shipper_id);
+CREATE INDEX idx_cargos_consignee_id ON cargos (consignee_id);
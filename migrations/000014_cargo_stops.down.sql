@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cargo_stops;
+DROP TYPE IF EXISTS stop_type;
@@ -0,0 +1,22 @@
+CREATE TYPE stop_type AS ENUM ('load', 'unload');
+
+CREATE TABLE cargo_stops (
+  id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  cargo_id     UUID NOT NULL,
+  position     INT NOT NULL CHECK (position > 0),
+  type         stop_type NOT NULL,
+  address      TEXT NOT NULL,
+  lat          DOUBLE PRECISION CHECK (lat BETWEEN -90 AND 90),
+  lng          DOUBLE PRECISION CHECK (lng BETWEEN -180 AND 180),
+  planned_from TIMESTAMPTZ,
+  planned_to   TIMESTAMPTZ,
+  arrived_at   TIMESTAMPTZ,
+  departed_at  TIMESTAMPTZ,
+  created_at   TIMESTAMPTZ DEFAULT now(),
+
+  CONSTRAINT fk_cargo FOREIGN KEY (cargo_id) REFERENCES cargos(id) ON DELETE CASCADE,
+  -- отложенная проверка позволяет сдвигать позиции одним UPDATE
+  CONSTRAINT cargo_stops_position_key UNIQUE (cargo_id, position) DEFERRABLE INITIALLY DEFERRED,
+  CONSTRAINT cargo_stops_window CHECK (planned_to IS NULL OR planned_from IS NULL OR planned_to >= planned_from),
+  CONSTRAINT cargo_stops_times CHECK (departed_at IS NULL OR arrived_at IS NULL OR departed_at >= arrived_at)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cargos_truck_planned;
+
+ALTER TABLE cargos DROP CONSTRAINT IF EXISTS cargos_planned_window;
+ALTER TABLE cargos DROP COLUMN IF EXISTS planned_end;
+ALTER TABLE cargos DROP COLUMN IF EXISTS planned_start;
@@ -0,0 +1,11 @@
+ALTER TABLE cargos ADD COLUMN planned_start TIMESTAMPTZ;
+ALTER TABLE cargos ADD COLUMN planned_end   TIMESTAMPTZ;
+
+ALTER TABLE cargos ADD CONSTRAINT cargos_planned_window CHECK (
+  (planned_start IS NULL AND planned_end IS NULL)
+  OR (planned_start IS NOT NULL AND planned_end IS NOT NULL AND planned_end > planned_start)
+);
+
+-- поиск пересечений по машине и выборка расписания за период
+CREATE INDEX idx_cargos_truck_planned ON cargos (truckid, planned_start, planned_end)
+  WHERE deleted_at IS NULL AND planned_start IS NOT NULL;
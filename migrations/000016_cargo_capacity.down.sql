@@ -0,0 +1,4 @@
+ALTER TABLE cargos DROP COLUMN IF EXISTS hazard_class;
+ALTER TABLE cargos DROP COLUMN IF EXISTS pallets;
+ALTER TABLE cargos DROP COLUMN IF EXISTS volume_m3;
+ALTER TABLE cargos DROP COLUMN IF EXISTS weight_kg;
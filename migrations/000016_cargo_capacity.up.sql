@@ -0,0 +1,6 @@
+ALTER TABLE cargos ADD COLUMN weight_kg    NUMERIC CHECK (weight_kg > 0);
+ALTER TABLE cargos ADD COLUMN volume_m3    NUMERIC CHECK (volume_m3 > 0);
+ALTER TABLE cargos ADD COLUMN pallets      INT CHECK (pallets >= 0);
+ALTER TABLE cargos ADD COLUMN hazard_class TEXT CHECK (
+  hazard_class IN ('1', '2', '3', '4.1', '4.2', '4.3', '5.1', '5.2', '6.1', '6.2', '7', '8', '9')
+);
@@ -0,0 +1,14 @@
+-- Сессия — один вход пользователя. id совпадает с семейством refresh-токенов
+-- (claim fam), текущий jti семейства хранится в Redis.
+CREATE TABLE sessions (
+  id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id           UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  user_agent        TEXT NOT NULL DEFAULT '',
+  ip                TEXT NOT NULL DEFAULT '',
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_refreshed_at TIMESTAMPTZ,
+  expires_at        TIMESTAMPTZ NOT NULL,
+  revoked_at        TIMESTAMPTZ
+);
+
+CREATE INDEX idx_sessions_user_active ON sessions (user_id) WHERE revoked_at IS NULL;
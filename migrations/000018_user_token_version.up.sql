@@ -0,0 +1,3 @@
+-- Версия токенов пользователя: растёт при смене роли, токены со старой
+-- версией отклоняются. Кэшируется в Redis (token_version:<user id>).
+ALTER TABLE users ADD COLUMN token_version INT NOT NULL DEFAULT 0;
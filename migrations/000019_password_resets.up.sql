@@ -0,0 +1,10 @@
+-- Одноразовые токены сброса пароля; хранится только SHA-256 токена.
+CREATE TABLE password_resets (
+  token_hash TEXT PRIMARY KEY,
+  user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at    TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_password_resets_user ON password_resets (user_id) WHERE used_at IS NULL;
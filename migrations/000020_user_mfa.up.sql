@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN mfa_secret    TEXT;
+ALTER TABLE users ADD COLUMN mfa_enabled   BOOLEAN NOT NULL DEFAULT FALSE;
+-- последний принятый интервал TOTP, чтобы один код нельзя было применить дважды
+ALTER TABLE users ADD COLUMN mfa_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- Одноразовые резервные коды 2FA; хранится только SHA-256 кода.
+CREATE TABLE mfa_recovery_codes (
+  id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash  TEXT NOT NULL,
+  used_at    TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, code_hash)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE invitations ALTER COLUMN used DROP NOT NULL;
+ALTER TABLE invitations DROP COLUMN IF EXISTS used_at;
+ALTER TABLE invitations DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE invitations DROP COLUMN IF EXISTS username;
+ALTER TABLE invitations DROP COLUMN IF EXISTS role;
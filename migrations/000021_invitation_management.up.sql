@@ -0,0 +1,13 @@
+-- Роль и имя, которые получит приглашённый при регистрации.
+ALTER TABLE invitations ADD COLUMN role     role NOT NULL DEFAULT 'USER';
+ALTER TABLE invitations ADD COLUMN username TEXT;
+
+-- Срок действия хранится явно: просроченные приглашения больше не удаляются
+-- сразу, чтобы их было видно в списке и можно было отправить повторно.
+ALTER TABLE invitations ADD COLUMN expires_at TIMESTAMPTZ;
+UPDATE invitations SET expires_at = "createdAt" + INTERVAL '5 minutes';
+ALTER TABLE invitations ALTER COLUMN expires_at SET NOT NULL;
+
+ALTER TABLE invitations ADD COLUMN used_at TIMESTAMPTZ;
+UPDATE invitations SET used = FALSE WHERE used IS NULL;
+ALTER TABLE invitations ALTER COLUMN used SET NOT NULL;
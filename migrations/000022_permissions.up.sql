@@ -0,0 +1,51 @@
+-- Реестр разрешений. Имена совпадают с константами internal/domain/permission.
+CREATE TABLE permissions (
+  name        TEXT PRIMARY KEY,
+  description TEXT NOT NULL
+);
+
+CREATE TABLE role_permissions (
+  role       role NOT NULL,
+  permission TEXT NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+  PRIMARY KEY (role, permission)
+);
+
+INSERT INTO permissions (name, description) VALUES
+  ('cargo:view',          'Просмотр грузов, их маршрутов и истории'),
+  ('cargo:create',        'Создание грузов'),
+  ('cargo:update',        'Изменение грузов и их маршрутов, откат изменений'),
+  ('cargo:delete',        'Удаление грузов в корзину'),
+  ('cargo:transition',    'Смена статуса груза и отметка остановок'),
+  ('finance:view',        'Просмотр выплат по грузам'),
+  ('truck:view',          'Просмотр машин'),
+  ('truck:manage',        'Создание, изменение и удаление машин и их файлов'),
+  ('driver:view',         'Просмотр водителей'),
+  ('driver:manage',       'Создание, изменение и удаление водителей'),
+  ('counterparty:view',   'Просмотр контрагентов'),
+  ('counterparty:manage', 'Создание, изменение и удаление контрагентов'),
+  ('schedule:view',       'Просмотр расписания машин'),
+  ('trash:view',          'Просмотр корзины'),
+  ('trash:restore',       'Восстановление из корзины'),
+  ('trash:purge',         'Окончательное удаление из корзины'),
+  ('user:view',           'Просмотр пользователей'),
+  ('user:manage',         'Изменение и удаление пользователей, их сессий и блокировок'),
+  ('invitation:manage',   'Приглашение пользователей'),
+  ('permission:manage',   'Управление разрешениями ролей');
+
+-- Права по умолчанию повторяют прежние проверки ролей в обработчиках.
+INSERT INTO role_permissions (role, permission)
+SELECT 'USER'::role, unnest(ARRAY[
+  'cargo:view', 'cargo:transition', 'finance:view', 'truck:view', 'driver:view',
+  'counterparty:view', 'schedule:view', 'user:view'
+]);
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'EDITOR'::role, unnest(ARRAY[
+  'cargo:view', 'cargo:create', 'cargo:update', 'cargo:delete', 'cargo:transition',
+  'finance:view', 'truck:view', 'truck:manage', 'driver:view', 'driver:manage',
+  'counterparty:view', 'counterparty:manage', 'schedule:view',
+  'trash:view', 'trash:restore', 'user:view'
+]);
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'SUPERADMIN'::role, name FROM permissions;
@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE name = 'cargo:view_all';
+
+DROP TABLE IF EXISTS user_trucks;
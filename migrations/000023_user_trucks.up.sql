@@ -0,0 +1,17 @@
+-- Машины, закреплённые за пользователем. Пользователь без права
+-- cargo:view_all видит только грузы этих машин.
+CREATE TABLE user_trucks (
+  user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  truck_id   UUID NOT NULL REFERENCES trucks(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, truck_id)
+);
+
+CREATE INDEX idx_user_trucks_truck_id ON user_trucks (truck_id);
+
+INSERT INTO permissions (name, description) VALUES
+  ('cargo:view_all', 'Просмотр грузов всех машин, а не только закреплённых');
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('EDITOR', 'cargo:view_all'),
+  ('SUPERADMIN', 'cargo:view_all');
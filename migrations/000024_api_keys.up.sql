@@ -0,0 +1,21 @@
+-- Ключи API для интеграций (бухгалтерия, складские скрипты). Хранится
+-- только sha256 ключа, сам ключ показывается один раз при создании.
+-- Права ключа задаются при создании и не зависят от ролей.
+CREATE TABLE api_keys (
+  id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name         TEXT NOT NULL,
+  prefix       TEXT NOT NULL,
+  key_hash     TEXT NOT NULL UNIQUE,
+  permissions  TEXT[] NOT NULL DEFAULT '{}',
+  expires_at   TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  created_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at   TIMESTAMPTZ
+);
+
+INSERT INTO permissions (name, description) VALUES
+  ('apikey:manage', 'Создание и отзыв ключей API');
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('SUPERADMIN', 'apikey:manage');
@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name = 'user:impersonate';
+
+DROP TABLE IF EXISTS impersonation_requests;
+DROP TABLE IF EXISTS impersonations;
+DROP FUNCTION IF EXISTS impersonations_append_only();
@@ -0,0 +1,46 @@
+-- Входы суперадминистратора от имени пользователя и запросы, сделанные
+-- в это время. Без внешних ключей: журнал должен переживать удаление
+-- пользователей.
+CREATE TABLE impersonations (
+  id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_id   UUID NOT NULL,
+  user_id    UUID NOT NULL,
+  reason     TEXT NOT NULL DEFAULT '',
+  ip         TEXT NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX ON impersonations (created_at DESC);
+CREATE INDEX ON impersonations (user_id, created_at DESC);
+
+CREATE TABLE impersonation_requests (
+  id               BIGSERIAL PRIMARY KEY,
+  impersonation_id UUID NOT NULL REFERENCES impersonations(id),
+  method           TEXT NOT NULL,
+  path             TEXT NOT NULL,
+  status           INT NOT NULL,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX ON impersonation_requests (impersonation_id, id);
+
+-- журнал только дополняется
+CREATE FUNCTION impersonations_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER impersonations_append_only
+  BEFORE UPDATE OR DELETE ON impersonations
+  FOR EACH ROW EXECUTE FUNCTION impersonations_append_only();
+
+CREATE TRIGGER impersonation_requests_append_only
+  BEFORE UPDATE OR DELETE ON impersonation_requests
+  FOR EACH ROW EXECUTE FUNCTION impersonations_append_only();
+
+INSERT INTO permissions (name, description) VALUES
+  ('user:impersonate', 'Вход от имени пользователя и просмотр журнала таких входов');
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('SUPERADMIN', 'user:impersonate');
@@ -2,11 +2,54 @@ package pkg
 
 import (
 	"fmt"
+	"math"
 	"net/smtp"
 	"test-project/config"
+	"time"
 )
 
-func SendEmail(to, inviteLink string) error {
+func SendEmail(to, inviteLink string, ttl time.Duration) error {
+	subject := "Приглашение на регистрацию"
+
+	htmlBody := `<p>Вас приглашают зарегистрироваться.</p><p><a href="` + inviteLink + `">Нажмите здесь</a>, чтобы пройти регистрацию. Ссылка действует ` + FormatTTL(ttl) + `</p>`
+
+	return send(to, subject, htmlBody)
+}
+
+// SendPasswordReset отправляет ссылку на сброс пароля.
+func SendPasswordReset(to, resetLink string, ttl time.Duration) error {
+	subject := "Сброс пароля"
+
+	htmlBody := `<p>Кто-то запросил сброс пароля для вашей учётной записи.</p><p><a href="` + resetLink + `">Нажмите здесь</a>, чтобы задать новый пароль. Ссылка одноразовая, время жизни ` +
+		FormatTTL(ttl) + `</p><p>Если это были не вы, просто проигнорируйте письмо.</p>`
+
+	return send(to, subject, htmlBody)
+}
+
+// SendMagicLink отправляет ссылку для входа без пароля.
+func SendMagicLink(to, loginLink string, ttl time.Duration) error {
+	subject := "Вход в систему"
+
+	htmlBody := `<p>Кто-то запросил вход в вашу учётную запись по ссылке.</p><p><a href="` + loginLink + `">Нажмите здесь</a>, чтобы войти. Ссылка одноразовая, работает только в браузере, где её запросили, время жизни ` +
+		FormatTTL(ttl) + `</p><p>Если это были не вы, просто проигнорируйте письмо.</p>`
+
+	return send(to, subject, htmlBody)
+}
+
+// FormatTTL записывает срок действия ссылки для писем и ответов API:
+// целые дни, часы или минуты.
+func FormatTTL(ttl time.Duration) string {
+	switch {
+	case ttl >= 24*time.Hour && ttl%(24*time.Hour) == 0:
+		return fmt.Sprintf("%d дн.", int(ttl.Hours()/24))
+	case ttl >= time.Hour && ttl%time.Hour == 0:
+		return fmt.Sprintf("%d ч", int(ttl.Hours()))
+	default:
+		return fmt.Sprintf("%d мин.", int(math.Ceil(ttl.Minutes())))
+	}
+}
+
+func send(to, subject, htmlBody string) error {
 	// Настройки SMTP сервера
 	smtpHost := config.Envs.SMTP_HOST
 	smtpPort := config.Envs.SMTP_PORT
@@ -16,10 +59,6 @@ func SendEmail(to, inviteLink string) error {
 	// Адрес отправителя
 	from := username
 
-	subject := "Приглашение на регистрацию"
-
-	htmlBody := `<p>Вас приглашают зарегистрироваться.</p><p><a href="` + inviteLink + `">Нажмите здесь</a>, чтобы пройти регистрацию. Время жизни 5 минут.</p>`
-
 	message := []byte(fmt.Sprintf(
 		"Subject: %s\r\n"+
 			"MIME-Version: 1.0\r\n"+
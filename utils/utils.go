@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"test-project/config"
 	"time"
 
 	"github.com/go-playground/form"
@@ -60,9 +63,54 @@ func ParseNumber(s string) (int, error) {
 	return strconv.Atoi(s)
 }
 
+// ClientIP возвращает адрес клиента. X-Forwarded-For и X-Real-IP учитываются,
+// только если запрос пришёл от доверенного прокси (TRUSTED_PROXIES).
+func ClientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if !trustedProxy(remote) {
+		return remote
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		// каждый прокси дописывает адрес справа: первый справа адрес не
+		// нашего прокси и есть клиент, всё левее мог подставить он сам
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if i == 0 || !trustedProxy(ip) {
+				return ip
+			}
+		}
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	return remote
+}
+
+func trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range config.Envs.TRUSTED_PROXIES {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Просроченные приглашения какое-то время остаются в списке (их можно
+// отправить повторно), затем удаляются.
 const deleteExpiredInvitationsQuery = `
 	DELETE FROM invitations
-	WHERE "createdAt" + INTERVAL '5 minutes' < NOW()
+	WHERE NOT used
+	  AND expires_at + INTERVAL '30 days' < NOW()
 `
 
 func StartInvitationCleaner(db *pgxpool.Pool, logger *zap.Logger) {